@@ -0,0 +1,84 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// APITokensInterface api tokens interface
+type APITokensInterface interface {
+	GetMultiple() (result []*models.APIToken, err error)
+	Create(token *models.APIToken) (returnedToken *models.APIToken, err error)
+	Revoke(tokenID int) (returnedToken *models.APIToken, err error)
+}
+
+type apiTokens struct {
+	client *Client
+}
+
+// GetMultiple gets all of the api tokens
+func (t *apiTokens) GetMultiple() (result []*models.APIToken, err error) {
+	req, err := t.client.buildRequest("GET", "/api/v1/tokens", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result = []*models.APIToken{}
+
+	resp, err := t.client.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Create creates an api token, the returned one holds the token itself
+func (t *apiTokens) Create(token *models.APIToken) (returnedToken *models.APIToken, err error) {
+	req, err := t.client.buildRequest("POST", "/api/v1/tokens", token)
+	if err != nil {
+		return nil, err
+	}
+
+	returnedToken = &models.APIToken{}
+
+	resp, err := t.client.do(req, returnedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return returnedToken, nil
+}
+
+// Revoke revokes an api token
+func (t *apiTokens) Revoke(tokenID int) (returnedToken *models.APIToken, err error) {
+	req, err := t.client.buildRequest("DELETE", fmt.Sprintf("/api/v1/tokens/%d", tokenID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	returnedToken = &models.APIToken{}
+
+	resp, err := t.client.do(req, returnedToken)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return returnedToken, nil
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// AuditInterface audit log interface
+type AuditInterface interface {
+	GetMultiple(filter models.AuditFilter) (result []*models.AuditEntry, err error)
+	Get(entryID int) (result *models.AuditEntry, err error)
+}
+
+type audit struct {
+	client *Client
+}
+
+// GetMultiple gets the entries of the audit log passing the filter, newest first
+func (a *audit) GetMultiple(filter models.AuditFilter) (result []*models.AuditEntry, err error) {
+	query := url.Values{}
+
+	if filter.Actor != "" {
+		query.Set("actor", filter.Actor)
+	}
+
+	if filter.Action != "" {
+		query.Set("action", filter.Action)
+	}
+
+	if filter.TargetType != "" {
+		query.Set("targetType", filter.TargetType)
+	}
+
+	if filter.TargetID != 0 {
+		query.Set("targetId", strconv.Itoa(filter.TargetID))
+	}
+
+	if !filter.From.IsZero() {
+		query.Set("from", filter.From.Format(time.RFC3339))
+	}
+
+	if !filter.To.IsZero() {
+		query.Set("to", filter.To.Format(time.RFC3339))
+	}
+
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+
+	req, err := a.client.buildRequest("GET", "/api/v1/audit?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result = []*models.AuditEntry{}
+
+	resp, err := a.client.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Get gets one entry of the audit log
+func (a *audit) Get(entryID int) (result *models.AuditEntry, err error) {
+	req, err := a.client.buildRequest("GET", fmt.Sprintf("/api/v1/audit/%d", entryID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result = &models.AuditEntry{}
+
+	resp, err := a.client.do(req, result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -89,12 +89,32 @@ func (c *Client) Incidents() IncidentsInterface {
 	return &incidents{client: c}
 }
 
+// IncidentTemplates incident template methods
+func (c *Client) IncidentTemplates() IncidentTemplatesInterface {
+	return &incidentTemplates{client: c}
+}
+
 // ScheduledMaintenance maintenance methods
 func (c *Client) ScheduledMaintenance() ScheduledMaintenanceInterface {
 	return &scheduledMaintenance{client: c}
 }
 
+// RecurringMaintenance recurring maintenance methods
+func (c *Client) RecurringMaintenance() RecurringMaintenanceInterface {
+	return &recurringMaintenance{client: c}
+}
+
 // Services service methods
 func (c *Client) Services() ServicesInterface {
 	return &services{client: c}
 }
+
+// APITokens api token methods
+func (c *Client) APITokens() APITokensInterface {
+	return &apiTokens{client: c}
+}
+
+// Audit audit log methods
+func (c *Client) Audit() AuditInterface {
+	return &audit{client: c}
+}
@@ -3,6 +3,8 @@ package client
 import (
 	"errors"
 	"fmt"
+
+	"github.com/RocketChat/statuscentral/models"
 )
 
 // ErrInvalidID is for when an id is provided but it is invalid
@@ -13,6 +15,10 @@ type ErrorResponse struct { //nolint:errname // Tech debt, rename errors at a la
 	Code      string `json:"code"`
 	ErrorCode string `json:"error"`
 	RequestID string `json:"requestID"`
+	Details   string `json:"details"`
+
+	// Conflicts are set when scheduled maintenance wasn't created or moved because it conflicts
+	Conflicts []models.MaintenanceConflict `json:"conflicts"`
 }
 
 func (e *ErrorResponse) Error() string {
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// IncidentTemplatesInterface incident templates interface
+type IncidentTemplatesInterface interface {
+	GetMultiple() (result []*models.IncidentTemplate, err error)
+	Create(incidentTemplate *models.IncidentTemplate) (returnedIncidentTemplate *models.IncidentTemplate, err error)
+	Delete(id int) error
+	Render(id int, variables map[string]string) (incident *models.Incident, err error)
+}
+
+type incidentTemplates struct {
+	client *Client
+}
+
+// GetMultiple gets all of the incident templates
+func (t *incidentTemplates) GetMultiple() (result []*models.IncidentTemplate, err error) {
+	req, err := t.client.buildRequest("GET", "/api/v1/incident-templates", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result = []*models.IncidentTemplate{}
+
+	resp, err := t.client.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Create creates an incident template
+func (t *incidentTemplates) Create(incidentTemplate *models.IncidentTemplate) (returnedIncidentTemplate *models.IncidentTemplate, err error) {
+	req, err := t.client.buildRequest("POST", "/api/v1/incident-templates", incidentTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	returnedIncidentTemplate = &models.IncidentTemplate{}
+
+	resp, err := t.client.do(req, returnedIncidentTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return returnedIncidentTemplate, nil
+}
+
+// Delete deletes an incident template
+func (t *incidentTemplates) Delete(id int) error {
+	req, err := t.client.buildRequest("DELETE", fmt.Sprintf("/api/v1/incident-templates/%d", id), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := t.client.do(req, nil)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// Render renders an incident template with the variables, giving the incident to create
+func (t *incidentTemplates) Render(id int, variables map[string]string) (incident *models.Incident, err error) {
+	req, err := t.client.buildRequest("POST", fmt.Sprintf("/api/v1/incident-templates/%d/render", id), &models.IncidentTemplateRender{Variables: variables})
+	if err != nil {
+		return nil, err
+	}
+
+	incident = &models.Incident{}
+
+	resp, err := t.client.do(req, incident)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
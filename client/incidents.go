@@ -2,6 +2,7 @@ package client
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/RocketChat/statuscentral/models"
 )
@@ -9,10 +10,16 @@ import (
 // IncidentsInterface incidents interface
 type IncidentsInterface interface {
 	Create(incident *models.Incident) (returnedIncident *models.Incident, err error)
+	Patch(incidentID int, incident *models.Incident) (returnedIncident *models.Incident, err error)
 	Get(id int) (incident *models.Incident, err error)
 	GetMultiple(latestOnly bool) (result []*models.Incident, err error)
 	CreateStatusUpdate(incidentID int, statusUpdate *models.StatusUpdate) (returnedIncident *models.Incident, err error)
+	EditStatusUpdate(incidentID int, updateID int, statusUpdate *models.StatusUpdate) (returnedStatusUpdate *models.StatusUpdate, err error)
 	Delete(incidentID int) error
+	SavePostmortem(incidentID int, postmortem *models.Postmortem) (returnedPostmortem *models.Postmortem, err error)
+	PublishPostmortem(incidentID int) (returnedPostmortem *models.Postmortem, err error)
+	UnpublishPostmortem(incidentID int) (returnedPostmortem *models.Postmortem, err error)
+	DeletePostmortem(incidentID int) error
 }
 
 type incidents struct {
@@ -93,6 +100,28 @@ func (i *incidents) Create(incident *models.Incident) (returnedIncident *models.
 	return returnedIncident, nil
 }
 
+// Patch changes the fields of the incident which are set
+func (i *incidents) Patch(incidentID int, incident *models.Incident) (returnedIncident *models.Incident, err error) {
+	req, err := i.client.buildRequest("PATCH", fmt.Sprintf("/api/v1/incidents/%d", incidentID), incident)
+	if err != nil {
+		return nil, err
+	}
+
+	returnedIncident = &models.Incident{}
+
+	resp, err := i.client.do(req, returnedIncident)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return returnedIncident, nil
+}
+
 // CreateStatusUpdate Creates a status update for an incident
 func (i *incidents) CreateStatusUpdate(incidentID int, statusUpdate *models.StatusUpdate) (returnedIncident *models.Incident, err error) {
 	req, err := i.client.buildRequest("POST", fmt.Sprintf("/api/v1/incidents/%d/updates", incidentID), statusUpdate)
@@ -115,6 +144,28 @@ func (i *incidents) CreateStatusUpdate(incidentID int, statusUpdate *models.Stat
 	return returnedIncident, nil
 }
 
+// EditStatusUpdate edits a status update of an incident
+func (i *incidents) EditStatusUpdate(incidentID int, updateID int, statusUpdate *models.StatusUpdate) (returnedStatusUpdate *models.StatusUpdate, err error) {
+	req, err := i.client.buildRequest("PATCH", fmt.Sprintf("/api/v1/incidents/%d/updates/%d", incidentID, updateID), statusUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	returnedStatusUpdate = &models.StatusUpdate{}
+
+	resp, err := i.client.do(req, returnedStatusUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return returnedStatusUpdate, nil
+}
+
 // Delete deletes an incident
 func (i *incidents) Delete(incidentID int) error {
 	req, err := i.client.buildRequest(
@@ -136,3 +187,64 @@ func (i *incidents) Delete(incidentID int) error {
 
 	return err
 }
+
+// SavePostmortem creates or replaces the postmortem of a resolved incident
+func (i *incidents) SavePostmortem(incidentID int, postmortem *models.Postmortem) (returnedPostmortem *models.Postmortem, err error) {
+	req, err := i.client.buildRequest("POST", fmt.Sprintf("/api/v1/incidents/%d/postmortem", incidentID), postmortem)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.doPostmortem(req)
+}
+
+// PublishPostmortem shows the postmortem on the page of the incident
+func (i *incidents) PublishPostmortem(incidentID int) (returnedPostmortem *models.Postmortem, err error) {
+	req, err := i.client.buildRequest("POST", fmt.Sprintf("/api/v1/incidents/%d/postmortem/publish", incidentID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.doPostmortem(req)
+}
+
+// UnpublishPostmortem makes the postmortem of the incident a draft again
+func (i *incidents) UnpublishPostmortem(incidentID int) (returnedPostmortem *models.Postmortem, err error) {
+	req, err := i.client.buildRequest("POST", fmt.Sprintf("/api/v1/incidents/%d/postmortem/unpublish", incidentID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.doPostmortem(req)
+}
+
+// DeletePostmortem deletes the postmortem of an incident
+func (i *incidents) DeletePostmortem(incidentID int) error {
+	req, err := i.client.buildRequest("DELETE", fmt.Sprintf("/api/v1/incidents/%d/postmortem", incidentID), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := i.client.do(req, nil)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+func (i *incidents) doPostmortem(req *http.Request) (returnedPostmortem *models.Postmortem, err error) {
+	returnedPostmortem = &models.Postmortem{}
+
+	resp, err := i.client.do(req, returnedPostmortem)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return returnedPostmortem, nil
+}
@@ -29,6 +29,14 @@ type ClientConfig struct {
 	PKCE         bool
 	Scope        string
 	RedirectURI  string
+	Endpoints    Endpoints
+}
+
+// Endpoints of the authorization server, the ones left empty are relative to the url of the config
+type Endpoints struct {
+	Authorize string `json:"authorize,omitempty"`
+	Token     string `json:"token,omitempty"`
+	Revoke    string `json:"revoke,omitempty"`
 }
 
 type ClientSession struct {
@@ -76,9 +84,55 @@ func New(config ClientConfig) (*OAuthClient, error) {
 		return nil, errors.New("ClientSecret must be set unless using PKCE")
 	}
 
+	if client.config.Endpoints.Authorize == "" {
+		client.config.Endpoints.Authorize = client.config.URL + "/authorize"
+	}
+
+	if client.config.Endpoints.Token == "" {
+		client.config.Endpoints.Token = client.config.URL + "/api/oauth/token"
+	}
+
+	if client.config.Endpoints.Revoke == "" {
+		client.config.Endpoints.Revoke = client.config.URL + "/api/oauth/revoke"
+	}
+
 	return client, nil
 }
 
+// DiscoverEndpoints gets the endpoints from the openid configuration of the issuer
+func DiscoverEndpoints(issuer string) (Endpoints, error) {
+	response, err := http.Get(strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration")
+	if err != nil {
+		return Endpoints{}, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return Endpoints{}, fmt.Errorf("openid configuration returned status code %d", response.StatusCode)
+	}
+
+	var discovery struct {
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		RevocationEndpoint    string `json:"revocation_endpoint"`
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(&discovery); err != nil {
+		return Endpoints{}, err
+	}
+
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" {
+		return Endpoints{}, errors.New("openid configuration is missing the authorization or token endpoint")
+	}
+
+	return Endpoints{
+		Authorize: discovery.AuthorizationEndpoint,
+		Token:     discovery.TokenEndpoint,
+		Revoke:    discovery.RevocationEndpoint,
+	}, nil
+}
+
 // NewSession initializes a new session
 func (o *OAuthClient) NewSession() error {
 	state, err := newUUID()
@@ -123,23 +177,27 @@ func (o *OAuthClient) RestoreSession(session ClientSession) error {
 
 // BuildAuthorizeURL builds the authorize url the user would visit to grant authorization
 func (o *OAuthClient) BuildAuthorizeURL() string {
-	// Make spaces link safe
-	scope := strings.ReplaceAll(o.config.Scope, " ", "%20")
-
-	authorizeURL := o.config.URL + "/authorize" +
-		"?client_id=" + o.config.ClientID +
-		"&response_type=code" +
-		"&access_type=offline" + // Actually not sure what cases this needs passed?
-		"&redirect_uri=" + o.config.RedirectURI +
-		"&scope=" + scope +
-		"&state=" + o.session.state
+	query := url.Values{
+		"client_id":     {o.config.ClientID},
+		"response_type": {"code"},
+		"access_type":   {"offline"}, // Actually not sure what cases this needs passed?
+		"redirect_uri":  {o.config.RedirectURI},
+		"scope":         {o.config.Scope},
+		"state":         {o.session.state},
+	}
 
 	if o.config.PKCE {
-		authorizeURL = authorizeURL + "&code_challenge_method=S256" +
-			"&code_challenge=" + o.session.codeChallenge
+		query.Set("code_challenge_method", "S256")
+		query.Set("code_challenge", o.session.codeChallenge)
 	}
 
-	return authorizeURL
+	separator := "?"
+	if strings.Contains(o.config.Endpoints.Authorize, "?") {
+		separator = "&"
+	}
+
+	// Make spaces link safe
+	return o.config.Endpoints.Authorize + separator + strings.ReplaceAll(query.Encode(), "+", "%20")
 }
 
 // CompleteAuthorization exchanges authorization code for access token and refresh (if offline_access scope was used)
@@ -156,7 +214,7 @@ func (o *OAuthClient) CompleteAuthorization(code string) error {
 		formBody.Add("code_verifier", o.session.codeVerifier)
 	}
 
-	response, err := http.PostForm(o.config.URL+"/api/oauth/token", formBody)
+	response, err := http.PostForm(o.config.Endpoints.Token, formBody)
 
 	if err != nil {
 		return err
@@ -169,7 +227,7 @@ func (o *OAuthClient) CompleteAuthorization(code string) error {
 		return err
 	}
 
-	if response.StatusCode != 201 {
+	if !isSuccessStatus(response.StatusCode) {
 		return errors.New(string(body))
 	}
 
@@ -198,7 +256,7 @@ func (o *OAuthClient) refreshAccessToken(scope string, store bool) (accessToken
 		return "", errors.New("No refresh token available to use for refresh")
 	}
 
-	response, err := http.PostForm(o.config.URL+"/api/oauth/token", url.Values{
+	response, err := http.PostForm(o.config.Endpoints.Token, url.Values{
 		"grant_type":    {"refresh_token"},
 		"redirect_uri":  {o.config.RedirectURI},
 		"client_id":     {o.config.ClientID},
@@ -218,7 +276,7 @@ func (o *OAuthClient) refreshAccessToken(scope string, store bool) (accessToken
 		return "", err
 	}
 
-	if response.StatusCode != 201 {
+	if !isSuccessStatus(response.StatusCode) {
 		return "", errors.New(string(body))
 	}
 
@@ -231,6 +289,11 @@ func (o *OAuthClient) refreshAccessToken(scope string, store bool) (accessToken
 	if store {
 		o.session.AccessToken = token.AccessToken
 
+		// Servers rotating the refresh tokens give a new one with every refresh
+		if token.RefreshToken != "" {
+			o.session.RefreshToken = token.RefreshToken
+		}
+
 		// Add expires seconds to current time - minus 10 seconds so we never try to use an almost expired access token
 		o.session.ExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn-10) * time.Second)
 	}
@@ -273,7 +336,7 @@ func (o *OAuthClient) Revoke() error {
 		return nil
 	}
 
-	response, err := http.PostForm(o.config.URL+"/api/oauth/revoke", url.Values{
+	response, err := http.PostForm(o.config.Endpoints.Revoke, url.Values{
 		"client_id":       {o.config.ClientID},
 		"client_secret":   {o.config.ClientSecret},
 		"token":           {o.session.RefreshToken},
@@ -316,12 +379,19 @@ func (o *OAuthClient) HasActiveSession() bool {
 	return true
 }
 
+func isSuccessStatus(statusCode int) bool {
+	return statusCode == http.StatusOK || statusCode == http.StatusCreated
+}
+
 func generateCodeChallenge() (codeVerifier string, codeChallenge string, err error) {
-	codeVerifier, err = newUUID()
-	if err != nil {
+	// The verifier has to be at least 43 characters long, which 32 random bytes are once encoded
+	verifier := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, verifier); err != nil {
 		return "", "", err
 	}
 
+	codeVerifier = base64.RawURLEncoding.EncodeToString(verifier)
+
 	sha256Hash := sha256.New()
 	if _, err := sha256Hash.Write([]byte(codeVerifier)); err != nil {
 		return "", "", err
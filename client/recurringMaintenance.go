@@ -0,0 +1,123 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// RecurringMaintenanceInterface recurring maintenance interface
+type RecurringMaintenanceInterface interface {
+	GetMultiple() (result []*models.RecurringMaintenance, err error)
+	Create(recurringMaintenance *models.RecurringMaintenance) (returnedRecurringMaintenance *models.RecurringMaintenance, err error)
+	Delete(id int) error
+	GetOccurrences(id int, count int) (result []models.RecurringMaintenanceOccurrence, err error)
+	SetException(id int, exception *models.RecurringMaintenanceException) (returnedRecurringMaintenance *models.RecurringMaintenance, err error)
+}
+
+type recurringMaintenance struct {
+	client *Client
+}
+
+// GetMultiple gets all of the recurring maintenance
+func (r *recurringMaintenance) GetMultiple() (result []*models.RecurringMaintenance, err error) {
+	req, err := r.client.buildRequest("GET", "/api/v1/recurring-maintenance", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result = []*models.RecurringMaintenance{}
+
+	resp, err := r.client.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Create creates a recurring maintenance
+func (r *recurringMaintenance) Create(recurringMaintenance *models.RecurringMaintenance) (returnedRecurringMaintenance *models.RecurringMaintenance, err error) {
+	req, err := r.client.buildRequest("POST", "/api/v1/recurring-maintenance", recurringMaintenance)
+	if err != nil {
+		return nil, err
+	}
+
+	returnedRecurringMaintenance = &models.RecurringMaintenance{}
+
+	resp, err := r.client.do(req, returnedRecurringMaintenance)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return returnedRecurringMaintenance, nil
+}
+
+// Delete deletes a recurring maintenance, the occurrences already scheduled stay
+func (r *recurringMaintenance) Delete(id int) error {
+	req, err := r.client.buildRequest("DELETE", fmt.Sprintf("/api/v1/recurring-maintenance/%d", id), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := r.client.do(req, nil)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// GetOccurrences gets the next occurrences of a recurring maintenance
+func (r *recurringMaintenance) GetOccurrences(id int, count int) (result []models.RecurringMaintenanceOccurrence, err error) {
+	req, err := r.client.buildRequest("GET", fmt.Sprintf("/api/v1/recurring-maintenance/%d/occurrences?count=%d", id, count), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result = []models.RecurringMaintenanceOccurrence{}
+
+	resp, err := r.client.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SetException skips or changes a single occurrence of a recurring maintenance
+func (r *recurringMaintenance) SetException(id int, exception *models.RecurringMaintenanceException) (returnedRecurringMaintenance *models.RecurringMaintenance, err error) {
+	req, err := r.client.buildRequest("POST", fmt.Sprintf("/api/v1/recurring-maintenance/%d/exceptions", id), exception)
+	if err != nil {
+		return nil, err
+	}
+
+	returnedRecurringMaintenance = &models.RecurringMaintenance{}
+
+	resp, err := r.client.do(req, returnedRecurringMaintenance)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return returnedRecurringMaintenance, nil
+}
@@ -2,18 +2,23 @@ package client
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/RocketChat/statuscentral/models"
 )
 
 // ScheduledMaintenanceInterface ScheduledMaintenance interface
 type ScheduledMaintenanceInterface interface {
-	Create(scheduledMaintenance *models.ScheduledMaintenance) (returnedScheduledMaintenance *models.ScheduledMaintenance, err error)
-	Patch(maintenanceID int, scheduledMaintenance *models.ScheduledMaintenance) (returnedScheduledMaintenance *models.ScheduledMaintenance, err error)
+	Create(scheduledMaintenance *models.ScheduledMaintenance, force bool) (returnedScheduledMaintenance *models.ScheduledMaintenance, err error)
+	Patch(maintenanceID int, scheduledMaintenance *models.ScheduledMaintenance, force bool) (returnedScheduledMaintenance *models.ScheduledMaintenance, err error)
 	Get(id int) (scheduledMaintenance *models.ScheduledMaintenance, err error)
 	GetMultiple(latestOnly bool) (result []*models.ScheduledMaintenance, err error)
 	CreateStatusUpdate(maintenanceID int, statusUpdate *models.StatusUpdate) (returnedScheduledMaintenance *models.ScheduledMaintenance, err error)
+	EditStatusUpdate(maintenanceID int, updateID int, statusUpdate *models.StatusUpdate) (returnedStatusUpdate *models.StatusUpdate, err error)
 	Delete(maintenanceID int) error
+	Extend(maintenanceID int, plannedEnd time.Time, message string) (returnedScheduledMaintenance *models.ScheduledMaintenance, err error)
+	Start(maintenanceID int, message string) (returnedScheduledMaintenance *models.ScheduledMaintenance, err error)
+	Cancel(maintenanceID int, message string) (returnedScheduledMaintenance *models.ScheduledMaintenance, err error)
 }
 
 type scheduledMaintenance struct {
@@ -72,9 +77,10 @@ func (i *scheduledMaintenance) GetMultiple(latestOnly bool) (result []*models.Sc
 	return result, nil
 }
 
-// Create creates a scheduled maintenance
-func (i *scheduledMaintenance) Create(scheduledMaintenance *models.ScheduledMaintenance) (returnedScheduledMaintenance *models.ScheduledMaintenance, err error) {
-	req, err := i.client.buildRequest("POST", "/api/v1/scheduled-maintenance", scheduledMaintenance)
+// Create creates a scheduled maintenance, when it conflicts with other maintenance or open incidents it's only
+// created if forced and the *ErrorResponse lists the conflicts
+func (i *scheduledMaintenance) Create(scheduledMaintenance *models.ScheduledMaintenance, force bool) (returnedScheduledMaintenance *models.ScheduledMaintenance, err error) {
+	req, err := i.client.buildRequest("POST", fmt.Sprintf("/api/v1/scheduled-maintenance?force=%v", force), scheduledMaintenance)
 	if err != nil {
 		return nil, err
 	}
@@ -94,9 +100,9 @@ func (i *scheduledMaintenance) Create(scheduledMaintenance *models.ScheduledMain
 	return returnedScheduledMaintenance, nil
 }
 
-// Patches an existing scheduled maintenance
-func (i *scheduledMaintenance) Patch(maintenanceID int, scheduledMaintenance *models.ScheduledMaintenance) (returnedScheduledMaintenance *models.ScheduledMaintenance, err error) {
-	req, err := i.client.buildRequest("PATCH", fmt.Sprintf("/api/v1/scheduled-maintenance/%d", maintenanceID), scheduledMaintenance)
+// Patches an existing scheduled maintenance, moving it onto other maintenance or open incidents is only done if forced
+func (i *scheduledMaintenance) Patch(maintenanceID int, scheduledMaintenance *models.ScheduledMaintenance, force bool) (returnedScheduledMaintenance *models.ScheduledMaintenance, err error) {
+	req, err := i.client.buildRequest("PATCH", fmt.Sprintf("/api/v1/scheduled-maintenance/%d?force=%v", maintenanceID, force), scheduledMaintenance)
 	if err != nil {
 		return nil, err
 	}
@@ -138,6 +144,28 @@ func (i *scheduledMaintenance) CreateStatusUpdate(maintenanceID int, statusUpdat
 	return returnedScheduledMaintenance, nil
 }
 
+// EditStatusUpdate edits a status update of a scheduled maintenance
+func (i *scheduledMaintenance) EditStatusUpdate(maintenanceID int, updateID int, statusUpdate *models.StatusUpdate) (returnedStatusUpdate *models.StatusUpdate, err error) {
+	req, err := i.client.buildRequest("PATCH", fmt.Sprintf("/api/v1/scheduled-maintenance/%d/updates/%d", maintenanceID, updateID), statusUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	returnedStatusUpdate = &models.StatusUpdate{}
+
+	resp, err := i.client.do(req, returnedStatusUpdate)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return returnedStatusUpdate, nil
+}
+
 // Delete deletes an incident
 func (i *scheduledMaintenance) Delete(maintenanceID int) error {
 	req, err := i.client.buildRequest(
@@ -159,3 +187,45 @@ func (i *scheduledMaintenance) Delete(maintenanceID int) error {
 
 	return err
 }
+
+// scheduledMaintenanceAction is the body of extending, starting early or cancelling a scheduled maintenance
+type scheduledMaintenanceAction struct {
+	PlannedEnd time.Time `json:"plannedEnd,omitempty"`
+	Message    string    `json:"message,omitempty"`
+}
+
+// Extend pushes back the planned end of a scheduled maintenance in progress
+func (i *scheduledMaintenance) Extend(maintenanceID int, plannedEnd time.Time, message string) (returnedScheduledMaintenance *models.ScheduledMaintenance, err error) {
+	return i.action(maintenanceID, "extend", scheduledMaintenanceAction{PlannedEnd: plannedEnd, Message: message})
+}
+
+// Start starts a scheduled maintenance before its planned start
+func (i *scheduledMaintenance) Start(maintenanceID int, message string) (returnedScheduledMaintenance *models.ScheduledMaintenance, err error) {
+	return i.action(maintenanceID, "start", scheduledMaintenanceAction{Message: message})
+}
+
+// Cancel cancels a scheduled maintenance, unlike deleting it stays on the status page
+func (i *scheduledMaintenance) Cancel(maintenanceID int, message string) (returnedScheduledMaintenance *models.ScheduledMaintenance, err error) {
+	return i.action(maintenanceID, "cancel", scheduledMaintenanceAction{Message: message})
+}
+
+func (i *scheduledMaintenance) action(maintenanceID int, action string, body scheduledMaintenanceAction) (returnedScheduledMaintenance *models.ScheduledMaintenance, err error) {
+	req, err := i.client.buildRequest("POST", fmt.Sprintf("/api/v1/scheduled-maintenance/%d/%s", maintenanceID, action), body)
+	if err != nil {
+		return nil, err
+	}
+
+	returnedScheduledMaintenance = &models.ScheduledMaintenance{}
+
+	resp, err := i.client.do(req, returnedScheduledMaintenance)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return returnedScheduledMaintenance, nil
+}
@@ -1,12 +1,15 @@
 package client
 
 import (
+	"fmt"
+
 	"github.com/RocketChat/statuscentral/models"
 )
 
 // ServicesInterface services interface
 type ServicesInterface interface {
 	GetMultiple() (result []*models.Service, err error)
+	GetHistory(serviceID int, limit int) (result []*models.StatusChange, err error)
 }
 
 type services struct {
@@ -34,3 +37,25 @@ func (s *services) GetMultiple() (result []*models.Service, err error) {
 
 	return result, nil
 }
+
+// GetHistory gets the latest status changes of the service and its regions, newest first
+func (s *services) GetHistory(serviceID int, limit int) (result []*models.StatusChange, err error) {
+	req, err := s.client.buildRequest("GET", fmt.Sprintf("/api/v1/services/%d/history?limit=%d", serviceID, limit), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result = []*models.StatusChange{}
+
+	resp, err := s.client.do(req, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,34 @@
+package audit
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var SubCommands []*cobra.Command
+
+var AuditCmd = &cobra.Command{
+	Use:     "audit",
+	Short:   "StatusCentral audit log",
+	Example: "statusctl audit [command]",
+	Args: func(c *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("%v requires arguments", c.UseLine())
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	listCmd.Flags().StringVarP(&filterActor, "actor", "a", "", "Only entries of this actor, like token:3")
+	listCmd.Flags().StringVar(&filterAction, "action", "", "Only entries of this action, like incident.create")
+	listCmd.Flags().StringVarP(&filterTargetType, "target-type", "t", "", "Only entries for this type of record: service, region, incident, incident_template, scheduled_maintenance, recurring_maintenance, subscriber, webhook or token")
+	listCmd.Flags().IntVar(&filterTargetID, "target-id", 0, "Only entries for the record with this id")
+	listCmd.Flags().DurationVarP(&filterSince, "since", "s", 0, "Only entries in this past period, like 24h")
+	listCmd.Flags().IntVarP(&filterLimit, "limit", "l", 50, "Maximum number of entries")
+
+	SubCommands = append(SubCommands, listCmd, describeCmd)
+	AuditCmd.AddCommand(SubCommands...)
+}
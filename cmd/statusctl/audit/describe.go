@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RocketChat/statuscentral/cmd/statusctl/common"
+)
+
+var describeCmd = &cobra.Command{
+	Use:     "describe [id]",
+	Short:   "Show an audit log entry with the target before and after the change",
+	Example: "statusctl audit describe 12",
+	Args:    cobra.ExactArgs(1),
+	Run: func(c *cobra.Command, args []string) {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			panic(err)
+		}
+
+		cl := common.GetStatusCentralClient()
+
+		entry, err := cl.Audit().Get(id)
+		if err != nil {
+			panic(err)
+		}
+
+		fmt.Printf("ID:      %d\n", entry.ID)
+		fmt.Printf("Time:    %s\n", entry.Time.Format("Jan 02 2006 15:04:05 MST"))
+		fmt.Printf("Actor:   %s\n", entry.Actor)
+		fmt.Printf("Action:  %s\n", entry.Action)
+		fmt.Printf("Target:  %s %d\n", entry.TargetType, entry.TargetID)
+		fmt.Printf("Request: %s %s (%d)\n", entry.Method, entry.Path, entry.Status)
+
+		fmt.Printf("\nBefore:\n%s\n", indentSnapshot(entry.Before))
+		fmt.Printf("\nAfter:\n%s\n", indentSnapshot(entry.After))
+	},
+}
+
+func indentSnapshot(snapshot json.RawMessage) string {
+	if len(snapshot) == 0 {
+		return "  (none)"
+	}
+
+	var b bytes.Buffer
+	if err := json.Indent(&b, snapshot, "  ", "  "); err != nil {
+		return string(snapshot)
+	}
+
+	return "  " + b.String()
+}
@@ -0,0 +1,73 @@
+package audit
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/spf13/cobra"
+
+	"github.com/RocketChat/statuscentral/cmd/statusctl/common"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+var (
+	filterActor      string
+	filterAction     string
+	filterTargetType string
+	filterTargetID   int
+	filterSince      time.Duration
+	filterLimit      int
+)
+
+var listCmd = &cobra.Command{
+	Use: "list",
+	Aliases: []string{
+		"ls",
+	},
+	Short:   "List the audit log, newest first",
+	Example: "statusctl audit ls --target-type incident --since 24h",
+	Run: func(c *cobra.Command, args []string) {
+		filter := models.AuditFilter{
+			Actor:      filterActor,
+			Action:     filterAction,
+			TargetType: filterTargetType,
+			TargetID:   filterTargetID,
+			Limit:      filterLimit,
+		}
+
+		if filterSince > 0 {
+			filter.From = time.Now().Add(-filterSince)
+		}
+
+		cl := common.GetStatusCentralClient()
+
+		entries, err := cl.Audit().GetMultiple(filter)
+		if err != nil {
+			panic(err)
+		}
+
+		t := table.NewWriter()
+
+		t.Style().Options.DrawBorder = false
+		t.Style().Options.SeparateRows = false
+		t.Style().Options.SeparateColumns = false
+		t.Style().Options.SeparateHeader = false
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"ID", "Time", "Actor", "Action", "Target", "Request"})
+
+		for _, entry := range entries {
+			target := entry.TargetType
+			if entry.TargetID != 0 {
+				target += " #" + strconv.Itoa(entry.TargetID)
+			}
+
+			t.AppendRows([]table.Row{
+				{entry.ID, entry.Time.Format("Jan 02 2006 15:04:05"), entry.Actor, entry.Action, target, entry.Method + " " + entry.Path},
+			})
+		}
+
+		t.Render()
+	},
+}
@@ -10,13 +10,14 @@ import (
 
 var baseURL = ""
 var loginToken = ""
+var oauthSettings common.OAuthSettings
 
 var loginCmd = &cobra.Command{
 	Use:     "login",
 	Short:   "Login",
-	Example: "statusctl login",
+	Example: "statusctl login --host https://status.example.com --oauth-host https://sso.example.com/realms/ops --client-id statusctl",
 	Run: func(c *cobra.Command, args []string) {
-		if err := common.Login(baseURL, loginToken); err != nil {
+		if err := common.Login(baseURL, loginToken, oauthSettings); err != nil {
 			panic(err)
 		}
 
@@ -38,6 +39,9 @@ var logoutCmd = &cobra.Command{
 func init() {
 	loginCmd.Flags().StringVarP(&baseURL, "host", "", "", "status page host")
 	loginCmd.Flags().StringVarP(&loginToken, "token", "t", "", "auth token")
+	loginCmd.Flags().StringVarP(&oauthSettings.Host, "oauth-host", "", "", "oauth or openid issuer to login with, defaults to $STATUSCTL_OAUTH_HOST")
+	loginCmd.Flags().StringVarP(&oauthSettings.ClientID, "client-id", "", "", "oauth client id of statusctl, defaults to $STATUSCTL_OAUTH_CLIENT_ID")
+	loginCmd.Flags().StringVarP(&oauthSettings.Scopes, "scopes", "", "", "oauth scopes to request, defaults to $STATUSCTL_OAUTH_SCOPES or \"openid offline_access\"")
 	rootCmd.AddCommand(loginCmd)
 	rootCmd.AddCommand(logoutCmd)
 }
@@ -16,10 +16,43 @@ import (
 	"github.com/RocketChat/statuscentral/client/oauthclient"
 )
 
-const oauthHost string = ""
-const clientID string = ""
+// Environment variables the oauth settings are taken from when not passed as flags
+const (
+	oauthHostEnv     = "STATUSCTL_OAUTH_HOST"
+	oauthClientIDEnv = "STATUSCTL_OAUTH_CLIENT_ID"
+	oauthScopesEnv   = "STATUSCTL_OAUTH_SCOPES"
+)
+
+const defaultOAuthScopes = "openid offline_access"
+
+// OAuthSettings are the authorization server and client statusctl logs in with
+type OAuthSettings struct {
+	Host      string                `json:"host"`
+	ClientID  string                `json:"clientId"`
+	Scopes    string                `json:"scopes"`
+	Endpoints oauthclient.Endpoints `json:"endpoints"`
+}
+
+// WithDefaults fills the settings which weren't passed from the environment
+func (s OAuthSettings) WithDefaults() OAuthSettings {
+	if s.Host == "" {
+		s.Host = os.Getenv(oauthHostEnv)
+	}
+
+	if s.ClientID == "" {
+		s.ClientID = os.Getenv(oauthClientIDEnv)
+	}
 
-const scopes string = "offline_access workspace"
+	if s.Scopes == "" {
+		s.Scopes = os.Getenv(oauthScopesEnv)
+	}
+
+	if s.Scopes == "" {
+		s.Scopes = defaultOAuthScopes
+	}
+
+	return s
+}
 
 var oClient *oauthclient.OAuthClient
 
@@ -27,14 +60,23 @@ var debugMode = false
 
 var codeChan = make(chan string)
 
-func Login(baseURL, token string) error {
+func Login(baseURL, token string, oauth OAuthSettings) error {
 	state := State{}
+	oauth = oauth.WithDefaults()
 
 	if token != "" && baseURL != "" {
 		state.BaseURL = baseURL
 		state.LoginToken = token
-	} else if oauthHost != "" && clientID != "" {
-		if err := initOAuthClient(oauthHost); err != nil {
+	} else if oauth.Host != "" && oauth.ClientID != "" {
+		// Servers which aren't openid providers use the endpoints of the oauth client
+		endpoints, err := oauthclient.DiscoverEndpoints(oauth.Host)
+		if err != nil {
+			verboseLog("No openid configuration found, using the default endpoints:", err)
+		}
+
+		oauth.Endpoints = endpoints
+
+		if err := initOAuthClient(oauth); err != nil {
 			return err
 		}
 
@@ -47,7 +89,11 @@ func Login(baseURL, token string) error {
 			return err
 		}
 
+		state.BaseURL = baseURL
 		state.Session = s
+		state.OAuth = &oauth
+	} else {
+		return errors.New("either --host and --token, or the oauth host and client id are required to login")
 	}
 
 	if err := SaveState(state); err != nil {
@@ -58,12 +104,22 @@ func Login(baseURL, token string) error {
 }
 
 func Logout() error {
-	if err := initOAuthClient(oauthHost); err != nil {
+	state, err := LoadState()
+	if err != nil {
 		return err
 	}
 
-	if err := oClient.Revoke(); err != nil {
-		return err
+	if state.Session != nil && state.OAuth != nil {
+		if err := initOAuthClient(*state.OAuth); err != nil {
+			return err
+		}
+
+		if err := oClient.RestoreSession(*state.Session); err == nil {
+			// The local session is removed either way
+			if err := oClient.Revoke(); err != nil {
+				logger("Error while revoking the session:", err)
+			}
+		}
 	}
 
 	if err := DeleteState(); err != nil {
@@ -105,7 +161,11 @@ func getClient() (*client.Client, error) {
 	}
 
 	if state.Session != nil {
-		if err := initOAuthClient(oauthHost); err != nil {
+		if state.OAuth == nil {
+			return nil, errors.New("State doesn't contain the oauth settings")
+		}
+
+		if err := initOAuthClient(*state.OAuth); err != nil {
 			return nil, err
 		}
 
@@ -118,6 +178,19 @@ func getClient() (*client.Client, error) {
 			return nil, errors.New("State doesn't contain active session")
 		}
 
+		// Refresh right away so the new tokens are kept, refresh tokens might only work once
+		if state.Session.ExpiresAt.Before(time.Now()) {
+			if _, err := oClient.GetAccessToken("", false); err != nil {
+				return nil, err
+			}
+
+			state.Session, _ = oClient.GetSessionInfo()
+
+			if err := SaveState(*state); err != nil {
+				return nil, err
+			}
+		}
+
 		clientConfig.OAuthClient = oClient
 	} else if state.LoginToken != "" {
 		clientConfig.Token = state.LoginToken
@@ -201,13 +274,14 @@ func openbrowser(url string) {
 	}
 }
 
-func initOAuthClient(URL string) error {
+func initOAuthClient(settings OAuthSettings) error {
 	c, err := oauthclient.New(oauthclient.ClientConfig{
-		URL:         URL,
-		ClientID:    clientID,
-		Scope:       scopes,
+		URL:         settings.Host,
+		ClientID:    settings.ClientID,
+		Scope:       settings.Scopes,
 		RedirectURI: "http://localhost:11899/callback",
 		PKCE:        true,
+		Endpoints:   settings.Endpoints,
 	})
 
 	oClient = c
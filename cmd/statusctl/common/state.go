@@ -15,6 +15,7 @@ import (
 type State struct {
 	BaseURL    string                     `json:"baseUrl"`
 	Session    *oauthclient.ClientSession `json:"session"`
+	OAuth      *OAuthSettings             `json:"oauth,omitempty"`
 	LoginToken string                     `json:"loginToken,omitempty"`
 }
 
@@ -13,10 +13,29 @@ import (
 var createCmd = &cobra.Command{
 	Use:     "create",
 	Short:   "create incident",
-	Example: "statusctl incident create",
+	Example: "statusctl incident create [--template name --set region=EU]",
 	Run: func(c *cobra.Command, args []string) {
 		client := common.GetStatusCentralClient()
 
+		if templateName != "" {
+			incident := renderTemplate(client)
+
+			logRenderedIncident(incident)
+
+			create, err := common.GetYesNoPrompt("Create this incident?", true)
+			if err != nil {
+				panic(err)
+			}
+
+			if !create {
+				log.Fatalln("Incident not created")
+			}
+
+			createIncident(incident)
+
+			return
+		}
+
 		services, err := client.Services().GetMultiple()
 		if err != nil {
 			panic(err)
@@ -44,20 +63,24 @@ var createCmd = &cobra.Command{
 			Services: servicesImpacted,
 		}
 
-		returnedIncident, err := client.Incidents().Create(incident)
-		if err != nil {
-			panic(err)
-		}
+		createIncident(incident)
+	},
+}
 
-		log.Println(fmt.Sprintf("Incident %d created!", returnedIncident.ID))
+func createIncident(incident *models.Incident) {
+	returnedIncident, err := common.GetStatusCentralClient().Incidents().Create(incident)
+	if err != nil {
+		panic(err)
+	}
 
-		rendered, err := renderIncident(returnedIncident)
-		if err != nil {
-			panic(err)
-		}
+	log.Println(fmt.Sprintf("Incident %d created!", returnedIncident.ID))
 
-		log.Println(rendered)
-	},
+	rendered, err := renderIncident(returnedIncident)
+	if err != nil {
+		panic(err)
+	}
+
+	log.Println(rendered)
 }
 
 func getImpactedServices(services []*models.Service) ([]models.ServiceUpdate, error) {
@@ -17,6 +17,9 @@ ID: {{.ID}}
 Title: {{.Title}}
 Created: {{ .Time.Format "Jan 02 2006 15:04" }}
 Status: {{.Status}}
+{{ if .PostmortemURL }}Postmortem: {{.PostmortemURL}}
+{{ end }}{{ with .Postmortem }}Postmortem: {{ if .Published }}Published{{ else }}Draft{{ end }}, see statusctl incident postmortem show
+{{ end }}
 Services: 
 {{ range $service := .Services }}
 - Name: {{$service.Name}}
@@ -32,7 +35,7 @@ Updates:
 - ID: {{$update.ID}}
   Time: {{ $update.Time.Format "Jan 02 2006 15:04" }}
   Status: {{ $update.Status }}
-  Message: {{ $update.Message }}
+  Message: {{ $update.Message }}{{ if $update.Edited }} (edited){{ end }}
 {{ end }}
 `
 
@@ -0,0 +1,96 @@
+package incident
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/RocketChat/statuscentral/cmd/statusctl/common"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/spf13/cobra"
+)
+
+var editCmd = &cobra.Command{
+	Use:     "edit",
+	Short:   "edit a published incident update",
+	Example: "statusctl incident update edit [incident id] [update id]",
+	Args:    cobra.ExactArgs(2),
+	Run: func(c *cobra.Command, args []string) {
+		client := common.GetStatusCentralClient()
+
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			panic("Unable to parse incident id")
+		}
+
+		updateID, err := strconv.Atoi(args[1])
+		if err != nil {
+			panic("Unable to parse update id")
+		}
+
+		incident, err := client.Incidents().Get(id)
+		if err != nil {
+			panic(err)
+		}
+
+		var update *models.StatusUpdate
+		for _, u := range incident.Updates {
+			if u.ID == updateID {
+				update = u
+			}
+		}
+
+		if update == nil {
+			log.Fatalln("No update found by that id")
+		}
+
+		log.Printf("%s - %s\n", update.Status, update.Message)
+
+		message := common.StringPromptWithDefault("Status Update Message [unchanged]:", update.Message)
+
+		currentStatus := 0
+		for i, statusOption := range models.IncidentStatusArray {
+			if statusOption == update.Status {
+				currentStatus = i
+			}
+
+			log.Printf("%d) %s\n", i, statusOption)
+		}
+
+		status, err := common.IntPrompt(fmt.Sprintf("Update Status [%d]:", currentStatus), currentStatus)
+		if err != nil || status < 0 || status >= len(models.IncidentStatusArray) {
+			log.Fatalln("Invalid selection")
+		}
+
+		edit := &models.StatusUpdate{
+			Status:  models.IncidentStatusArray[status],
+			Message: message,
+		}
+
+		updateServiceStatus, err := common.GetYesNoPrompt("Update Service Status?", false)
+		if err != nil {
+			panic(err)
+		}
+
+		if updateServiceStatus {
+			services := update.Services
+			if len(services) == 0 {
+				services = incident.Services
+			}
+
+			serviceUpdates, err := updateImpactedServices(append([]models.ServiceUpdate{}, services...))
+			if err != nil {
+				panic(err)
+			}
+
+			edit.Services = serviceUpdates
+		}
+
+		edited, err := client.Incidents().EditStatusUpdate(id, updateID, edit)
+		if err != nil {
+			panic(err)
+		}
+
+		log.Printf("Update %d now reads: %s - %s (%d revisions)\n", edited.ID, edited.Status, edited.Message, len(edited.Revisions))
+	},
+}
@@ -31,6 +31,17 @@ func init() {
 	getCmd.Flags().StringVarP(&outputFormat, "output", "o", "list", "output format")
 	listCmd.Flags().BoolVarP(&latestOnly, "latest", "l", false, "Show latest only")
 
-	SubCommands = append(SubCommands, listCmd, describeCmd, getCmd, createCmd, updateCmd)
+	for _, cmd := range []*cobra.Command{createCmd, updateCmd} {
+		cmd.Flags().StringVarP(&templateName, "template", "t", "", "Name of the incident template to use")
+		cmd.Flags().StringToStringVar(&templateVariables, "set", nil, "Variable of the template, like region=EU sets {{.Region}}, can be repeated")
+	}
+
+	postmortemEditCmd.Flags().StringVarP(&postmortemBodyFile, "body", "b", "", "Markdown file with the body of the postmortem")
+
+	updateCmd.AddCommand(editCmd)
+	templatesCmd.AddCommand(templatesListCmd, templatesCreateCmd, templatesDeleteCmd)
+	postmortemCmd.AddCommand(postmortemShowCmd, postmortemEditCmd, postmortemPublishCmd, postmortemUnpublishCmd, postmortemDeleteCmd)
+
+	SubCommands = append(SubCommands, listCmd, describeCmd, getCmd, createCmd, updateCmd, patchCmd, templatesCmd, postmortemCmd)
 	IncidentCmd.AddCommand(SubCommands...)
 }
@@ -0,0 +1,81 @@
+package incident
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/RocketChat/statuscentral/cmd/statusctl/common"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/spf13/cobra"
+)
+
+var patchCmd = &cobra.Command{
+	Use:     "patch",
+	Short:   "patch an existing incident",
+	Example: "statusctl incident patch [id]",
+	Run: func(c *cobra.Command, args []string) {
+		client := common.GetStatusCentralClient()
+
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			panic("Unable to parse incident id")
+		}
+
+		incident, err := client.Incidents().Get(id)
+		if err != nil {
+			panic(err)
+		}
+
+		rendered, err := renderIncident(incident)
+		if err != nil {
+			panic(err)
+		}
+
+		log.Println(rendered)
+
+		patch := &models.Incident{ID: incident.ID}
+
+		patch.Title = common.StringPromptWithDefault(fmt.Sprintf("Title [%s]:", incident.Title), incident.Title)
+
+		startTimeText := common.StringPrompt(fmt.Sprintf("Start UTC Time [%s]:", incident.Time.UTC().Format("2006/01/02 15:04:05")))
+		if startTimeText != "" {
+			patch.Time, err = time.ParseInLocation("2006/01/02 15:04:05", startTimeText, time.UTC)
+			if err != nil {
+				panic(err)
+			}
+		}
+
+		patch.PostmortemURL = common.StringPromptWithDefault(fmt.Sprintf("Postmortem Link [%s]:", incident.PostmortemURL), incident.PostmortemURL)
+
+		changeServices, err := common.GetYesNoPrompt("Change Impacted Services?", false)
+		if err != nil {
+			panic(err)
+		}
+
+		if changeServices {
+			services, err := client.Services().GetMultiple()
+			if err != nil {
+				panic(err)
+			}
+
+			patch.Services, err = getImpactedServices(services)
+			if err != nil {
+				panic(err)
+			}
+		}
+
+		returnedIncident, err := client.Incidents().Patch(incident.ID, patch)
+		if err != nil {
+			panic(err)
+		}
+
+		renderedResult, err := renderIncident(returnedIncident)
+		if err != nil {
+			panic(err)
+		}
+
+		log.Println(renderedResult)
+	},
+}
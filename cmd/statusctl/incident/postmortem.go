@@ -0,0 +1,251 @@
+package incident
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RocketChat/statuscentral/cmd/statusctl/common"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+var postmortemBodyFile string
+
+var postmortemDetailTemplate = `
+Summary: {{.Summary}}
+State: {{ if .Published }}Published {{ .PublishedAt.Format "Jan 02 2006 15:04" }}{{ else }}Draft{{ end }}
+Impact: {{ .ImpactStart.Format "Jan 02 2006 15:04" }} - {{ .ImpactEnd.Format "Jan 02 2006 15:04" }} ({{ .ImpactDurationText }})
+Root Cause: {{.RootCause}}
+
+Action Items:
+{{ range $item := .ActionItems }}
+- [{{ if $item.Done }}x{{ else }} {{ end }}] {{ $item.Description }}{{ if $item.Owner }} ({{ $item.Owner }}){{ end }}
+{{ end }}
+{{ .Body }}
+`
+
+var postmortemCmd = &cobra.Command{
+	Use: "postmortem",
+	Aliases: []string{
+		"pm",
+	},
+	Short:   "postmortem of a resolved incident",
+	Example: "statusctl incident postmortem [command]",
+	Args: func(c *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("%v requires arguments", c.UseLine())
+		}
+
+		return nil
+	},
+}
+
+var postmortemShowCmd = &cobra.Command{
+	Use: "show",
+	Aliases: []string{
+		"describe",
+	},
+	Short:   "show the postmortem of an incident, published or a draft",
+	Example: "statusctl incident postmortem show [id]",
+	Args:    cobra.ExactArgs(1),
+	Run: func(c *cobra.Command, args []string) {
+		incident, err := common.GetStatusCentralClient().Incidents().Get(parseIncidentID(args[0]))
+		if err != nil {
+			panic(err)
+		}
+
+		if incident.Postmortem == nil {
+			log.Fatalf("Incident %d has no postmortem\n", incident.ID)
+		}
+
+		rendered, err := renderPostmortem(incident.Postmortem)
+		if err != nil {
+			panic(err)
+		}
+
+		log.Println(rendered)
+	},
+}
+
+var postmortemEditCmd = &cobra.Command{
+	Use: "edit",
+	Aliases: []string{
+		"save",
+	},
+	Short:   "write or change the postmortem of a resolved incident, it stays published or a draft as it was",
+	Example: "statusctl incident postmortem edit [id] --body postmortem.md",
+	Args:    cobra.ExactArgs(1),
+	Run: func(c *cobra.Command, args []string) {
+		client := common.GetStatusCentralClient()
+
+		incident, err := client.Incidents().Get(parseIncidentID(args[0]))
+		if err != nil {
+			panic(err)
+		}
+
+		postmortem := incident.Postmortem
+		if postmortem == nil {
+			postmortem = &models.Postmortem{}
+		}
+
+		if postmortemBodyFile != "" {
+			body, err := ioutil.ReadFile(postmortemBodyFile)
+			if err != nil {
+				panic(err)
+			}
+
+			postmortem.Body = string(body)
+		}
+
+		postmortem.Summary = common.StringPromptWithDefault(fmt.Sprintf("Summary [%s]:", postmortem.Summary), postmortem.Summary)
+		postmortem.RootCause = common.StringPromptWithDefault(fmt.Sprintf("Root Cause [%s]:", postmortem.RootCause), postmortem.RootCause)
+
+		postmortem.ImpactStart = postmortemTimePrompt("Impact Start UTC Time", postmortem.ImpactStart)
+		postmortem.ImpactEnd = postmortemTimePrompt("Impact End UTC Time", postmortem.ImpactEnd)
+
+		changeActionItems, err := common.GetYesNoPrompt("Change Action Items?", len(postmortem.ActionItems) == 0)
+		if err != nil {
+			panic(err)
+		}
+
+		if changeActionItems {
+			postmortem.ActionItems = getActionItems()
+		}
+
+		returned, err := client.Incidents().SavePostmortem(incident.ID, postmortem)
+		if err != nil {
+			panic(err)
+		}
+
+		rendered, err := renderPostmortem(returned)
+		if err != nil {
+			panic(err)
+		}
+
+		log.Println(rendered)
+	},
+}
+
+var postmortemPublishCmd = &cobra.Command{
+	Use:     "publish",
+	Short:   "show the postmortem on the status page, the notifiers announce it the first time",
+	Example: "statusctl incident postmortem publish [id]",
+	Args:    cobra.ExactArgs(1),
+	Run: func(c *cobra.Command, args []string) {
+		id := parseIncidentID(args[0])
+
+		if _, err := common.GetStatusCentralClient().Incidents().PublishPostmortem(id); err != nil {
+			panic(err)
+		}
+
+		log.Printf("Postmortem of incident %d published\n", id)
+	},
+}
+
+var postmortemUnpublishCmd = &cobra.Command{
+	Use:     "unpublish",
+	Short:   "make the postmortem a draft again",
+	Example: "statusctl incident postmortem unpublish [id]",
+	Args:    cobra.ExactArgs(1),
+	Run: func(c *cobra.Command, args []string) {
+		id := parseIncidentID(args[0])
+
+		if _, err := common.GetStatusCentralClient().Incidents().UnpublishPostmortem(id); err != nil {
+			panic(err)
+		}
+
+		log.Printf("Postmortem of incident %d unpublished\n", id)
+	},
+}
+
+var postmortemDeleteCmd = &cobra.Command{
+	Use:     "delete",
+	Short:   "delete the postmortem of an incident",
+	Example: "statusctl incident postmortem delete [id]",
+	Args:    cobra.ExactArgs(1),
+	Run: func(c *cobra.Command, args []string) {
+		id := parseIncidentID(args[0])
+
+		if err := common.GetStatusCentralClient().Incidents().DeletePostmortem(id); err != nil {
+			panic(err)
+		}
+
+		log.Printf("Postmortem of incident %d deleted\n", id)
+	},
+}
+
+func parseIncidentID(arg string) int {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		panic("Unable to parse incident id")
+	}
+
+	return id
+}
+
+// postmortemTimePrompt asks for a time, keeping the current one when nothing is entered. The server fills in
+// the times of the incident when they are left empty.
+func postmortemTimePrompt(label string, current time.Time) time.Time {
+	currentText := ""
+	if !current.IsZero() {
+		currentText = current.UTC().Format("2006/01/02 15:04:05")
+	}
+
+	text := common.StringPromptWithDefault(fmt.Sprintf("%s [%s]:", label, currentText), currentText)
+	if text == "" {
+		return time.Time{}
+	}
+
+	t, err := time.ParseInLocation("2006/01/02 15:04:05", text, time.UTC)
+	if err != nil {
+		panic(err)
+	}
+
+	return t
+}
+
+// getActionItems asks for action items until an empty description is entered
+func getActionItems() []models.PostmortemActionItem {
+	actionItems := make([]models.PostmortemActionItem, 0)
+
+	for {
+		description := common.StringPrompt("Action Item (empty to finish):")
+		if description == "" {
+			return actionItems
+		}
+
+		owner := common.StringPrompt("Owner:")
+
+		done, err := common.GetYesNoPrompt("Done?", false)
+		if err != nil {
+			panic(err)
+		}
+
+		actionItems = append(actionItems, models.PostmortemActionItem{
+			Description: description,
+			Owner:       owner,
+			Done:        done,
+		})
+	}
+}
+
+func renderPostmortem(postmortem *models.Postmortem) (string, error) {
+	t, err := template.New("postmortemDetail").Parse(postmortemDetailTemplate)
+	if err != nil {
+		return "", err
+	}
+
+	buf := new(bytes.Buffer)
+
+	if err := t.Execute(buf, postmortem); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
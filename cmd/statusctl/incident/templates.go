@@ -0,0 +1,191 @@
+package incident
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/spf13/cobra"
+
+	"github.com/RocketChat/statuscentral/client"
+	"github.com/RocketChat/statuscentral/cmd/statusctl/common"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+var (
+	templateName      string
+	templateVariables map[string]string
+)
+
+var templatesCmd = &cobra.Command{
+	Use: "templates",
+	Aliases: []string{
+		"template",
+		"tpl",
+	},
+	Short:   "incident templates for common failure scenarios",
+	Example: "statusctl incident templates [command]",
+	Args: func(c *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("%v requires arguments", c.UseLine())
+		}
+
+		return nil
+	},
+}
+
+var templatesListCmd = &cobra.Command{
+	Use: "list",
+	Aliases: []string{
+		"ls",
+	},
+	Short:   "list incident templates",
+	Example: "statusctl incident templates ls",
+	Run: func(c *cobra.Command, args []string) {
+		t := table.NewWriter()
+
+		t.Style().Options.DrawBorder = false
+		t.Style().Options.SeparateRows = false
+		t.Style().Options.SeparateColumns = false
+		t.Style().Options.SeparateHeader = false
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"ID", "Name", "Title", "Status", "Services"})
+
+		incidentTemplates, err := common.GetStatusCentralClient().IncidentTemplates().GetMultiple()
+		if err != nil {
+			panic(err)
+		}
+
+		for _, incidentTemplate := range incidentTemplates {
+			services := make([]string, 0, len(incidentTemplate.Services))
+			for _, s := range incidentTemplate.Services {
+				services = append(services, s.Name)
+			}
+
+			t.AppendRows([]table.Row{
+				{incidentTemplate.ID, incidentTemplate.Name, incidentTemplate.Title, incidentTemplate.Status, strings.Join(services, ", ")},
+			})
+		}
+
+		t.Render()
+	},
+}
+
+var templatesCreateCmd = &cobra.Command{
+	Use:     "create",
+	Short:   "create an incident template",
+	Example: "statusctl incident templates create",
+	Run: func(c *cobra.Command, args []string) {
+		cl := common.GetStatusCentralClient()
+
+		services, err := cl.Services().GetMultiple()
+		if err != nil {
+			panic(err)
+		}
+
+		log.Println("Title and message are templates, like: We are investigating elevated error rates in {{.Region}}")
+
+		name := common.StringPrompt("Template Name:")
+		title := common.StringPrompt("Incident Title:")
+		message := common.StringPrompt("Update Message:")
+
+		for i, statusOption := range models.IncidentStatusArray {
+			log.Printf("%d) %s\n", i, statusOption)
+		}
+
+		status, err := common.IntPrompt("Incident Status [1]:", 1)
+		if err != nil {
+			log.Fatalln("Invalid selection")
+		}
+
+		incidentTemplate := &models.IncidentTemplate{
+			Name:    name,
+			Title:   title,
+			Message: message,
+			Status:  models.IncidentStatusArray[status],
+		}
+
+		addServices, err := common.GetYesNoPrompt("Add affected services?", true)
+		if err != nil {
+			panic(err)
+		}
+
+		if addServices {
+			incidentTemplate.Services, err = getImpactedServices(services)
+			if err != nil {
+				panic(err)
+			}
+		}
+
+		returned, err := cl.IncidentTemplates().Create(incidentTemplate)
+		if err != nil {
+			panic(err)
+		}
+
+		log.Printf("Incident template %d created!\n", returned.ID)
+	},
+}
+
+var templatesDeleteCmd = &cobra.Command{
+	Use:     "delete",
+	Short:   "delete an incident template",
+	Example: "statusctl incident templates delete [id]",
+	Args:    cobra.ExactArgs(1),
+	Run: func(c *cobra.Command, args []string) {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			panic("Unable to parse incident template id")
+		}
+
+		if err := common.GetStatusCentralClient().IncidentTemplates().Delete(id); err != nil {
+			panic(err)
+		}
+
+		log.Printf("Incident template %d deleted\n", id)
+	},
+}
+
+// renderTemplate renders the incident template given with --template, using the variables given with --set
+func renderTemplate(cl *client.Client) *models.Incident {
+	incidentTemplates, err := cl.IncidentTemplates().GetMultiple()
+	if err != nil {
+		panic(err)
+	}
+
+	for _, incidentTemplate := range incidentTemplates {
+		if strings.EqualFold(incidentTemplate.Name, templateName) {
+			incident, err := cl.IncidentTemplates().Render(incidentTemplate.ID, templateVariables)
+			if err != nil {
+				panic(err)
+			}
+
+			return incident
+		}
+	}
+
+	log.Fatalf("No incident template named %s\n", templateName)
+
+	return nil
+}
+
+// logRenderedIncident shows what the template gave before it's used
+func logRenderedIncident(incident *models.Incident) {
+	log.Printf("Title: %s\n", incident.Title)
+	log.Printf("Status: %s\n", incident.Status)
+
+	for _, s := range incident.Services {
+		if len(s.Regions) > 0 {
+			log.Printf("Service: %s (%s) - %s\n", s.Name, strings.Join(s.Regions, ", "), s.Status)
+			continue
+		}
+
+		log.Printf("Service: %s - %s\n", s.Name, s.Status)
+	}
+
+	if len(incident.Updates) > 0 {
+		log.Printf("Message: %s\n", incident.Updates[0].Message)
+	}
+}
@@ -14,7 +14,7 @@ import (
 var updateCmd = &cobra.Command{
 	Use:     "update",
 	Short:   "add incident update",
-	Example: "statusctl incident update",
+	Example: "statusctl incident update [id] [--template name --set region=EU]",
 	Run: func(c *cobra.Command, args []string) {
 		client := common.GetStatusCentralClient()
 
@@ -36,6 +36,36 @@ var updateCmd = &cobra.Command{
 
 		log.Println(rendered)
 
+		if templateName != "" {
+			templated := renderTemplate(client)
+
+			logRenderedIncident(templated)
+
+			post, err := common.GetYesNoPrompt("Post this update?", true)
+			if err != nil {
+				panic(err)
+			}
+
+			if !post {
+				log.Fatalln("Update not posted")
+			}
+
+			incidentUpdate := &models.StatusUpdate{
+				Status:   templated.Updates[0].Status,
+				Message:  templated.Updates[0].Message,
+				Services: templated.Services,
+			}
+
+			// Templates without services leave the ones of the incident as they are
+			if len(incidentUpdate.Services) == 0 {
+				incidentUpdate.Services = incident.Services
+			}
+
+			createIncidentUpdate(incident.ID, incidentUpdate)
+
+			return
+		}
+
 		updateMessage := common.StringPrompt("Status Update Message:")
 
 		for i, statusOption := range models.IncidentStatusArray {
@@ -67,18 +97,22 @@ var updateCmd = &cobra.Command{
 			incidentUpdate.Services = serviceUpdates
 		}
 
-		returnedIncident, err := client.Incidents().CreateStatusUpdate(incident.ID, incidentUpdate)
-		if err != nil {
-			panic(err)
-		}
+		createIncidentUpdate(incident.ID, incidentUpdate)
+	},
+}
 
-		renderedResult, err := renderIncident(returnedIncident)
-		if err != nil {
-			panic(err)
-		}
+func createIncidentUpdate(id int, incidentUpdate *models.StatusUpdate) {
+	returnedIncident, err := common.GetStatusCentralClient().Incidents().CreateStatusUpdate(id, incidentUpdate)
+	if err != nil {
+		panic(err)
+	}
 
-		log.Println(renderedResult)
-	},
+	renderedResult, err := renderIncident(returnedIncident)
+	if err != nil {
+		panic(err)
+	}
+
+	log.Println(renderedResult)
 }
 
 func updateImpactedServices(services []models.ServiceUpdate) ([]models.ServiceUpdate, error) {
@@ -6,8 +6,11 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/RocketChat/statuscentral/buildInfo"
+	"github.com/RocketChat/statuscentral/cmd/statusctl/audit"
 	"github.com/RocketChat/statuscentral/cmd/statusctl/incident"
 	"github.com/RocketChat/statuscentral/cmd/statusctl/maintenance"
+	"github.com/RocketChat/statuscentral/cmd/statusctl/service"
+	"github.com/RocketChat/statuscentral/cmd/statusctl/token"
 )
 
 var rootCmd = &cobra.Command{
@@ -19,5 +22,8 @@ func main() {
 	rootCmd.Version = fmt.Sprintf("%s", buildInfo.GetVersion())
 	rootCmd.AddCommand(incident.IncidentCmd)
 	rootCmd.AddCommand(maintenance.MaintenanceCmd)
+	rootCmd.AddCommand(service.ServiceCmd)
+	rootCmd.AddCommand(token.TokenCmd)
+	rootCmd.AddCommand(audit.AuditCmd)
 	rootCmd.Execute() //nolint:errcheck // Tech debt
 }
@@ -0,0 +1,52 @@
+package maintenance
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/RocketChat/statuscentral/cmd/statusctl/common"
+	"github.com/spf13/cobra"
+)
+
+var cancelCmd = &cobra.Command{
+	Use:     "cancel",
+	Short:   "cancel a maintenance, it stays on the status page marked as cancelled",
+	Example: "statusctl maintenance cancel [id]",
+	Args:    cobra.ExactArgs(1),
+	Run: func(c *cobra.Command, args []string) {
+		client := common.GetStatusCentralClient()
+
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			panic("Unable to parse maintenance id")
+		}
+
+		maintenance, err := client.ScheduledMaintenance().Get(id)
+		if err != nil {
+			panic(err)
+		}
+
+		confirmed, err := common.GetYesNoPrompt("Cancel \""+maintenance.Title+"\"?", false)
+		if err != nil {
+			panic(err)
+		}
+
+		if !confirmed {
+			return
+		}
+
+		message := common.StringPrompt("Status Update Message (optional):")
+
+		returnedMaintenance, err := client.ScheduledMaintenance().Cancel(id, message)
+		if err != nil {
+			panic(err)
+		}
+
+		rendered, err := renderMaintenance(returnedMaintenance)
+		if err != nil {
+			panic(err)
+		}
+
+		log.Println(rendered)
+	},
+}
@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
+	"github.com/RocketChat/statuscentral/client"
 	"github.com/RocketChat/statuscentral/cmd/statusctl/common"
 	"github.com/RocketChat/statuscentral/models"
 	"github.com/spf13/cobra"
@@ -55,9 +57,16 @@ var createCmd = &cobra.Command{
 			PlannedEnd:   plannedEndTime,
 		}
 
-		returnedScheduledMaintenance, err := client.ScheduledMaintenance().Create(scheduledMaintenance)
+		returnedScheduledMaintenance, err := client.ScheduledMaintenance().Create(scheduledMaintenance, false)
 		if err != nil {
-			panic(err)
+			if !confirmConflicts(err) {
+				log.Fatalln("Maintenance not scheduled")
+			}
+
+			returnedScheduledMaintenance, err = client.ScheduledMaintenance().Create(scheduledMaintenance, true)
+			if err != nil {
+				panic(err)
+			}
 		}
 
 		log.Println(fmt.Sprintf("Maintenance %d Scheduled!", returnedScheduledMaintenance.ID))
@@ -112,3 +121,29 @@ func getImpactedServices(services []*models.Service) ([]models.ServiceUpdate, er
 
 	return serviceUpdates, nil
 }
+
+// confirmConflicts lists what the maintenance conflicts with and asks whether to go ahead anyway, any other error panics
+func confirmConflicts(err error) bool {
+	var errResp *client.ErrorResponse
+	if !errors.As(err, &errResp) || len(errResp.Conflicts) == 0 {
+		panic(err)
+	}
+
+	log.Println("The maintenance window conflicts with:")
+
+	for _, conflict := range errResp.Conflicts {
+		if conflict.Type == models.MaintenanceConflictIncident {
+			log.Printf("  Incident %d \"%s\", open since %s, on %s\n", conflict.ID, conflict.Title, conflict.Start.UTC().Format("2006/01/02 15:04:05"), strings.Join(conflict.Services, ", "))
+			continue
+		}
+
+		log.Printf("  Maintenance %d \"%s\", %s to %s, on %s\n", conflict.ID, conflict.Title, conflict.Start.UTC().Format("2006/01/02 15:04:05"), conflict.End.UTC().Format("2006/01/02 15:04:05"), strings.Join(conflict.Services, ", "))
+	}
+
+	proceed, err := common.GetYesNoPrompt("Schedule it anyway?", false)
+	if err != nil {
+		panic(err)
+	}
+
+	return proceed
+}
@@ -18,8 +18,9 @@ Title: {{.Title}}
 Description: {{.Description}}
 Created: {{ .CreatedAt.Format "Jan 02 2006 15:04" }}
 Completed: {{ .Completed }}
-Planned Start: {{ .PlannedStart.Format "Jan 02 2006 15:04" }}
-Planned End: {{ .PlannedEnd.Format "Jan 02 2006 15:04" }}
+{{ if .Cancelled }}Cancelled: {{ .CancelledAt.Format "Jan 02 2006 15:04" }}
+{{ end }}Planned Start: {{ .PlannedStart.Format "Jan 02 2006 15:04" }}
+Planned End: {{ .PlannedEnd.Format "Jan 02 2006 15:04" }}{{ if .ExtendedBy }} (extended by {{ .ExtendedByText }}){{ end }}
 Services: 
 {{ range $service := .Services }}
 - Name: {{$service.Name}}
@@ -35,7 +36,7 @@ Updates:
 - ID: {{$update.ID}}
   Time: {{ $update.Time.Format "Jan 02 15:04" }}
   Status: {{ $update.Status }}
-  Message: {{ $update.Message }}
+  Message: {{ $update.Message }}{{ if $update.Edited }} (edited){{ end }}
 {{ end }}
 `
 
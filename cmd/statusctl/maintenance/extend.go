@@ -0,0 +1,51 @@
+package maintenance
+
+import (
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/RocketChat/statuscentral/cmd/statusctl/common"
+	"github.com/spf13/cobra"
+)
+
+var extendCmd = &cobra.Command{
+	Use:     "extend",
+	Short:   "extend a maintenance in progress",
+	Example: "statusctl maintenance extend [id]",
+	Args:    cobra.ExactArgs(1),
+	Run: func(c *cobra.Command, args []string) {
+		client := common.GetStatusCentralClient()
+
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			panic("Unable to parse maintenance id")
+		}
+
+		maintenance, err := client.ScheduledMaintenance().Get(id)
+		if err != nil {
+			panic(err)
+		}
+
+		log.Printf("%s - planned to end at %s UTC\n", maintenance.Title, maintenance.PlannedEnd.UTC().Format("2006/01/02 15:04:05"))
+
+		minutes, err := common.IntPrompt("Extend By Minutes [30]:", 30)
+		if err != nil || minutes <= 0 {
+			log.Fatalln("Invalid number of minutes")
+		}
+
+		message := common.StringPrompt("Status Update Message (optional):")
+
+		returnedMaintenance, err := client.ScheduledMaintenance().Extend(id, maintenance.PlannedEnd.Add(time.Duration(minutes)*time.Minute), message)
+		if err != nil {
+			panic(err)
+		}
+
+		rendered, err := renderMaintenance(returnedMaintenance)
+		if err != nil {
+			panic(err)
+		}
+
+		log.Println(rendered)
+	},
+}
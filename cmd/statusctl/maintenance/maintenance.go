@@ -31,6 +31,11 @@ func init() {
 	getCmd.Flags().StringVarP(&outputFormat, "output", "o", "list", "output format")
 	listCmd.Flags().BoolVarP(&latestOnly, "latest", "l", false, "Show latest only")
 
-	SubCommands = append(SubCommands, listCmd, describeCmd, getCmd, createCmd, updateCmd, patchCmd)
+	updateCmd.AddCommand(editCmd)
+
+	recurringOccurrencesCmd.Flags().IntVarP(&occurrencesCount, "count", "c", 10, "Number of occurrences to show")
+	recurringCmd.AddCommand(recurringListCmd, recurringCreateCmd, recurringDeleteCmd, recurringOccurrencesCmd, recurringSkipCmd, recurringModifyCmd)
+
+	SubCommands = append(SubCommands, listCmd, describeCmd, getCmd, createCmd, updateCmd, patchCmd, extendCmd, startCmd, cancelCmd, recurringCmd)
 	MaintenanceCmd.AddCommand(SubCommands...)
 }
@@ -104,9 +104,16 @@ var patchCmd = &cobra.Command{
 		maintenance.PlannedStart = plannedStartTime
 		maintenance.PlannedEnd = plannedEndTime
 
-		_, err = client.ScheduledMaintenance().Patch(maintenance.ID, maintenance)
+		_, err = client.ScheduledMaintenance().Patch(maintenance.ID, maintenance, false)
 		if err != nil {
-			panic(err)
+			if !confirmConflicts(err) {
+				log.Fatalln("Maintenance not changed")
+			}
+
+			_, err = client.ScheduledMaintenance().Patch(maintenance.ID, maintenance, true)
+			if err != nil {
+				panic(err)
+			}
 		}
 
 		returnedIncident, err := client.ScheduledMaintenance().CreateStatusUpdate(maintenance.ID, statusUpdate)
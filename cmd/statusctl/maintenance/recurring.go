@@ -0,0 +1,261 @@
+package maintenance
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/spf13/cobra"
+
+	"github.com/RocketChat/statuscentral/cmd/statusctl/common"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// recurringTimeFormat is how the times of recurring maintenance and their occurrences are entered and shown, in UTC
+const recurringTimeFormat = "2006/01/02 15:04:05"
+
+var occurrencesCount int
+
+var recurringCmd = &cobra.Command{
+	Use: "recurring",
+	Aliases: []string{
+		"rec",
+	},
+	Short:   "recurring maintenance, like a weekly patch window",
+	Example: "statusctl maintenance recurring [command]",
+	Args: func(c *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("%v requires arguments", c.UseLine())
+		}
+
+		return nil
+	},
+}
+
+var recurringListCmd = &cobra.Command{
+	Use: "list",
+	Aliases: []string{
+		"ls",
+	},
+	Short:   "list recurring maintenance",
+	Example: "statusctl maintenance recurring ls",
+	Run: func(c *cobra.Command, args []string) {
+		t := newRecurringTable()
+		t.AppendHeader(table.Row{"ID", "Title", "Rule", "Start", "Duration", "Enabled", "Scheduled Until"})
+
+		client := common.GetStatusCentralClient()
+
+		recurringMaintenances, err := client.RecurringMaintenance().GetMultiple()
+		if err != nil {
+			panic(err)
+		}
+
+		for _, r := range recurringMaintenances {
+			scheduledUntil := "-"
+			if !r.CreatedUntil.IsZero() {
+				scheduledUntil = r.CreatedUntil.UTC().Format(recurringTimeFormat)
+			}
+
+			t.AppendRows([]table.Row{
+				{r.ID, r.Title, r.Rule, r.Start.UTC().Format(recurringTimeFormat), fmt.Sprintf("%d min", r.DurationMinutes), r.Enabled, scheduledUntil},
+			})
+		}
+
+		t.Render()
+	},
+}
+
+var recurringCreateCmd = &cobra.Command{
+	Use:     "create",
+	Short:   "create a recurring maintenance",
+	Example: "statusctl maintenance recurring create",
+	Run: func(c *cobra.Command, args []string) {
+		client := common.GetStatusCentralClient()
+
+		services, err := client.Services().GetMultiple()
+		if err != nil {
+			panic(err)
+		}
+
+		title := common.StringPrompt("Maintenance Short Descripton / Title:")
+		description := common.StringPrompt("Longer Description:")
+
+		servicesImpacted, err := getImpactedServices(services)
+		if err != nil {
+			panic(err)
+		}
+
+		rule := common.StringPromptWithDefault("Recurrence Rule [FREQ=WEEKLY]:", "FREQ=WEEKLY")
+
+		start, err := time.ParseInLocation(recurringTimeFormat, common.StringPrompt("First Occurrence UTC Time (format: 2022/07/19 02:00:00):"), time.UTC)
+		if err != nil {
+			panic(err)
+		}
+
+		duration, err := common.IntPrompt("Duration In Minutes [60]:", 60)
+		if err != nil {
+			log.Fatalln("Invalid duration")
+		}
+
+		returned, err := client.RecurringMaintenance().Create(&models.RecurringMaintenance{
+			Title:           title,
+			Description:     description,
+			Services:        servicesImpacted,
+			Rule:            rule,
+			Start:           start,
+			DurationMinutes: duration,
+			Enabled:         true,
+		})
+		if err != nil {
+			panic(err)
+		}
+
+		log.Printf("Recurring maintenance %d created!\n", returned.ID)
+
+		renderOccurrences(client.RecurringMaintenance().GetOccurrences(returned.ID, 5))
+	},
+}
+
+var recurringDeleteCmd = &cobra.Command{
+	Use:     "delete",
+	Short:   "delete a recurring maintenance, the occurrences already scheduled stay",
+	Example: "statusctl maintenance recurring delete [id]",
+	Args:    cobra.ExactArgs(1),
+	Run: func(c *cobra.Command, args []string) {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			panic("Unable to parse recurring maintenance id")
+		}
+
+		if err := common.GetStatusCentralClient().RecurringMaintenance().Delete(id); err != nil {
+			panic(err)
+		}
+
+		log.Printf("Recurring maintenance %d deleted\n", id)
+	},
+}
+
+var recurringOccurrencesCmd = &cobra.Command{
+	Use:     "occurrences",
+	Short:   "list the next occurrences of a recurring maintenance",
+	Example: "statusctl maintenance recurring occurrences [id]",
+	Args:    cobra.ExactArgs(1),
+	Run: func(c *cobra.Command, args []string) {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			panic("Unable to parse recurring maintenance id")
+		}
+
+		renderOccurrences(common.GetStatusCentralClient().RecurringMaintenance().GetOccurrences(id, occurrencesCount))
+	},
+}
+
+var recurringSkipCmd = &cobra.Command{
+	Use:     "skip",
+	Short:   "skip one occurrence of a recurring maintenance",
+	Example: "statusctl maintenance recurring skip [id] \"2022/07/19 02:00:00\"",
+	Args:    cobra.ExactArgs(2),
+	Run: func(c *cobra.Command, args []string) {
+		id, occurrence := parseOccurrenceArgs(args)
+
+		setException(id, &models.RecurringMaintenanceException{
+			Occurrence: occurrence,
+			Skip:       true,
+		})
+	},
+}
+
+var recurringModifyCmd = &cobra.Command{
+	Use:     "modify",
+	Short:   "change the window, title or description of one occurrence of a recurring maintenance",
+	Example: "statusctl maintenance recurring modify [id] \"2022/07/19 02:00:00\"",
+	Args:    cobra.ExactArgs(2),
+	Run: func(c *cobra.Command, args []string) {
+		id, occurrence := parseOccurrenceArgs(args)
+
+		exception := &models.RecurringMaintenanceException{
+			Occurrence:  occurrence,
+			Title:       common.StringPrompt("Title (empty keeps the one of the recurring maintenance):"),
+			Description: common.StringPrompt("Description (empty keeps the one of the recurring maintenance):"),
+		}
+
+		var err error
+
+		if text := common.StringPrompt(fmt.Sprintf("Planned Start UTC Time [%s]:", occurrence.Format(recurringTimeFormat))); text != "" {
+			exception.PlannedStart, err = time.ParseInLocation(recurringTimeFormat, text, time.UTC)
+			if err != nil {
+				panic(err)
+			}
+		}
+
+		if text := common.StringPrompt("Planned End UTC Time (empty keeps the duration):"); text != "" {
+			exception.PlannedEnd, err = time.ParseInLocation(recurringTimeFormat, text, time.UTC)
+			if err != nil {
+				panic(err)
+			}
+		}
+
+		setException(id, exception)
+	},
+}
+
+func parseOccurrenceArgs(args []string) (int, time.Time) {
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		panic("Unable to parse recurring maintenance id")
+	}
+
+	occurrence, err := time.ParseInLocation(recurringTimeFormat, args[1], time.UTC)
+	if err != nil {
+		panic(err)
+	}
+
+	return id, occurrence
+}
+
+func setException(id int, exception *models.RecurringMaintenanceException) {
+	client := common.GetStatusCentralClient()
+
+	if _, err := client.RecurringMaintenance().SetException(id, exception); err != nil {
+		panic(err)
+	}
+
+	renderOccurrences(client.RecurringMaintenance().GetOccurrences(id, 5))
+}
+
+func renderOccurrences(occurrences []models.RecurringMaintenanceOccurrence, err error) {
+	if err != nil {
+		panic(err)
+	}
+
+	t := newRecurringTable()
+	t.AppendHeader(table.Row{"Occurrence", "Title", "Planned Start", "Planned End", "Skipped", "Maintenance"})
+
+	for _, o := range occurrences {
+		maintenance := "-"
+		if o.ScheduledMaintenanceID != 0 {
+			maintenance = strconv.Itoa(o.ScheduledMaintenanceID)
+		}
+
+		t.AppendRows([]table.Row{
+			{o.Occurrence.UTC().Format(recurringTimeFormat), o.Title, o.PlannedStart.UTC().Format(recurringTimeFormat), o.PlannedEnd.UTC().Format(recurringTimeFormat), o.Skipped, maintenance},
+		})
+	}
+
+	t.Render()
+}
+
+func newRecurringTable() table.Writer {
+	t := table.NewWriter()
+
+	t.Style().Options.DrawBorder = false
+	t.Style().Options.SeparateRows = false
+	t.Style().Options.SeparateColumns = false
+	t.Style().Options.SeparateHeader = false
+	t.SetOutputMirror(os.Stdout)
+
+	return t
+}
@@ -0,0 +1,38 @@
+package maintenance
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/RocketChat/statuscentral/cmd/statusctl/common"
+	"github.com/spf13/cobra"
+)
+
+var startCmd = &cobra.Command{
+	Use:     "start",
+	Short:   "start a maintenance before its planned start",
+	Example: "statusctl maintenance start [id]",
+	Args:    cobra.ExactArgs(1),
+	Run: func(c *cobra.Command, args []string) {
+		client := common.GetStatusCentralClient()
+
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			panic("Unable to parse maintenance id")
+		}
+
+		message := common.StringPrompt("Status Update Message (optional):")
+
+		returnedMaintenance, err := client.ScheduledMaintenance().Start(id, message)
+		if err != nil {
+			panic(err)
+		}
+
+		rendered, err := renderMaintenance(returnedMaintenance)
+		if err != nil {
+			panic(err)
+		}
+
+		log.Println(rendered)
+	},
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"log"
+	"os"
+	"strconv"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/spf13/cobra"
+
+	"github.com/RocketChat/statuscentral/client"
+	"github.com/RocketChat/statuscentral/cmd/statusctl/common"
+)
+
+var historyLimit = 25
+
+var historyCmd = &cobra.Command{
+	Use:     "history [id or name]",
+	Short:   "Show the status history of a service",
+	Example: "statusctl service history 1",
+	Args:    cobra.ExactArgs(1),
+	Run: func(c *cobra.Command, args []string) {
+		cl := common.GetStatusCentralClient()
+
+		id, err := serviceID(cl, args[0])
+		if err != nil {
+			panic(err)
+		}
+
+		changes, err := cl.Services().GetHistory(id, historyLimit)
+		if err != nil {
+			panic(err)
+		}
+
+		t := table.NewWriter()
+
+		t.Style().Options.DrawBorder = false
+		t.Style().Options.SeparateRows = false
+		t.Style().Options.SeparateColumns = false
+		t.Style().Options.SeparateHeader = false
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"Time", "Region", "From", "To", "Cause", "Actor"})
+
+		for _, change := range changes {
+			cause := ""
+			if change.IncidentID != 0 {
+				cause = "incident #" + strconv.Itoa(change.IncidentID)
+			} else if change.ScheduledMaintenanceID != 0 {
+				cause = "maintenance #" + strconv.Itoa(change.ScheduledMaintenanceID)
+			}
+
+			t.AppendRows([]table.Row{
+				{change.Time.Format("Jan 02 2006 15:04"), change.RegionCode, change.PreviousStatus, change.Status, cause, change.Actor},
+			})
+		}
+
+		t.Render()
+	},
+}
+
+// serviceID resolves the argument to a service id, it can either be the id itself or the name of the service
+func serviceID(cl *client.Client, arg string) (int, error) {
+	if id, err := strconv.Atoi(arg); err == nil {
+		return id, nil
+	}
+
+	services, err := cl.Services().GetMultiple()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, s := range services {
+		if s.Name == arg {
+			return s.ID, nil
+		}
+	}
+
+	log.Fatalln("Unknown service:", arg)
+
+	return 0, nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"os"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/spf13/cobra"
+
+	"github.com/RocketChat/statuscentral/cmd/statusctl/common"
+)
+
+var listCmd = &cobra.Command{
+	Use: "list",
+	Aliases: []string{
+		"ls",
+	},
+	Short:   "List services",
+	Example: "statusctl service ls",
+	Run: func(c *cobra.Command, args []string) {
+		t := table.NewWriter()
+
+		t.Style().Options.DrawBorder = false
+		t.Style().Options.SeparateRows = false
+		t.Style().Options.SeparateColumns = false
+		t.Style().Options.SeparateHeader = false
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"ID", "Name", "Status", "Enabled"})
+
+		cl := common.GetStatusCentralClient()
+
+		services, err := cl.Services().GetMultiple()
+		if err != nil {
+			panic(err)
+		}
+
+		for _, service := range services {
+			t.AppendRows([]table.Row{
+				{service.ID, service.Name, service.Status, service.Enabled},
+			})
+		}
+
+		t.Render()
+	},
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var SubCommands []*cobra.Command
+
+var ServiceCmd = &cobra.Command{
+	Use: "service",
+	Aliases: []string{
+		"svc",
+		"s",
+	},
+	Short:   "StatusCentral services",
+	Example: "statusctl service [command]",
+	Args: func(c *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("%v requires arguments", c.UseLine())
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 25, "Number of changes to show")
+
+	SubCommands = append(SubCommands, listCmd, historyCmd)
+	ServiceCmd.AddCommand(SubCommands...)
+}
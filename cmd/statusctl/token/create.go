@@ -0,0 +1,54 @@
+package token
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RocketChat/statuscentral/cmd/statusctl/common"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+var (
+	tokenName      string
+	tokenScopes    []string
+	tokenExpiresIn time.Duration
+)
+
+var createCmd = &cobra.Command{
+	Use:     "create",
+	Short:   "Create an api token",
+	Example: "statusctl token create --name deploy-bot --scope incidents:write --scope read-private --expires-in 720h",
+	Run: func(c *cobra.Command, args []string) {
+		client := common.GetStatusCentralClient()
+
+		token := &models.APIToken{
+			Name:   tokenName,
+			Scopes: scopesFromFlags(tokenScopes),
+		}
+
+		if tokenExpiresIn > 0 {
+			expiresAt := time.Now().Add(tokenExpiresIn)
+			token.ExpiresAt = &expiresAt
+		}
+
+		created, err := client.APITokens().Create(token)
+		if err != nil {
+			panic(err)
+		}
+
+		log.Println(fmt.Sprintf("Token %d (%s) created, it won't be shown again:", created.ID, created.Name))
+		fmt.Println(created.Token)
+	},
+}
+
+func scopesFromFlags(values []string) []models.Scope {
+	scopes := make([]models.Scope, 0, len(values))
+	for _, v := range values {
+		scopes = append(scopes, models.Scope(v))
+	}
+
+	return scopes
+}
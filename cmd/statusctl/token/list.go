@@ -0,0 +1,68 @@
+package token
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/table"
+	"github.com/spf13/cobra"
+
+	"github.com/RocketChat/statuscentral/cmd/statusctl/common"
+)
+
+var listCmd = &cobra.Command{
+	Use: "list",
+	Aliases: []string{
+		"ls",
+	},
+	Short:   "List api tokens",
+	Example: "statusctl token ls",
+	Run: func(c *cobra.Command, args []string) {
+		t := table.NewWriter()
+
+		t.Style().Options.DrawBorder = false
+		t.Style().Options.SeparateRows = false
+		t.Style().Options.SeparateColumns = false
+		t.Style().Options.SeparateHeader = false
+		t.SetOutputMirror(os.Stdout)
+		t.AppendHeader(table.Row{"ID", "Name", "Prefix", "Scopes", "Expires", "Last Used", "State"})
+
+		cl := common.GetStatusCentralClient()
+
+		tokens, err := cl.APITokens().GetMultiple()
+		if err != nil {
+			panic(err)
+		}
+
+		now := time.Now()
+
+		for _, token := range tokens {
+			scopes := make([]string, 0, len(token.Scopes))
+			for _, s := range token.Scopes {
+				scopes = append(scopes, string(s))
+			}
+
+			state := "active"
+			if token.RevokedAt != nil {
+				state = "revoked"
+			} else if !token.IsActive(now) {
+				state = "expired"
+			}
+
+			t.AppendRows([]table.Row{
+				{token.ID, token.Name, token.Prefix, strings.Join(scopes, ","), formatOptionalTime(token.ExpiresAt, "never"), formatOptionalTime(token.LastUsedAt, "never"), state},
+			})
+		}
+
+		t.Render()
+	},
+}
+
+func formatOptionalTime(t *time.Time, empty string) string {
+	if t == nil {
+		return empty
+	}
+
+	return t.Format("Jan 02 2006 15:04")
+}
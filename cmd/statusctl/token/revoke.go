@@ -0,0 +1,33 @@
+package token
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/RocketChat/statuscentral/cmd/statusctl/common"
+)
+
+var revokeCmd = &cobra.Command{
+	Use:     "revoke [id]",
+	Short:   "Revoke an api token",
+	Example: "statusctl token revoke 3",
+	Args:    cobra.ExactArgs(1),
+	Run: func(c *cobra.Command, args []string) {
+		client := common.GetStatusCentralClient()
+
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			panic("Unable to parse token id")
+		}
+
+		token, err := client.APITokens().Revoke(id)
+		if err != nil {
+			panic(err)
+		}
+
+		log.Println(fmt.Sprintf("Token %d (%s) revoked", token.ID, token.Name))
+	},
+}
@@ -0,0 +1,37 @@
+package token
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var SubCommands []*cobra.Command
+
+var TokenCmd = &cobra.Command{
+	Use: "token",
+	Aliases: []string{
+		"tokens",
+		"t",
+	},
+	Short:   "StatusCentral api tokens",
+	Example: "statusctl token [command]",
+	Args: func(c *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return fmt.Errorf("%v requires arguments", c.UseLine())
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	createCmd.Flags().StringVarP(&tokenName, "name", "n", "", "Name of the token")
+	createCmd.Flags().StringSliceVarP(&tokenScopes, "scope", "s", nil, "Scope of the token, can be repeated: read-private, incidents:write, maintenance:write, services:admin, snapshot or admin")
+	createCmd.Flags().DurationVarP(&tokenExpiresIn, "expires-in", "e", 0, "How long until the token expires, like 720h. Never expires when not set")
+	createCmd.MarkFlagRequired("name")  //nolint:errcheck // Only fails for unknown flags
+	createCmd.MarkFlagRequired("scope") //nolint:errcheck // Only fails for unknown flags
+
+	SubCommands = append(SubCommands, listCmd, createCmd, revokeCmd)
+	TokenCmd.AddCommand(SubCommands...)
+}
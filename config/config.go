@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
@@ -22,10 +23,28 @@ type config struct {
 	Services  []serviceConfig `yaml:"services" json:"services"`
 	Regions   []regionConfig  `yaml:"regions" json:"regions"`
 	Twitter   twitterConfig   `yaml:"twitter" json:"twitter"`
+
+	SMTP          smtpConfig          `yaml:"smtp" json:"smtp"`
+	Subscriptions subscriptionsConfig `yaml:"subscriptions" json:"subscriptions"`
+	Webhooks      webhooksConfig      `yaml:"webhooks" json:"webhooks"`
+	RocketChat    rocketChatConfig    `yaml:"rocketchat" json:"rocketchat"`
+	Notifiers     []NotifierConfig    `yaml:"notifiers" json:"notifiers"`
+	Uptime        uptimeConfig        `yaml:"uptime" json:"uptime"`
+	Probes        []ProbeConfig       `yaml:"probes" json:"probes"`
+	Alertmanager  alertmanagerConfig  `yaml:"alertmanager" json:"alertmanager"`
+	OIDC          oidcConfig          `yaml:"oidc" json:"oidc"`
+
+	ScheduledMaintenance scheduledMaintenanceConfig `yaml:"scheduledMaintenance" json:"scheduledMaintenance"`
 }
 
 type httpConfig struct {
 	Port int `yaml:"port" json:"port"`
+
+	// SecureCookies marks the cookies of the admin ui as https only, it should be on unless the status page is served over plain http
+	SecureCookies bool `yaml:"secureCookies" json:"secureCookies"`
+
+	// TrustedProxies are the addresses or CIDRs of the proxies whose X-Forwarded-For header is used as the client address
+	TrustedProxies []string `yaml:"trustedProxies" json:"trustedProxies"`
 }
 
 type websiteConfig struct {
@@ -33,6 +52,7 @@ type websiteConfig struct {
 	Title           string `yaml:"title" json:"title"`
 	CacheBreaker    string `yaml:"cacheBreaker" json:"cacheBreaker"`
 	EmptyDaysToShow int    `yaml:"emptyDaysToShow" json:"emptyDaysToShow"`
+	URL             string `yaml:"url" json:"url"`
 }
 
 type serviceConfig struct {
@@ -55,6 +75,119 @@ type twitterConfig struct {
 	AccessSecret   string `yaml:"accessSecret" json:"accessSecret"`
 }
 
+type smtpConfig struct {
+	Host     string `yaml:"host" json:"host"`
+	Port     int    `yaml:"port" json:"port"`
+	Username string `yaml:"username" json:"username"`
+	Password string `yaml:"password" json:"-"`
+	From     string `yaml:"from" json:"from"`
+}
+
+type subscriptionsConfig struct {
+	Enabled     bool   `yaml:"enabled" json:"enabled"`
+	TokenSecret string `yaml:"tokenSecret" json:"-"`
+}
+
+type webhooksConfig struct {
+	Enabled     bool                    `yaml:"enabled" json:"enabled"`
+	MaxAttempts int                     `yaml:"maxAttempts" json:"maxAttempts"`
+	Endpoints   []webhookEndpointConfig `yaml:"endpoints" json:"endpoints"`
+}
+
+type webhookEndpointConfig struct {
+	Name   string   `yaml:"name" json:"name"`
+	URL    string   `yaml:"url" json:"url"`
+	Secret string   `yaml:"secret" json:"-"`
+	Events []string `yaml:"events" json:"events"`
+}
+
+type rocketChatConfig struct {
+	Enabled     bool     `yaml:"enabled" json:"enabled"`
+	WebhookURLs []string `yaml:"webhookUrls" json:"-"`
+}
+
+type uptimeConfig struct {
+	// Weights is how much of the time spent in a status counts as downtime, keyed by the status name
+	Weights map[string]float64 `yaml:"weights" json:"weights"`
+}
+
+type scheduledMaintenanceConfig struct {
+	// RecurringLeadTime is how far ahead the occurrences of recurring maintenance are scheduled
+	RecurringLeadTime time.Duration `yaml:"recurringLeadTime" json:"recurringLeadTime"`
+}
+
+type alertmanagerConfig struct {
+	Enabled bool   `yaml:"enabled" json:"enabled"`
+	Token   string `yaml:"token" json:"-"`
+	// Rules map the alerts to services and regions, the first one matching an alert is used
+	Rules []AlertmanagerRuleConfig `yaml:"rules" json:"rules"`
+}
+
+// AlertmanagerRuleConfig maps the alerts whose labels match to a service and optionally a region. Either
+// can be fixed or taken from a label of the alert.
+type AlertmanagerRuleConfig struct {
+	Match        map[string]string `yaml:"match" json:"match"`
+	Service      string            `yaml:"service" json:"service"`
+	ServiceLabel string            `yaml:"serviceLabel" json:"serviceLabel"`
+	Region       string            `yaml:"region" json:"region"`
+	RegionLabel  string            `yaml:"regionLabel" json:"regionLabel"`
+	Status       string            `yaml:"status" json:"status"`
+}
+
+type oidcConfig struct {
+	Enabled bool `yaml:"enabled" json:"enabled"`
+	// Issuer is where the openid configuration and the signing keys are discovered from
+	Issuer   string `yaml:"issuer" json:"issuer"`
+	Audience string `yaml:"audience" json:"audience"`
+	// GroupsClaim holds the groups of the user, nested claims are separated by dots like realm_access.roles
+	GroupsClaim string `yaml:"groupsClaim" json:"groupsClaim"`
+	// Roles give scopes to the users, who get the scopes of every role they match
+	Roles []OIDCRoleConfig `yaml:"roles" json:"roles"`
+}
+
+// OIDCRoleConfig gives the scopes to the users in the group, or whose claim has the value
+type OIDCRoleConfig struct {
+	Name   string   `yaml:"name" json:"name"`
+	Group  string   `yaml:"group" json:"group"`
+	Claim  string   `yaml:"claim" json:"claim"`
+	Value  string   `yaml:"value" json:"value"`
+	Scopes []string `yaml:"scopes" json:"scopes"`
+}
+
+// NotifierConfig holds the definition of a notifier, the settings depend on its type
+type NotifierConfig struct {
+	Type     string                 `yaml:"type" json:"type"`
+	Name     string                 `yaml:"name" json:"name"`
+	Enabled  bool                   `yaml:"enabled" json:"enabled"`
+	Events   []string               `yaml:"events" json:"events"`
+	Settings map[string]interface{} `yaml:"settings" json:"-"`
+}
+
+// ProbeConfig holds the definition of a synthetic health check of a service or one of its regions
+type ProbeConfig struct {
+	Name    string `yaml:"name" json:"name"`
+	Service string `yaml:"service" json:"service"`
+	Region  string `yaml:"region" json:"region"`
+
+	// Type is either http, tcp or dns. The target is the url, host:port or hostname respectively
+	Type     string        `yaml:"type" json:"type"`
+	Target   string        `yaml:"target" json:"target"`
+	Interval time.Duration `yaml:"interval" json:"interval"`
+	Timeout  time.Duration `yaml:"timeout" json:"timeout"`
+
+	// What a passing check looks like, a check slower than the max latency fails as well
+	ExpectedStatus  int           `yaml:"expectedStatus" json:"expectedStatus"`
+	ExpectedBody    string        `yaml:"expectedBody" json:"expectedBody"`
+	ExpectedAddress string        `yaml:"expectedAddress" json:"expectedAddress"`
+	MaxLatency      time.Duration `yaml:"maxLatency" json:"maxLatency"`
+
+	// How many checks in a row it takes to open and to resolve the incident
+	FailureThreshold int    `yaml:"failureThreshold" json:"failureThreshold"`
+	SuccessThreshold int    `yaml:"successThreshold" json:"successThreshold"`
+	FailureStatus    string `yaml:"failureStatus" json:"failureStatus"`
+	AutoResolve      bool   `yaml:"autoResolve" json:"autoResolve"`
+}
+
 func (c *config) Load(filePath string) error {
 	yamlFile, err := ioutil.ReadFile(filePath)
 	if err != nil {
@@ -79,6 +212,137 @@ func (c *config) VerifySettings() error {
 		return errors.New("dataPath must end with '/'")
 	}
 
+	if c.Subscriptions.Enabled {
+		if c.Subscriptions.TokenSecret == "" {
+			return errors.New("subscriptions.tokenSecret is required when subscriptions are enabled")
+		}
+
+		if c.SMTP.Host == "" || c.SMTP.From == "" {
+			return errors.New("smtp.host and smtp.from are required when subscriptions are enabled")
+		}
+
+		if c.Website.URL == "" {
+			return errors.New("website.url is required when subscriptions are enabled")
+		}
+	}
+
+	if c.RocketChat.Enabled && len(c.RocketChat.WebhookURLs) == 0 {
+		return errors.New("rocketchat.webhookUrls must have at least one url when enabled")
+	}
+
+	for status, weight := range c.Uptime.Weights {
+		switch strings.ToLower(status) {
+		case "nominal", "degraded", "partial-outage", "outage", "scheduled maintenance":
+		default:
+			return errors.New("uptime.weights has an unknown status: " + status)
+		}
+
+		if weight < 0 || weight > 1 {
+			return errors.New("uptime.weights must be between 0 and 1")
+		}
+	}
+
+	if c.ScheduledMaintenance.RecurringLeadTime <= 0 {
+		c.ScheduledMaintenance.RecurringLeadTime = 7 * 24 * time.Hour
+	}
+
+	for _, notifier := range c.Notifiers {
+		if notifier.Type == "" {
+			return errors.New("notifiers must all have a type")
+		}
+	}
+
+	probeNames := make(map[string]bool)
+	for i := range c.Probes {
+		probe := &c.Probes[i]
+
+		if probe.Name == "" || probe.Service == "" || probe.Target == "" {
+			return errors.New("probes must all have a name, service and target")
+		}
+
+		if probeNames[probe.Name] {
+			return errors.New("duplicate probe name: " + probe.Name)
+		}
+
+		probeNames[probe.Name] = true
+
+		switch probe.Type {
+		case "http", "tcp", "dns":
+		default:
+			return errors.New("probe " + probe.Name + " has an unknown type, must be http, tcp or dns")
+		}
+
+		switch strings.ToLower(probe.FailureStatus) {
+		case "", "degraded", "partial-outage", "outage":
+		default:
+			return errors.New("probe " + probe.Name + " has an invalid failureStatus, must be Degraded, Partial-outage or Outage")
+		}
+
+		if probe.Interval <= 0 {
+			probe.Interval = time.Minute
+		}
+
+		if probe.Timeout <= 0 {
+			probe.Timeout = 10 * time.Second
+		}
+
+		if probe.FailureThreshold <= 0 {
+			probe.FailureThreshold = 3
+		}
+
+		if probe.SuccessThreshold <= 0 {
+			probe.SuccessThreshold = 3
+		}
+	}
+
+	if c.Alertmanager.Enabled {
+		if c.Alertmanager.Token == "" {
+			return errors.New("alertmanager.token is required when the alertmanager integration is enabled")
+		}
+
+		if c.Alertmanager.Token == c.AuthToken {
+			return errors.New("alertmanager.token must be different from the authToken")
+		}
+	}
+
+	for _, rule := range c.Alertmanager.Rules {
+		if rule.Service == "" && rule.ServiceLabel == "" {
+			return errors.New("alertmanager.rules must all have a service or serviceLabel")
+		}
+
+		switch strings.ToLower(rule.Status) {
+		case "", "degraded", "partial-outage", "outage":
+		default:
+			return errors.New("alertmanager.rules have an invalid status, must be Degraded, Partial-outage or Outage")
+		}
+	}
+
+	if c.OIDC.Enabled {
+		if c.OIDC.Issuer == "" || c.OIDC.Audience == "" {
+			return errors.New("oidc.issuer and oidc.audience are required when oidc is enabled")
+		}
+
+		if c.OIDC.GroupsClaim == "" {
+			c.OIDC.GroupsClaim = "groups"
+		}
+	}
+
+	for _, role := range c.OIDC.Roles {
+		if role.Group == "" && (role.Claim == "" || role.Value == "") {
+			return errors.New("oidc.roles must all have a group, or a claim and value")
+		}
+
+		if len(role.Scopes) == 0 {
+			return errors.New("oidc.roles must all have at least one scope")
+		}
+	}
+
+	for _, endpoint := range c.Webhooks.Endpoints {
+		if endpoint.Name == "" || endpoint.URL == "" {
+			return errors.New("webhooks.endpoints must all have a name and url")
+		}
+	}
+
 	return nil
 }
 
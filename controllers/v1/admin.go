@@ -0,0 +1,535 @@
+package v1
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/RocketChat/statuscentral/router/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// adminTimeLayout is the layout of the datetime-local inputs, their times are in utc
+const adminTimeLayout = "2006-01-02T15:04"
+
+// adminServiceStatuses are the statuses responders can give services and regions
+var adminServiceStatuses = []models.ServiceAndRegionStatus{
+	models.ServiceStatusNominal,
+	models.ServiceStatusDegraded,
+	models.ServiceStatusPartialOutage,
+	models.ServiceStatusOutage,
+	models.ServiceStatusScheduledMaintenance,
+}
+
+// adminIncidentStatuses are the statuses of incidents and their updates, without the deprecated maintenance one
+var adminIncidentStatuses = []models.IncidentStatus{
+	models.IncidentStatusInvestigating,
+	models.IncidentStatusIdentified,
+	models.IncidentStatusUpdate,
+	models.IncidentStatusMonitoring,
+	models.IncidentStatusResolved,
+}
+
+// adminServicePicker is a service of the service status picker, with what was picked for it
+type adminServicePicker struct {
+	ID      int
+	Name    string
+	Status  models.ServiceAndRegionStatus
+	Regions []adminRegionPicker
+}
+
+// adminRegionPicker is a region of a service in the service status picker
+type adminRegionPicker struct {
+	Code    string
+	Name    string
+	Checked bool
+}
+
+// AdminLoginPage is the html controller for the sign in page of the admin ui
+func AdminLoginPage(c *gin.Context) {
+	c.HTML(http.StatusOK, "adminLogin.tmpl", adminData(c, gin.H{}))
+}
+
+// AdminLogin signs in to the admin ui with any token the api accepts, the browser only gets the id of the session
+func AdminLogin(c *gin.Context) {
+	token := strings.TrimSpace(c.PostForm("token"))
+
+	status := http.StatusUnauthorized
+	if token != "" {
+		status = middleware.Authenticate(c, token)
+	}
+
+	switch status {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		c.HTML(http.StatusUnauthorized, "adminLogin.tmpl", adminData(c, gin.H{"error": "The token isn't valid."}))
+		return
+	default:
+		c.HTML(http.StatusInternalServerError, "adminLogin.tmpl", adminData(c, gin.H{"error": "The token couldn't be checked, please try again."}))
+		return
+	}
+
+	session, err := core.CreateAdminSession(token)
+	if err != nil {
+		log.Println(err)
+		c.HTML(http.StatusInternalServerError, "adminLogin.tmpl", adminData(c, gin.H{"error": "The session couldn't be created, please try again."}))
+		return
+	}
+
+	middleware.SetAdminSessionCookie(c, session)
+
+	c.Redirect(http.StatusSeeOther, "/admin")
+}
+
+// AdminLogout signs out of the admin ui
+func AdminLogout(c *gin.Context) {
+	if session, err := c.Cookie(middleware.AdminSessionCookie); err == nil {
+		core.DeleteAdminSession(session)
+	}
+
+	middleware.ClearAdminSessionCookie(c)
+
+	c.Redirect(http.StatusSeeOther, "/admin/login")
+}
+
+// AdminDashboardHandler is the html controller for the admin ui, it lists what is still open
+func AdminDashboardHandler(c *gin.Context) {
+	incidents, err := core.GetOpenIncidents()
+	if err != nil {
+		adminErrorHandler(c, err)
+		return
+	}
+
+	scheduledMaintenance, err := core.GetPendingScheduledMaintenance()
+	if err != nil {
+		adminErrorHandler(c, err)
+		return
+	}
+
+	c.HTML(http.StatusOK, "adminDashboard.tmpl", adminData(c, gin.H{
+		"incidents":            incidents,
+		"scheduledMaintenance": scheduledMaintenance,
+	}))
+}
+
+// AdminIncidentNewHandler is the html controller for the form creating an incident
+func AdminIncidentNewHandler(c *gin.Context) {
+	renderAdminIncidentNew(c, http.StatusOK, &models.Incident{Status: models.IncidentDefaultStatus}, "", gin.H{})
+}
+
+// AdminIncidentCreateHandler previews or creates the incident of the form
+func AdminIncidentCreateHandler(c *gin.Context) {
+	incident := &models.Incident{
+		Time:   time.Now(),
+		Title:  strings.TrimSpace(c.PostForm("title")),
+		Status: models.IncidentStatus(c.PostForm("status")),
+	}
+
+	message := strings.TrimSpace(c.PostForm("message"))
+
+	services, err := adminServiceUpdatesFromForm(c)
+	if err != nil {
+		renderAdminIncidentNew(c, http.StatusBadRequest, incident, message, gin.H{"error": err.Error()})
+		return
+	}
+
+	incident.Services = services
+
+	if incident.Title == "" {
+		renderAdminIncidentNew(c, http.StatusBadRequest, incident, message, gin.H{"error": "title must be provided"})
+		return
+	}
+
+	status, ok := models.IncidentStatuses[strings.ToLower(incident.Status.String())]
+	if !ok || status == models.IncidentStatusScheduledMaintenance {
+		renderAdminIncidentNew(c, http.StatusBadRequest, incident, message, gin.H{"error": "invalid status value"})
+		return
+	}
+
+	incident.Status = status
+
+	if message != "" {
+		incident.Updates = []*models.StatusUpdate{{Time: incident.Time, Status: status, Message: message}}
+	}
+
+	if c.PostForm("action") == "preview" {
+		c.Set(middleware.AuditSkipKey, true)
+
+		tweet, err := core.PreviewIncident(incident)
+		if err != nil {
+			renderAdminIncidentNew(c, http.StatusBadRequest, incident, message, gin.H{"error": err.Error()})
+			return
+		}
+
+		renderAdminIncidentNew(c, http.StatusOK, incident, message, gin.H{"preview": incident, "tweet": tweet})
+		return
+	}
+
+	created, err := core.CreateIncident(incident, actorFromContext(c))
+	if err != nil {
+		renderAdminIncidentNew(c, http.StatusBadRequest, incident, message, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Set(middleware.AuditTargetIDKey, created.ID)
+	c.Redirect(http.StatusSeeOther, fmt.Sprintf("/admin/incidents/%d", created.ID))
+}
+
+// AdminIncidentHandler is the html controller for an incident and the form posting updates to it
+func AdminIncidentHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.AdminError(c, http.StatusNotFound, "incident not found")
+		return
+	}
+
+	renderAdminIncident(c, http.StatusOK, id, &models.StatusUpdate{}, gin.H{})
+}
+
+// AdminIncidentUpdateCreateHandler previews or posts the update of the form to the incident
+func AdminIncidentUpdateCreateHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.AdminError(c, http.StatusNotFound, "incident not found")
+		return
+	}
+
+	update, err := adminStatusUpdateFromForm(c)
+	if err != nil {
+		renderAdminIncident(c, http.StatusBadRequest, id, update, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.PostForm("action") == "preview" {
+		c.Set(middleware.AuditSkipKey, true)
+
+		incident, tweet, err := core.PreviewIncidentUpdate(id, update)
+		if err != nil {
+			renderAdminIncident(c, http.StatusBadRequest, id, update, gin.H{"error": err.Error()})
+			return
+		}
+
+		renderAdminIncident(c, http.StatusOK, id, update, gin.H{"preview": incident, "tweet": tweet})
+		return
+	}
+
+	if _, err := core.CreateIncidentUpdate(id, update, actorFromContext(c)); err != nil {
+		renderAdminIncident(c, http.StatusBadRequest, id, update, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, fmt.Sprintf("/admin/incidents/%d", id))
+}
+
+// AdminScheduledMaintenanceHandler is the html controller for a scheduled maintenance, with the forms
+// changing its window and posting updates to it
+func AdminScheduledMaintenanceHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.AdminError(c, http.StatusNotFound, "scheduled maintenance not found")
+		return
+	}
+
+	renderAdminScheduledMaintenance(c, http.StatusOK, id, &models.StatusUpdate{}, gin.H{})
+}
+
+// AdminScheduledMaintenancePatchHandler changes the title, description and window of the scheduled maintenance
+func AdminScheduledMaintenancePatchHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.AdminError(c, http.StatusNotFound, "scheduled maintenance not found")
+		return
+	}
+
+	maintenance := &models.ScheduledMaintenance{
+		ID:          id,
+		Title:       strings.TrimSpace(c.PostForm("title")),
+		Description: strings.TrimSpace(c.PostForm("description")),
+	}
+
+	maintenance.PlannedStart, err = time.ParseInLocation(adminTimeLayout, c.PostForm("plannedStart"), time.UTC)
+	if err != nil {
+		renderAdminScheduledMaintenance(c, http.StatusBadRequest, id, &models.StatusUpdate{}, gin.H{"error": "invalid planned start"})
+		return
+	}
+
+	maintenance.PlannedEnd, err = time.ParseInLocation(adminTimeLayout, c.PostForm("plannedEnd"), time.UTC)
+	if err != nil {
+		renderAdminScheduledMaintenance(c, http.StatusBadRequest, id, &models.StatusUpdate{}, gin.H{"error": "invalid planned end"})
+		return
+	}
+
+	if !maintenance.PlannedEnd.After(maintenance.PlannedStart) {
+		renderAdminScheduledMaintenance(c, http.StatusBadRequest, id, &models.StatusUpdate{}, gin.H{"error": "planned end must be after the planned start"})
+		return
+	}
+
+	// The form only has minutes, a time it didn't change keeps its seconds so it isn't taken for a new one
+	existingMaintenance, err := core.GetScheduledMaintenanceByID(id)
+	if err != nil {
+		adminErrorHandler(c, err)
+		return
+	}
+
+	if existingMaintenance == nil {
+		middleware.AdminError(c, http.StatusNotFound, "scheduled maintenance not found")
+		return
+	}
+
+	if maintenance.PlannedStart.Format(adminTimeLayout) == existingMaintenance.PlannedStart.UTC().Format(adminTimeLayout) {
+		maintenance.PlannedStart = existingMaintenance.PlannedStart
+	}
+
+	if maintenance.PlannedEnd.Format(adminTimeLayout) == existingMaintenance.PlannedEnd.UTC().Format(adminTimeLayout) {
+		maintenance.PlannedEnd = existingMaintenance.PlannedEnd
+	}
+
+	if _, err := core.PatchScheduledMaintenance(maintenance, c.PostForm("force") == "true", actorFromContext(c)); err != nil {
+		data := gin.H{"error": err.Error()}
+
+		// Keep the window which was asked for, so it can be saved despite the conflicts
+		var conflictErr *core.ScheduledMaintenanceConflictError
+		if errors.As(err, &conflictErr) {
+			data["conflicts"] = conflictErr.Conflicts
+			data["plannedStart"] = maintenance.PlannedStart.Format(adminTimeLayout)
+			data["plannedEnd"] = maintenance.PlannedEnd.Format(adminTimeLayout)
+		}
+
+		renderAdminScheduledMaintenance(c, http.StatusBadRequest, id, &models.StatusUpdate{}, data)
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, fmt.Sprintf("/admin/scheduled-maintenance/%d", id))
+}
+
+// AdminScheduledMaintenanceUpdateCreateHandler previews or posts the update of the form to the scheduled maintenance
+func AdminScheduledMaintenanceUpdateCreateHandler(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		middleware.AdminError(c, http.StatusNotFound, "scheduled maintenance not found")
+		return
+	}
+
+	update, err := adminStatusUpdateFromForm(c)
+	if err != nil {
+		renderAdminScheduledMaintenance(c, http.StatusBadRequest, id, update, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.PostForm("action") == "preview" {
+		c.Set(middleware.AuditSkipKey, true)
+
+		maintenance, tweet, err := core.PreviewScheduledMaintenanceUpdate(id, update)
+		if err != nil {
+			renderAdminScheduledMaintenance(c, http.StatusBadRequest, id, update, gin.H{"error": err.Error()})
+			return
+		}
+
+		renderAdminScheduledMaintenance(c, http.StatusOK, id, update, gin.H{"preview": maintenance, "tweet": tweet})
+		return
+	}
+
+	if _, err := core.CreateScheduledMaintenanceUpdate(id, update, actorFromContext(c)); err != nil {
+		renderAdminScheduledMaintenance(c, http.StatusBadRequest, id, update, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Redirect(http.StatusSeeOther, fmt.Sprintf("/admin/scheduled-maintenance/%d", id))
+}
+
+func renderAdminIncidentNew(c *gin.Context, status int, incident *models.Incident, message string, data gin.H) {
+	pickers, err := adminServicePickers(incident.Services)
+	if err != nil {
+		adminErrorHandler(c, err)
+		return
+	}
+
+	data["incident"] = incident
+	data["message"] = message
+	data["servicePickers"] = pickers
+
+	c.HTML(status, "adminIncidentNew.tmpl", adminData(c, data))
+}
+
+func renderAdminIncident(c *gin.Context, status int, id int, update *models.StatusUpdate, data gin.H) {
+	incident, err := core.GetIncidentByID(id)
+	if err != nil {
+		adminErrorHandler(c, err)
+		return
+	}
+
+	if incident == nil {
+		middleware.AdminError(c, http.StatusNotFound, "incident not found")
+		return
+	}
+
+	pickers, err := adminServicePickers(update.Services)
+	if err != nil {
+		adminErrorHandler(c, err)
+		return
+	}
+
+	data["incident"] = incident
+	data["update"] = update
+	data["servicePickers"] = pickers
+
+	c.HTML(status, "adminIncident.tmpl", adminData(c, data))
+}
+
+func renderAdminScheduledMaintenance(c *gin.Context, status int, id int, update *models.StatusUpdate, data gin.H) {
+	maintenance, err := core.GetScheduledMaintenanceByID(id)
+	if err != nil {
+		adminErrorHandler(c, err)
+		return
+	}
+
+	if maintenance == nil {
+		middleware.AdminError(c, http.StatusNotFound, "scheduled maintenance not found")
+		return
+	}
+
+	pickers, err := adminServicePickers(update.Services)
+	if err != nil {
+		adminErrorHandler(c, err)
+		return
+	}
+
+	data["scheduledMaintenance"] = maintenance
+	if _, ok := data["plannedStart"]; !ok {
+		data["plannedStart"] = maintenance.PlannedStart.UTC().Format(adminTimeLayout)
+		data["plannedEnd"] = maintenance.PlannedEnd.UTC().Format(adminTimeLayout)
+	}
+	data["update"] = update
+	data["servicePickers"] = pickers
+
+	c.HTML(status, "adminMaintenance.tmpl", adminData(c, data))
+}
+
+// adminData adds what every page of the admin ui needs to the data of the template
+func adminData(c *gin.Context, data gin.H) gin.H {
+	data["owner"] = config.Config.Website.Title
+	data["backgroundColor"] = config.Config.Website.HeaderBgColor
+	data["cacheBreaker"] = config.Config.Website.CacheBreaker
+	data["logo"] = "static/img/logo.svg"
+	data["actor"] = actorFromContext(c)
+	data["csrf"] = c.GetString(middleware.AdminCSRFKey)
+	data["incidentStatuses"] = adminIncidentStatuses
+	data["serviceStatuses"] = adminServiceStatuses
+
+	return data
+}
+
+func adminErrorHandler(c *gin.Context, err error) {
+	log.Println(err)
+
+	middleware.AdminError(c, http.StatusInternalServerError, "Something went wrong, please try again.")
+}
+
+// adminStatusUpdateFromForm reads the status update of the form, it's returned even when the services are invalid so the form can be filled again
+func adminStatusUpdateFromForm(c *gin.Context) (*models.StatusUpdate, error) {
+	update := &models.StatusUpdate{
+		Status:  models.IncidentStatus(c.PostForm("status")),
+		Message: strings.TrimSpace(c.PostForm("message")),
+	}
+
+	services, err := adminServiceUpdatesFromForm(c)
+	update.Services = services
+
+	if err != nil {
+		return update, err
+	}
+
+	if update.Message == "" {
+		return update, errors.New("message is missing")
+	}
+
+	status, ok := models.IncidentStatuses[strings.ToLower(update.Status.String())]
+	if !ok {
+		return update, errors.New("invalid status value")
+	}
+
+	update.Status = status
+
+	return update, nil
+}
+
+// adminServiceUpdatesFromForm reads the service status picker, services without a status are left out
+func adminServiceUpdatesFromForm(c *gin.Context) ([]models.ServiceUpdate, error) {
+	services, err := core.GetServices()
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make([]models.ServiceUpdate, 0)
+	for _, service := range services {
+		value := c.PostForm(fmt.Sprintf("service_%d", service.ID))
+		if value == "" {
+			continue
+		}
+
+		status, ok := models.ServiceStatuses[strings.ToLower(value)]
+		if !ok {
+			return updates, fmt.Errorf("invalid status of %s", service.Name)
+		}
+
+		updates = append(updates, models.ServiceUpdate{
+			Name:    service.Name,
+			Status:  status,
+			Regions: c.PostFormArray(fmt.Sprintf("regions_%d", service.ID)),
+		})
+	}
+
+	return updates, nil
+}
+
+// adminServicePickers gives the services and regions of the service status picker, with the ones of the updates picked
+func adminServicePickers(selected []models.ServiceUpdate) ([]adminServicePicker, error) {
+	services, err := core.GetServices()
+	if err != nil {
+		return nil, err
+	}
+
+	regions, err := core.GetRegions()
+	if err != nil {
+		return nil, err
+	}
+
+	pickers := make([]adminServicePicker, 0, len(services))
+	for _, service := range services {
+		picker := adminServicePicker{ID: service.ID, Name: service.Name}
+
+		var update *models.ServiceUpdate
+		for i := range selected {
+			if selected[i].Name == service.Name {
+				update = &selected[i]
+				picker.Status = update.Status
+			}
+		}
+
+		for _, region := range regions {
+			if region.ServiceID != service.ID {
+				continue
+			}
+
+			checked := false
+			if update != nil {
+				for _, code := range update.Regions {
+					checked = checked || code == region.RegionCode
+				}
+			}
+
+			picker.Regions = append(picker.Regions, adminRegionPicker{Code: region.RegionCode, Name: region.Name, Checked: checked})
+		}
+
+		pickers = append(pickers, picker)
+	}
+
+	return pickers, nil
+}
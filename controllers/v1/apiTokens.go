@@ -0,0 +1,129 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/gin-gonic/gin"
+)
+
+// APITokensGetAll gets all of the api tokens, their hashes are not returned
+// @Summary Gets list of api tokens
+// @ID tokens-getall
+// @Tags tokens
+// @Produce json
+// @Success 200 {object} []models.APIToken
+// @Router /v1/tokens [get]
+func APITokensGetAll(c *gin.Context) {
+	tokens, err := core.GetAPITokens()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	for _, token := range tokens {
+		token.Hash = ""
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// APITokenGetOne gets one api token by the provided id, the hash is not returned
+// @Summary Gets one api token
+// @ID tokens-getone
+// @Tags tokens
+// @Produce json
+// @Success 200 {object} models.APIToken
+// @Router /v1/tokens/{id} [get]
+func APITokenGetOne(c *gin.Context) {
+	id, err := apiTokenIDFromParam(c)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	token, err := core.GetAPITokenByID(id)
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	if token == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "token not found"})
+		return
+	}
+
+	token.Hash = ""
+
+	c.JSON(http.StatusOK, token)
+}
+
+// APITokenCreate creates an api token, the response is the only time the token is returned
+// @Summary Creates a new api token
+// @ID tokens-create
+// @Tags tokens
+// @Accept json
+// @Param token body models.APIToken true "Token object, only the name, scopes and expiresAt are used"
+// @Produce json
+// @Success 201 {object} models.APIToken
+// @Router /v1/tokens [post]
+func APITokenCreate(c *gin.Context) {
+	var token models.APIToken
+
+	if err := c.BindJSON(&token); err != nil {
+		return
+	}
+
+	created, err := core.CreateAPIToken(&token, actorFromContext(c))
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	created.Hash = ""
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// APITokenRevoke revokes an api token, it is kept so its use stays traceable
+// @Summary Revokes an api token
+// @ID tokens-revoke
+// @Tags tokens
+// @Param id path integer true "Token id"
+// @Produce json
+// @Success 200 {object} models.APIToken
+// @Router /v1/tokens/{id} [delete]
+func APITokenRevoke(c *gin.Context) {
+	id, err := apiTokenIDFromParam(c)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	token, err := core.RevokeAPIToken(id)
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	if token == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "token not found"})
+		return
+	}
+
+	token.Hash = ""
+
+	c.JSON(http.StatusOK, token)
+}
+
+func apiTokenIDFromParam(c *gin.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, errors.New("invalid token id passed")
+	}
+
+	return id, nil
+}
@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/gin-gonic/gin"
+)
+
+const defaultAuditLimit = 50
+
+// AuditGetAll gets the entries of the audit log, newest first
+// @Summary Gets list of audit log entries
+// @ID audit-getall
+// @Tags audit
+// @Param actor query string false "Only entries of this actor, like token:3"
+// @Param action query string false "Only entries of this action, like incident.create"
+// @Param targetType query string false "Only entries for this type of record"
+// @Param targetId query integer false "Only entries for the record with this id"
+// @Param from query string false "Only entries at or after this time, RFC 3339"
+// @Param to query string false "Only entries before this time, RFC 3339"
+// @Param limit query integer false "Maximum number of entries, defaults to 50"
+// @Produce json
+// @Success 200 {object} []models.AuditEntry
+// @Router /v1/audit [get]
+func AuditGetAll(c *gin.Context) {
+	filter, err := auditFilterFromQuery(c)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	entries, err := core.GetAuditEntries(filter)
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// AuditGetOne gets one entry of the audit log by the provided id
+// @Summary Gets one audit log entry
+// @ID audit-getone
+// @Tags audit
+// @Param id path integer true "Audit entry id"
+// @Produce json
+// @Success 200 {object} models.AuditEntry
+// @Router /v1/audit/{id} [get]
+func AuditGetOne(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequestHandlerDetailed(c, errors.New("invalid audit entry id passed"))
+		return
+	}
+
+	entry, err := core.GetAuditEntryByID(id)
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	if entry == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "audit entry not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+func auditFilterFromQuery(c *gin.Context) (models.AuditFilter, error) {
+	filter := models.AuditFilter{
+		Actor:      c.Query("actor"),
+		Action:     c.Query("action"),
+		TargetType: c.Query("targetType"),
+		Limit:      defaultAuditLimit,
+	}
+
+	if targetID := c.Query("targetId"); targetID != "" {
+		id, err := strconv.Atoi(targetID)
+		if err != nil {
+			return filter, errors.New("invalid targetId passed")
+		}
+
+		filter.TargetID = id
+	}
+
+	if from := c.Query("from"); from != "" {
+		t, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return filter, errors.New("invalid from passed, it must be in the RFC 3339 format")
+		}
+
+		filter.From = t
+	}
+
+	if to := c.Query("to"); to != "" {
+		t, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return filter, errors.New("invalid to passed, it must be in the RFC 3339 format")
+		}
+
+		filter.To = t
+	}
+
+	if limit := c.Query("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 1 {
+			return filter, errors.New("invalid limit passed")
+		}
+
+		filter.Limit = l
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,152 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/gin-gonic/gin"
+)
+
+const icsTimeFormat = "20060102T150405Z"
+
+// ScheduledMaintenanceCalendarHandler serves the scheduled maintenance windows as an iCalendar feed
+func ScheduledMaintenanceCalendarHandler(c *gin.Context) {
+	scheduledMaintenances, err := core.GetScheduledMaintenanceCalendar()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	renderCalendar(c, scheduledMaintenances)
+}
+
+// scheduledMaintenanceSingleCalendarHandler serves a single scheduled maintenance window as an iCalendar file
+func scheduledMaintenanceSingleCalendarHandler(c *gin.Context, id string) {
+	maintenanceID, err := strconv.Atoi(id)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	scheduledMaintenance, err := core.GetScheduledMaintenanceForCalendar(maintenanceID)
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	if scheduledMaintenance == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "scheduled maintenance not found"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="maintenance-%d.ics"`, scheduledMaintenance.ID))
+	renderCalendar(c, []*models.ScheduledMaintenance{scheduledMaintenance})
+}
+
+func renderCalendar(c *gin.Context, scheduledMaintenances []*models.ScheduledMaintenance) {
+	baseURL := feedBaseURL(c)
+
+	uidHost := "statuscentral"
+	if u, err := url.Parse(baseURL); err == nil && u.Hostname() != "" {
+		uidHost = u.Hostname()
+	}
+
+	b := &strings.Builder{}
+	writeICSLine(b, "BEGIN:VCALENDAR")
+	writeICSLine(b, "VERSION:2.0")
+	writeICSLine(b, "PRODID:-//Rocket.Chat//StatusCentral//EN")
+	writeICSLine(b, "CALSCALE:GREGORIAN")
+	writeICSLine(b, "METHOD:PUBLISH")
+	writeICSLine(b, "X-WR-CALNAME:"+escapeICSText(config.Config.Website.Title+" Scheduled Maintenance"))
+
+	for _, m := range scheduledMaintenances {
+		status := "CONFIRMED"
+		if isScheduledMaintenanceCancelled(m) {
+			status = "CANCELLED"
+		}
+
+		lastModified := m.UpdatedAt
+		if lastModified.IsZero() {
+			lastModified = m.CreatedAt
+		}
+
+		services := make([]string, 0, len(m.Services))
+		for _, s := range m.Services {
+			services = append(services, s.Name)
+		}
+
+		description := m.Description
+		if len(services) > 0 {
+			description = fmt.Sprintf("%s\n\nAffected services: %s", description, strings.Join(services, ", "))
+		}
+
+		writeICSLine(b, "BEGIN:VEVENT")
+		// The uid only depends on the id, so it stays the same no matter how often the maintenance changes
+		writeICSLine(b, fmt.Sprintf("UID:maintenance-%d@%s", m.ID, uidHost))
+		writeICSLine(b, "DTSTAMP:"+lastModified.UTC().Format(icsTimeFormat))
+		writeICSLine(b, "CREATED:"+m.CreatedAt.UTC().Format(icsTimeFormat))
+		writeICSLine(b, "LAST-MODIFIED:"+lastModified.UTC().Format(icsTimeFormat))
+		writeICSLine(b, "DTSTART:"+m.PlannedStart.UTC().Format(icsTimeFormat))
+		writeICSLine(b, "DTEND:"+m.PlannedEnd.UTC().Format(icsTimeFormat))
+		writeICSLine(b, "SEQUENCE:"+strconv.Itoa(m.Sequence))
+		writeICSLine(b, "STATUS:"+status)
+		writeICSLine(b, "SUMMARY:"+escapeICSText(m.Title))
+		writeICSLine(b, "DESCRIPTION:"+escapeICSText(description))
+		writeICSLine(b, fmt.Sprintf("URL:%s/scheduled-maintenance/%d", baseURL, m.ID))
+		writeICSLine(b, "TRANSP:TRANSPARENT")
+		writeICSLine(b, "END:VEVENT")
+	}
+
+	writeICSLine(b, "END:VCALENDAR")
+
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(b.String()))
+}
+
+// isScheduledMaintenanceCancelled tells whether the maintenance was deleted, cancelled or completed before its planned end
+func isScheduledMaintenanceCancelled(m *models.ScheduledMaintenance) bool {
+	if m.Cancelled {
+		return true
+	}
+
+	return m.Completed && !m.CompletedAt.IsZero() && m.CompletedAt.Before(m.PlannedEnd)
+}
+
+// writeICSLine writes the content line, folding it at 75 octets as required by RFC 5545
+func writeICSLine(b *strings.Builder, line string) {
+	// The leading space of the continuation lines counts towards the limit
+	limit := 75
+
+	for len(line) > limit {
+		cut := limit
+		// Don't split in the middle of a multi-byte character
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
+
+var icsTextEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	";", "\\;",
+	",", "\\,",
+	"\r\n", "\\n",
+	"\n", "\\n",
+)
+
+func escapeICSText(text string) string {
+	return icsTextEscaper.Replace(text)
+}
@@ -5,6 +5,8 @@ import (
 	"net/http"
 
 	"github.com/RocketChat/statuscentral/core"
+	"github.com/RocketChat/statuscentral/metrics"
+	"github.com/RocketChat/statuscentral/router/middleware"
 	"github.com/gin-gonic/gin"
 )
 
@@ -26,6 +28,11 @@ func internalErrorHandlerDetailed(c *gin.Context, err error) {
 	c.JSON(http.StatusInternalServerError, gin.H{"error": "internal error", "details": err.Error()})
 }
 
+// actorFromContext gets who made the request, as identified by the authorization middleware
+func actorFromContext(c *gin.Context) string {
+	return c.GetString(middleware.ActorKey)
+}
+
 // LivenessCheckHandler checks to see whether the database responds to a ping
 func LivenessCheckHandler(c *gin.Context) {
 	if err := core.LivenessCheck(); err != nil {
@@ -36,6 +43,16 @@ func LivenessCheckHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": http.StatusOK})
 }
 
+// MetricsHandler exports the metrics in the Prometheus text format
+func MetricsHandler(c *gin.Context) {
+	c.Header("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	if err := metrics.WriteTo(c.Writer); err != nil {
+		log.Println("Error while writing the metrics:", err)
+	}
+}
+
 // SnapshotHandler returns snapshot of database
 func SnapshotHandler(c *gin.Context) {
 	if err := core.DBSnapshot(c.Writer); err != nil {
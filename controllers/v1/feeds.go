@@ -0,0 +1,189 @@
+package v1
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/gin-gonic/gin"
+)
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	SelfLink      atomLink  `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle"`
+	ID       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Author   atomAuthor  `xml:"author"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published"`
+	Link      atomLink    `xml:"link"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// FeedRSSHandler serves the latest incident and scheduled maintenance updates as a RSS feed,
+// only those affecting the service when there is a name in the path
+func FeedRSSHandler(c *gin.Context) {
+	feed, ok := getFeed(c)
+	if !ok {
+		return
+	}
+
+	rss := rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         feed.Title,
+			Link:          feed.Link,
+			Description:   feed.Description,
+			LastBuildDate: feed.Updated.UTC().Format(time.RFC1123Z),
+			SelfLink:      atomLink{Href: feedSelfURL(c), Rel: "self", Type: "application/rss+xml"},
+			Items:         make([]rssItem, 0, len(feed.Entries)),
+		},
+	}
+
+	for _, entry := range feed.Entries {
+		rss.Channel.Items = append(rss.Channel.Items, rssItem{
+			Title:       entry.Title,
+			Link:        entry.Link,
+			GUID:        rssGUID{Value: entry.GUID},
+			PubDate:     entry.Published.UTC().Format(time.RFC1123Z),
+			Description: entry.Content,
+		})
+	}
+
+	renderFeed(c, "application/rss+xml; charset=utf-8", rss)
+}
+
+// FeedAtomHandler serves the latest incident and scheduled maintenance updates as an Atom feed,
+// only those affecting the service when there is a name in the path
+func FeedAtomHandler(c *gin.Context) {
+	feed, ok := getFeed(c)
+	if !ok {
+		return
+	}
+
+	atom := atomFeed{
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		ID:       feedSelfURL(c),
+		Updated:  feed.Updated.UTC().Format(time.RFC3339),
+		Author:   atomAuthor{Name: config.Config.Website.Title},
+		Links: []atomLink{
+			{Href: feed.Link, Rel: "alternate", Type: "text/html"},
+			{Href: feedSelfURL(c), Rel: "self", Type: "application/atom+xml"},
+		},
+		Entries: make([]atomEntry, 0, len(feed.Entries)),
+	}
+
+	for _, entry := range feed.Entries {
+		atom.Entries = append(atom.Entries, atomEntry{
+			Title:     entry.Title,
+			ID:        entry.GUID,
+			Updated:   entry.Published.UTC().Format(time.RFC3339),
+			Published: entry.Published.UTC().Format(time.RFC3339),
+			Link:      atomLink{Href: entry.Link, Rel: "alternate", Type: "text/html"},
+			Content:   atomContent{Type: "text", Value: entry.Content},
+		})
+	}
+
+	renderFeed(c, "application/atom+xml; charset=utf-8", atom)
+}
+
+func getFeed(c *gin.Context) (*models.Feed, bool) {
+	feed, err := core.GetFeed(feedBaseURL(c), c.Param("name"))
+	if err != nil {
+		internalErrorHandler(c, err)
+		return nil, false
+	}
+
+	if feed == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "service not found"})
+		return nil, false
+	}
+
+	return feed, true
+}
+
+func renderFeed(c *gin.Context, contentType string, feed interface{}) {
+	out, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, append([]byte(xml.Header), out...))
+}
+
+// feedBaseURL prefers the configured website url, as the feeds are often fetched through proxies
+func feedBaseURL(c *gin.Context) string {
+	if config.Config.Website.URL != "" {
+		return strings.TrimSuffix(config.Config.Website.URL, "/")
+	}
+
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+
+	return scheme + "://" + c.Request.Host
+}
+
+func feedSelfURL(c *gin.Context) string {
+	return feedBaseURL(c) + c.Request.URL.EscapedPath()
+}
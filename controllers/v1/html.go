@@ -5,6 +5,7 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/RocketChat/statuscentral/models"
 
@@ -13,6 +14,9 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// uptimeDaysToShow is how many days of uptime bars the index page shows
+const uptimeDaysToShow = 90
+
 // IndexHandler is the html controller for sending the html dashboard
 func IndexHandler(c *gin.Context) {
 	services, err := core.GetServicesEnabled()
@@ -55,6 +59,13 @@ func IndexHandler(c *gin.Context) {
 		}
 	}
 
+	// The page is still useful without the uptime bars, so don't fail on them
+	dailyStatuses, err := core.GetDailyStatusHistory(uptimeDaysToShow)
+	if err != nil {
+		log.Println("Error while getting the daily status history:")
+		log.Println(err)
+	}
+
 	c.HTML(http.StatusOK, "index.tmpl", gin.H{
 		"owner":                config.Config.Website.Title,
 		"backgroundColor":      config.Config.Website.HeaderBgColor,
@@ -64,6 +75,9 @@ func IndexHandler(c *gin.Context) {
 		"mostCriticalStatus":   core.MostCriticalServiceStatus(services, regions),
 		"incidents":            core.AggregateIncidents(incidents, true),
 		"scheduledMaintenance": core.AggregateScheduledMaintenance(scheduledMaintenance),
+		"subscriptionsEnabled": config.Config.Subscriptions.Enabled,
+		"dailyStatuses":        dailyStatuses,
+		"uptimeDaysToShow":     uptimeDaysToShow,
 	})
 }
 
@@ -184,6 +198,12 @@ func ScheduledMaintenanceShortRedirectHandler(c *gin.Context) {
 		return
 	}
 
+	// The router can't tell /m/:id and /m/:id.ics apart
+	if strings.HasSuffix(c.Param("id"), ".ics") {
+		scheduledMaintenanceSingleCalendarHandler(c, strings.TrimSuffix(c.Param("id"), ".ics"))
+		return
+	}
+
 	c.Redirect(http.StatusPermanentRedirect, fmt.Sprintf("/scheduled-maintenance/%s", c.Param("id")))
 }
 
@@ -0,0 +1,203 @@
+package v1
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/gin-gonic/gin"
+)
+
+// IncidentTemplatesGetAll gets all of the incident templates
+// @Summary Gets list of incident templates
+// @ID incident-templates-getall
+// @Tags incident-templates
+// @Produce json
+// @Success 200 {object} []models.IncidentTemplate
+// @Router /v1/incident-templates [get]
+func IncidentTemplatesGetAll(c *gin.Context) {
+	incidentTemplates, err := core.GetIncidentTemplates()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, incidentTemplates)
+}
+
+// IncidentTemplateGetOne gets one incident template by the provided id
+// @Summary Gets one incident template
+// @ID incident-template-getone
+// @Tags incident-templates
+// @Produce json
+// @Success 200 {object} models.IncidentTemplate
+// @Router /v1/incident-templates/{id} [get]
+func IncidentTemplateGetOne(c *gin.Context) {
+	incidentTemplate, ok := incidentTemplateFromParam(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, incidentTemplate)
+}
+
+// IncidentTemplateCreate creates an incident template
+// @Summary Creates a new incident template
+// @ID incident-template-create
+// @Tags incident-templates
+// @Accept json
+// @Param incidentTemplate body models.IncidentTemplate true "Incident template object"
+// @Produce json
+// @Success 201 {object} models.IncidentTemplate
+// @Router /v1/incident-templates [post]
+func IncidentTemplateCreate(c *gin.Context) {
+	var incidentTemplate models.IncidentTemplate
+
+	if err := c.BindJSON(&incidentTemplate); err != nil {
+		return
+	}
+
+	incidentTemplate.ID = 0
+
+	created, err := core.CreateIncidentTemplate(&incidentTemplate)
+	if errors.Is(err, core.ErrInvalidIncidentTemplate) {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// IncidentTemplateUpdate replaces an incident template
+// @Summary Updates an incident template
+// @ID incident-template-update
+// @Tags incident-templates
+// @Accept json
+// @Param incidentTemplate body models.IncidentTemplate true "Incident template object"
+// @Produce json
+// @Success 200 {object} models.IncidentTemplate
+// @Router /v1/incident-templates/{id} [post]
+func IncidentTemplateUpdate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequestHandlerDetailed(c, errors.New("invalid incident template id passed"))
+		return
+	}
+
+	var incidentTemplate models.IncidentTemplate
+
+	if err := c.BindJSON(&incidentTemplate); err != nil {
+		return
+	}
+
+	if id != incidentTemplate.ID {
+		badRequestHandlerDetailed(c, errors.New("invalid incident template id passed"))
+		return
+	}
+
+	updated, err := core.UpdateIncidentTemplate(&incidentTemplate)
+	if errors.Is(err, core.ErrInvalidIncidentTemplate) {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	if updated == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "incident template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// IncidentTemplateDelete removes an incident template
+// @Summary Deletes an incident template
+// @ID incident-template-delete
+// @Tags incident-templates
+// @Param id path integer true "Incident template id"
+// @Success 200
+// @Router /v1/incident-templates/{id} [delete]
+func IncidentTemplateDelete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequestHandlerDetailed(c, errors.New("invalid incident template id passed"))
+		return
+	}
+
+	if err := core.DeleteIncidentTemplate(id); err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// IncidentTemplateRender renders an incident template with the variables, giving the incident to create
+// @Summary Renders an incident template
+// @ID incident-template-render
+// @Tags incident-templates
+// @Accept json
+// @Param variables body models.IncidentTemplateRender false "Variables of the template"
+// @Produce json
+// @Success 200 {object} models.Incident
+// @Router /v1/incident-templates/{id}/render [post]
+func IncidentTemplateRender(c *gin.Context) {
+	incidentTemplate, ok := incidentTemplateFromParam(c)
+	if !ok {
+		return
+	}
+
+	// Templates without variables are rendered without a body
+	var render models.IncidentTemplateRender
+	if err := c.ShouldBindJSON(&render); err != nil && !errors.Is(err, io.EOF) {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	incident, err := core.RenderIncidentTemplate(incidentTemplate, render.Variables)
+	if errors.Is(err, core.ErrInvalidIncidentTemplate) {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+// incidentTemplateFromParam gets the incident template of the id in the path, responding when there is none
+func incidentTemplateFromParam(c *gin.Context) (*models.IncidentTemplate, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequestHandlerDetailed(c, errors.New("invalid incident template id passed"))
+		return nil, false
+	}
+
+	incidentTemplate, err := core.GetIncidentTemplateByID(id)
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return nil, false
+	}
+
+	if incidentTemplate == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "incident template not found"})
+		return nil, false
+	}
+
+	return incidentTemplate, true
+}
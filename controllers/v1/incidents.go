@@ -32,6 +32,13 @@ func IncidentsGetAll(c *gin.Context) {
 		return
 	}
 
+	// The list is public, drafts of postmortems aren't
+	for _, incident := range incidents {
+		if incident.Postmortem != nil && !incident.Postmortem.Published {
+			incident.Postmortem = nil
+		}
+	}
+
 	c.JSON(http.StatusOK, incidents)
 }
 
@@ -96,7 +103,7 @@ func IncidentCreate(c *gin.Context) {
 		return
 	}
 
-	inc, err := core.CreateIncident(&incident)
+	inc, err := core.CreateIncident(&incident, actorFromContext(c))
 	if err != nil {
 		internalErrorHandlerDetailed(c, err)
 		return
@@ -105,6 +112,53 @@ func IncidentCreate(c *gin.Context) {
 	c.JSON(http.StatusCreated, &inc)
 }
 
+// IncidentPatch changes the title, start time, affected services or postmortem link of the incident
+// @Summary Patches an incident
+// @ID incident-patch
+// @Tags incident
+// @Accept json
+// @Param incident body models.Incident true "Incident object with the fields to change"
+// @Produce json
+// @Success 200 {object} models.Incident
+// @Router /v1/incidents/{id} [patch]
+func IncidentPatch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequestHandlerDetailed(c, errors.New("invalid incident id passed"))
+		return
+	}
+
+	var patch models.Incident
+	if err := c.BindJSON(&patch); err != nil {
+		return
+	}
+
+	if patch.ID != 0 && patch.ID != id {
+		badRequestHandlerDetailed(c, errors.New("invalid incident id passed"))
+		return
+	}
+
+	patch.ID = id
+
+	incident, err := core.PatchIncident(&patch, actorFromContext(c))
+	if errors.Is(err, core.ErrInvalidIncidentPatch) {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	if incident == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "incident not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
 // IncidentDelete removes the service, ensuring the database is correct
 // @Summary Deletes an incidents
 // @ID incidents-delete
@@ -126,7 +180,7 @@ func IncidentDelete(c *gin.Context) {
 		return
 	}
 
-	if err := core.DeleteIncident(id); err != nil {
+	if err := core.DeleteIncident(id, actorFromContext(c)); err != nil {
 		internalErrorHandlerDetailed(c, err)
 		return
 	}
@@ -181,7 +235,7 @@ func IncidentUpdateCreate(c *gin.Context) {
 
 	update.Status = status
 
-	incident, err := core.CreateIncidentUpdate(id, &update)
+	incident, err := core.CreateIncidentUpdate(id, &update, actorFromContext(c))
 	if err != nil {
 		internalErrorHandlerDetailed(c, err)
 		return
@@ -302,3 +356,56 @@ func IncidentUpdateDelete(c *gin.Context) {
 
 	c.Status(http.StatusOK)
 }
+
+// IncidentUpdatePatch edits an update of an incident, keeping what it said before in its revisions
+// @Summary Edits one incident update
+// @ID incident-update-patch
+// @Tags incident-update
+// @Accept json
+// @Param update body models.StatusUpdate true "Status update with the fields to change"
+// @Produce json
+// @Success 200 {object} models.StatusUpdate
+// @Router /v1/incidents/{id}/updates/{updateId} [patch]
+func IncidentUpdatePatch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequestHandlerDetailed(c, errors.New("invalid incident id passed"))
+		return
+	}
+
+	updateID, err := strconv.Atoi(c.Param("updateId"))
+	if err != nil {
+		badRequestHandlerDetailed(c, errors.New("invalid update id passed"))
+		return
+	}
+
+	var edit models.StatusUpdate
+	if err := c.BindJSON(&edit); err != nil {
+		return
+	}
+
+	if edit.Status != "" {
+		if _, ok := models.IncidentStatuses[strings.ToLower(edit.Status.String())]; !ok {
+			badRequestHandlerDetailed(c, errors.New("invalid status value"))
+			return
+		}
+	}
+
+	update, err := core.EditIncidentUpdate(id, updateID, &edit, actorFromContext(c))
+	if errors.Is(err, core.ErrInvalidStatusUpdateEdit) {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	if update == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "incident update not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, update)
+}
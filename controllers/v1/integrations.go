@@ -0,0 +1,40 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/gin-gonic/gin"
+)
+
+// AlertmanagerWebhookReceive receives the notifications of Alertmanager
+// @Summary Opens, updates or resolves the incident of an Alertmanager alert group
+// @ID integrations-alertmanager
+// @Tags integrations
+// @Accept json
+// @Param webhook body models.AlertmanagerWebhook true "Alertmanager webhook payload"
+// @Produce json
+// @Success 200 {object} models.Incident
+// @Success 204
+// @Router /v1/integrations/alertmanager [post]
+func AlertmanagerWebhookReceive(c *gin.Context) {
+	var webhook models.AlertmanagerWebhook
+
+	if err := c.BindJSON(&webhook); err != nil {
+		return
+	}
+
+	incident, err := core.HandleAlertmanagerWebhook(&webhook)
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	if incident == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
@@ -0,0 +1,129 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/gin-gonic/gin"
+)
+
+// PostmortemGetOne gets the postmortem of the incident, published or a draft
+// @Summary Gets the postmortem of an incident
+// @ID incident-postmortem-getone
+// @Tags incident
+// @Param id path integer true "Incident id"
+// @Produce json
+// @Success 200 {object} models.Postmortem
+// @Router /v1/incidents/{id}/postmortem [get]
+func PostmortemGetOne(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequestHandlerDetailed(c, errors.New("invalid incident id passed"))
+		return
+	}
+
+	incident, err := core.GetIncidentByID(id)
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	if incident == nil || incident.Postmortem == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "postmortem not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, incident.Postmortem)
+}
+
+// PostmortemSave creates or replaces the postmortem of a resolved incident, it stays published or a draft as it was
+// @Summary Saves the postmortem of an incident
+// @ID incident-postmortem-save
+// @Tags incident
+// @Accept json
+// @Param id path integer true "Incident id"
+// @Param postmortem body models.Postmortem true "Postmortem object"
+// @Produce json
+// @Success 200 {object} models.Postmortem
+// @Router /v1/incidents/{id}/postmortem [post]
+func PostmortemSave(c *gin.Context) {
+	var postmortem models.Postmortem
+
+	if err := c.BindJSON(&postmortem); err != nil {
+		return
+	}
+
+	postmortemAction(c, func(id int) (*models.Incident, error) {
+		return core.SavePostmortem(id, &postmortem)
+	})
+}
+
+// PostmortemPublish shows the postmortem on the page of the incident, announcing it the first time
+// @Summary Publishes the postmortem of an incident
+// @ID incident-postmortem-publish
+// @Tags incident
+// @Param id path integer true "Incident id"
+// @Produce json
+// @Success 200 {object} models.Postmortem
+// @Router /v1/incidents/{id}/postmortem/publish [post]
+func PostmortemPublish(c *gin.Context) {
+	postmortemAction(c, core.PublishPostmortem)
+}
+
+// PostmortemUnpublish makes the postmortem of the incident a draft again
+// @Summary Unpublishes the postmortem of an incident
+// @ID incident-postmortem-unpublish
+// @Tags incident
+// @Param id path integer true "Incident id"
+// @Produce json
+// @Success 200 {object} models.Postmortem
+// @Router /v1/incidents/{id}/postmortem/unpublish [post]
+func PostmortemUnpublish(c *gin.Context) {
+	postmortemAction(c, core.UnpublishPostmortem)
+}
+
+// PostmortemDelete removes the postmortem of the incident
+// @Summary Deletes the postmortem of an incident
+// @ID incident-postmortem-delete
+// @Tags incident
+// @Param id path integer true "Incident id"
+// @Success 200
+// @Router /v1/incidents/{id}/postmortem [delete]
+func PostmortemDelete(c *gin.Context) {
+	postmortemAction(c, core.DeletePostmortem)
+}
+
+// postmortemAction runs the action on the postmortem of the incident in the path and responds with the postmortem
+func postmortemAction(c *gin.Context, action func(id int) (*models.Incident, error)) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequestHandlerDetailed(c, errors.New("invalid incident id passed"))
+		return
+	}
+
+	incident, err := action(id)
+	if errors.Is(err, core.ErrInvalidPostmortem) {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	if incident == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "incident not found"})
+		return
+	}
+
+	if incident.Postmortem == nil {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	c.JSON(http.StatusOK, incident.Postmortem)
+}
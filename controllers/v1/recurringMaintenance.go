@@ -0,0 +1,244 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/gin-gonic/gin"
+)
+
+// RecurringMaintenanceGetAll gets all of the recurring maintenance
+// @Summary Gets list of recurring maintenance
+// @ID recurring-maintenance-getall
+// @Tags recurring-maintenance
+// @Produce json
+// @Success 200 {object} []models.RecurringMaintenance
+// @Router /v1/recurring-maintenance [get]
+func RecurringMaintenanceGetAll(c *gin.Context) {
+	recurringMaintenances, err := core.GetRecurringMaintenances()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, recurringMaintenances)
+}
+
+// RecurringMaintenanceGetOne gets one recurring maintenance by the provided id
+// @Summary Gets one recurring maintenance
+// @ID recurring-maintenance-getone
+// @Tags recurring-maintenance
+// @Produce json
+// @Success 200 {object} models.RecurringMaintenance
+// @Router /v1/recurring-maintenance/{id} [get]
+func RecurringMaintenanceGetOne(c *gin.Context) {
+	id, err := recurringMaintenanceIDFromParam(c)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	recurringMaintenance, err := core.GetRecurringMaintenanceByID(id)
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	if recurringMaintenance == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "recurring maintenance not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, recurringMaintenance)
+}
+
+// RecurringMaintenanceCreate creates a recurring maintenance
+// @Summary Creates a new recurring maintenance
+// @ID recurring-maintenance-create
+// @Tags recurring-maintenance
+// @Accept json
+// @Param recurringMaintenance body models.RecurringMaintenance true "Recurring maintenance object"
+// @Produce json
+// @Success 201 {object} models.RecurringMaintenance
+// @Router /v1/recurring-maintenance [post]
+func RecurringMaintenanceCreate(c *gin.Context) {
+	var recurringMaintenance models.RecurringMaintenance
+
+	if err := c.BindJSON(&recurringMaintenance); err != nil {
+		return
+	}
+
+	created, err := core.CreateRecurringMaintenance(&recurringMaintenance)
+	if errors.Is(err, core.ErrInvalidRecurringMaintenance) {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// RecurringMaintenanceUpdate replaces the definition of a recurring maintenance, only the occurrences which weren't
+// scheduled yet change
+// @Summary Updates a recurring maintenance
+// @ID recurring-maintenance-update
+// @Tags recurring-maintenance
+// @Accept json
+// @Param recurringMaintenance body models.RecurringMaintenance true "Recurring maintenance object"
+// @Produce json
+// @Success 200 {object} models.RecurringMaintenance
+// @Router /v1/recurring-maintenance/{id} [post]
+func RecurringMaintenanceUpdate(c *gin.Context) {
+	id, err := recurringMaintenanceIDFromParam(c)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	var recurringMaintenance models.RecurringMaintenance
+
+	if err := c.BindJSON(&recurringMaintenance); err != nil {
+		return
+	}
+
+	if id != recurringMaintenance.ID {
+		badRequestHandlerDetailed(c, errors.New("invalid recurring maintenance id passed"))
+		return
+	}
+
+	updated, err := core.UpdateRecurringMaintenance(&recurringMaintenance)
+	if errors.Is(err, core.ErrInvalidRecurringMaintenance) {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	if updated == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "recurring maintenance not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// RecurringMaintenanceDelete removes a recurring maintenance, the occurrences already scheduled stay
+// @Summary Deletes a recurring maintenance
+// @ID recurring-maintenance-delete
+// @Tags recurring-maintenance
+// @Param id path integer true "Recurring maintenance id"
+// @Success 200
+// @Router /v1/recurring-maintenance/{id} [delete]
+func RecurringMaintenanceDelete(c *gin.Context) {
+	id, err := recurringMaintenanceIDFromParam(c)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	if err := core.DeleteRecurringMaintenance(id); err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// RecurringMaintenanceOccurrencesGetAll gets the next occurrences of a recurring maintenance, "?count=" defaults to 10
+// @Summary Gets the next occurrences of a recurring maintenance
+// @ID recurring-maintenance-occurrences-getall
+// @Tags recurring-maintenance
+// @Param id path integer true "Recurring maintenance id"
+// @Produce json
+// @Success 200 {object} []models.RecurringMaintenanceOccurrence
+// @Router /v1/recurring-maintenance/{id}/occurrences [get]
+func RecurringMaintenanceOccurrencesGetAll(c *gin.Context) {
+	id, err := recurringMaintenanceIDFromParam(c)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	count, err := strconv.Atoi(c.Query("count"))
+	if err != nil || count <= 0 || count > 100 {
+		count = 10
+	}
+
+	occurrences, err := core.GetRecurringMaintenanceOccurrences(id, count)
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	if occurrences == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "recurring maintenance not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, occurrences)
+}
+
+// RecurringMaintenanceExceptionSet skips or changes a single occurrence of a recurring maintenance
+// @Summary Skips or changes an occurrence of a recurring maintenance
+// @ID recurring-maintenance-exception-set
+// @Tags recurring-maintenance
+// @Accept json
+// @Param exception body models.RecurringMaintenanceException true "Exception of the occurrence"
+// @Produce json
+// @Success 200 {object} models.RecurringMaintenance
+// @Router /v1/recurring-maintenance/{id}/exceptions [post]
+func RecurringMaintenanceExceptionSet(c *gin.Context) {
+	id, err := recurringMaintenanceIDFromParam(c)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	var exception models.RecurringMaintenanceException
+
+	if err := c.BindJSON(&exception); err != nil {
+		return
+	}
+
+	if exception.Occurrence.IsZero() {
+		badRequestHandlerDetailed(c, errors.New("occurrence is missing"))
+		return
+	}
+
+	recurringMaintenance, err := core.SetRecurringMaintenanceException(id, exception, actorFromContext(c))
+	if errors.Is(err, core.ErrInvalidRecurringMaintenance) {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	if recurringMaintenance == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "recurring maintenance not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, recurringMaintenance)
+}
+
+func recurringMaintenanceIDFromParam(c *gin.Context) (int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, errors.New("invalid recurring maintenance id passed")
+	}
+
+	return id, nil
+}
@@ -2,9 +2,12 @@ package v1
 
 import (
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/RocketChat/statuscentral/core"
 	"github.com/RocketChat/statuscentral/models"
@@ -76,8 +79,10 @@ func ScheduledMaintenanceGetOne(c *gin.Context) {
 // @Tags scheduled-maintenance
 // @Accept json
 // @Param region body models.ScheduledMaintenance true "Scheduled Maintenance object"
+// @Param force query bool false "Create it even when it conflicts with other maintenance or open incidents"
 // @Produce json
 // @Success 200 {object} models.ScheduledMaintenance
+// @Failure 409 {object} []models.MaintenanceConflict
 // @Router /v1/scheduled-maintenance [post]
 func ScheduledMaintenanceCreate(c *gin.Context) {
 	var scheduledMaintenance models.ScheduledMaintenance
@@ -101,13 +106,13 @@ func ScheduledMaintenanceCreate(c *gin.Context) {
 		return
 	}
 
-	maint, err := core.CreateScheduledMaintenance(&scheduledMaintenance)
+	maint, conflicts, err := core.CreateScheduledMaintenance(&scheduledMaintenance, c.Query("force") == "true")
 	if err != nil {
-		internalErrorHandlerDetailed(c, err)
+		scheduledMaintenanceErrorHandler(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, &maint)
+	c.JSON(http.StatusCreated, scheduledMaintenanceResponse{maint, conflicts})
 }
 
 // ScheduledMaintenancePatch patches a scheduled maintenance, ensuring the database is correct
@@ -116,8 +121,10 @@ func ScheduledMaintenanceCreate(c *gin.Context) {
 // @Tags scheduled-maintenance
 // @Accept json
 // @Param region body models.ScheduledMaintenance true "Scheduled Maintenance object"
+// @Param force query bool false "Move it even when the new window conflicts with other maintenance or open incidents"
 // @Produce json
 // @Success 200 {object} models.ScheduledMaintenance
+// @Failure 409 {object} []models.MaintenanceConflict
 // @Router /v1/scheduled-maintenance [patch]
 func ScheduledMaintenancePatch(c *gin.Context) {
 	idParam := c.Param("id")
@@ -144,12 +151,35 @@ func ScheduledMaintenancePatch(c *gin.Context) {
 		return
 	}
 
-	if err := core.PatchScheduledMaintenance(&maintenance); err != nil {
-		internalErrorHandler(c, err)
+	conflicts, err := core.PatchScheduledMaintenance(&maintenance, c.Query("force") == "true", actorFromContext(c))
+	if err != nil {
+		scheduledMaintenanceErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, scheduledMaintenanceResponse{&maintenance, conflicts})
+}
+
+// scheduledMaintenanceResponse is the scheduled maintenance with the conflicts it was forced through, as warnings
+type scheduledMaintenanceResponse struct {
+	*models.ScheduledMaintenance
+	Conflicts []models.MaintenanceConflict `json:"conflicts,omitempty"`
+}
+
+// scheduledMaintenanceErrorHandler responds with the conflicts when the maintenance conflicts and wasn't forced
+func scheduledMaintenanceErrorHandler(c *gin.Context, err error) {
+	var conflictErr *core.ScheduledMaintenanceConflictError
+	if errors.As(err, &conflictErr) {
+		c.JSON(http.StatusConflict, gin.H{"error": "conflict", "details": err.Error(), "conflicts": conflictErr.Conflicts})
+		return
+	}
+
+	if errors.Is(err, core.ErrInvalidScheduledMaintenance) {
+		badRequestHandlerDetailed(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, maintenance)
+	internalErrorHandlerDetailed(c, err)
 }
 
 // ScheduledMaintenanceDelete removes the scheduled maintenance, ensuring the database is correct
@@ -173,7 +203,7 @@ func ScheduledMaintenanceDelete(c *gin.Context) {
 		return
 	}
 
-	if err := core.DeleteScheduledMaintenance(id); err != nil {
+	if err := core.DeleteScheduledMaintenance(id, actorFromContext(c)); err != nil {
 		internalErrorHandlerDetailed(c, err)
 		return
 	}
@@ -228,7 +258,12 @@ func ScheduledMaintenanceUpdateCreate(c *gin.Context) {
 
 	update.Status = status
 
-	maint, err := core.CreateScheduledMaintenanceUpdate(id, &update)
+	maint, err := core.CreateScheduledMaintenanceUpdate(id, &update, actorFromContext(c))
+	if errors.Is(err, core.ErrInvalidScheduledMaintenanceAction) {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
 	if err != nil {
 		internalErrorHandlerDetailed(c, err)
 		return
@@ -349,3 +384,145 @@ func ScheduledMaintenanceUpdateDelete(c *gin.Context) {
 
 	c.Status(http.StatusOK)
 }
+
+// ScheduledMaintenanceUpdatePatch edits an update of a scheduled maintenance, keeping what it said before in its revisions
+// @Summary Edits one scheduled maintenance update
+// @ID scheduled-maintenance-update-patch
+// @Tags scheduled-maintenance-update
+// @Accept json
+// @Param update body models.StatusUpdate true "Status update with the fields to change"
+// @Produce json
+// @Success 200 {object} models.StatusUpdate
+// @Router /v1/scheduled-maintenance/{id}/updates/{updateId} [patch]
+func ScheduledMaintenanceUpdatePatch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequestHandlerDetailed(c, errors.New("invalid scheduled maintenance id passed"))
+		return
+	}
+
+	updateID, err := strconv.Atoi(c.Param("updateId"))
+	if err != nil {
+		badRequestHandlerDetailed(c, errors.New("invalid update id passed"))
+		return
+	}
+
+	var edit models.StatusUpdate
+	if err := c.BindJSON(&edit); err != nil {
+		return
+	}
+
+	if edit.Status != "" {
+		if _, ok := models.IncidentStatuses[strings.ToLower(edit.Status.String())]; !ok {
+			badRequestHandlerDetailed(c, errors.New("invalid status value"))
+			return
+		}
+	}
+
+	update, err := core.EditScheduledMaintenanceUpdate(id, updateID, &edit, actorFromContext(c))
+	if errors.Is(err, core.ErrInvalidStatusUpdateEdit) {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	if update == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "scheduled maintenance update not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, update)
+}
+
+// scheduledMaintenanceActionRequest is the body of extending, starting early or cancelling a scheduled maintenance,
+// the message is optional and the planned end is only used when extending
+type scheduledMaintenanceActionRequest struct {
+	PlannedEnd time.Time `json:"plannedEnd"`
+	Message    string    `json:"message"`
+}
+
+// ScheduledMaintenanceExtend pushes back the planned end of a scheduled maintenance in progress
+// @Summary Extends a scheduled maintenance in progress
+// @ID scheduled-maintenance-extend
+// @Tags scheduled-maintenance
+// @Accept json
+// @Param request body scheduledMaintenanceActionRequest true "New planned end and optional message"
+// @Produce json
+// @Success 200 {object} models.ScheduledMaintenance
+// @Router /v1/scheduled-maintenance/{id}/extend [post]
+func ScheduledMaintenanceExtend(c *gin.Context) {
+	scheduledMaintenanceAction(c, func(id int, request scheduledMaintenanceActionRequest, actor string) (*models.ScheduledMaintenance, error) {
+		if request.PlannedEnd.IsZero() {
+			return nil, fmt.Errorf("%w: plannedEnd is missing", core.ErrInvalidScheduledMaintenanceAction)
+		}
+
+		return core.ExtendScheduledMaintenance(id, request.PlannedEnd, request.Message, actor)
+	})
+}
+
+// ScheduledMaintenanceStart starts a scheduled maintenance before its planned start
+// @Summary Starts a scheduled maintenance early
+// @ID scheduled-maintenance-start
+// @Tags scheduled-maintenance
+// @Accept json
+// @Param request body scheduledMaintenanceActionRequest false "Optional message"
+// @Produce json
+// @Success 200 {object} models.ScheduledMaintenance
+// @Router /v1/scheduled-maintenance/{id}/start [post]
+func ScheduledMaintenanceStart(c *gin.Context) {
+	scheduledMaintenanceAction(c, func(id int, request scheduledMaintenanceActionRequest, actor string) (*models.ScheduledMaintenance, error) {
+		return core.StartScheduledMaintenanceEarly(id, request.Message, actor)
+	})
+}
+
+// ScheduledMaintenanceCancel cancels a scheduled maintenance, which stays on the status page marked as cancelled
+// @Summary Cancels a scheduled maintenance
+// @ID scheduled-maintenance-cancel
+// @Tags scheduled-maintenance
+// @Accept json
+// @Param request body scheduledMaintenanceActionRequest false "Optional message"
+// @Produce json
+// @Success 200 {object} models.ScheduledMaintenance
+// @Router /v1/scheduled-maintenance/{id}/cancel [post]
+func ScheduledMaintenanceCancel(c *gin.Context) {
+	scheduledMaintenanceAction(c, func(id int, request scheduledMaintenanceActionRequest, actor string) (*models.ScheduledMaintenance, error) {
+		return core.CancelScheduledMaintenance(id, request.Message, actor)
+	})
+}
+
+func scheduledMaintenanceAction(c *gin.Context, action func(id int, request scheduledMaintenanceActionRequest, actor string) (*models.ScheduledMaintenance, error)) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequestHandlerDetailed(c, errors.New("invalid scheduled maintenance id passed"))
+		return
+	}
+
+	// The body is optional unless extending
+	var request scheduledMaintenanceActionRequest
+	if err := c.ShouldBindJSON(&request); err != nil && !errors.Is(err, io.EOF) {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	scheduledMaintenance, err := action(id, request, actorFromContext(c))
+	if errors.Is(err, core.ErrInvalidScheduledMaintenanceAction) {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	if scheduledMaintenance == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "scheduled maintenance not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, scheduledMaintenance)
+}
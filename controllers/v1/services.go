@@ -115,10 +115,46 @@ func ServiceUpdate(c *gin.Context) {
 		return
 	}
 
-	if err := core.UpdateService(&service); err != nil {
+	if err := core.UpdateService(&service, actorFromContext(c)); err != nil {
 		internalErrorHandler(c, err)
 		return
 	}
 
 	c.JSON(http.StatusOK, service)
 }
+
+// ServiceHistoryGetAll gets the status history of one of the services
+// @Summary Gets the status changes of a service and its regions, newest first
+// @ID services-history-getall
+// @Tags services
+// @Param limit query int false "Number of changes to return, defaults to 25"
+// @Param offset query int false "Number of changes to skip"
+// @Produce json
+// @Success 200 {object} []models.StatusChange
+// @Router /v1/services/{id}/history [get]
+func ServiceHistoryGetAll(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequestHandlerDetailed(c, errors.New("invalid service id passed"))
+		return
+	}
+
+	pagination := getPaginationFromQuery(c)
+	if pagination.Limit <= 0 || pagination.Offset < 0 {
+		badRequestHandlerDetailed(c, errors.New("invalid limit or offset passed"))
+		return
+	}
+
+	history, err := core.GetServiceStatusHistory(id, pagination)
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	if history == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "service not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
@@ -0,0 +1,194 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/RocketChat/statuscentral/router/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+type subscribeRequest struct {
+	Email    string                     `json:"email"`
+	Services []models.SubscribedService `json:"services"`
+}
+
+// SubscriberCreate subscribes an email address to notifications, sending the confirmation email
+// @Summary Subscribes to email notifications
+// @ID subscriber-create
+// @Tags subscriber
+// @Accept json
+// @Param subscriber body subscribeRequest true "Subscription request"
+// @Produce json
+// @Success 202
+// @Router /v1/subscribers [post]
+func SubscriberCreate(c *gin.Context) {
+	isForm := c.ContentType() == binding.MIMEPOSTForm
+
+	var request subscribeRequest
+
+	if isForm {
+		request.Email = c.PostForm("email")
+		request.Services = subscribedServicesFromForm(c.PostFormArray("services"))
+	} else if err := c.BindJSON(&request); err != nil {
+		return
+	}
+
+	if request.Email == "" {
+		subscriptionErrorHandler(c, isForm, errors.New("email must be provided"))
+		return
+	}
+
+	subscriber, err := core.Subscribe(request.Email, request.Services, c.ClientIP())
+	if err != nil {
+		subscriptionErrorHandler(c, isForm, err)
+		return
+	}
+
+	c.Set(middleware.AuditTargetIDKey, subscriber.ID)
+
+	if isForm {
+		renderSubscriptionPage(c, http.StatusAccepted, "Almost there! Check your inbox for an email to confirm your subscription.")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "confirmation email sent"})
+}
+
+// SubscriberConfirm confirms a subscription using the token sent by email
+// @Summary Confirms an email subscription
+// @ID subscriber-confirm
+// @Tags subscriber
+// @Param token query string true "Confirmation token"
+// @Produce html
+// @Success 200
+// @Router /v1/subscribers/confirm [get]
+func SubscriberConfirm(c *gin.Context) {
+	if _, err := core.ConfirmSubscription(c.Query("token")); err != nil {
+		subscriptionErrorHandler(c, true, err)
+		return
+	}
+
+	renderSubscriptionPage(c, http.StatusOK, "Your subscription is confirmed. You will now receive email notifications.")
+}
+
+// SubscriberUnsubscribe removes a subscription using the token sent by email
+// @Summary Unsubscribes from email notifications
+// @ID subscriber-unsubscribe
+// @Tags subscriber
+// @Param token query string true "Unsubscribe token"
+// @Produce html
+// @Success 200
+// @Router /v1/subscribers/unsubscribe [get]
+func SubscriberUnsubscribe(c *gin.Context) {
+	if err := core.Unsubscribe(c.Query("token")); err != nil {
+		subscriptionErrorHandler(c, true, err)
+		return
+	}
+
+	renderSubscriptionPage(c, http.StatusOK, "You have been unsubscribed and will no longer receive email notifications.")
+}
+
+// SubscribersGetAll gets all of the subscribers
+// @Summary Gets list of subscribers
+// @ID subscribers-getall
+// @Tags subscriber
+// @Produce json
+// @Success 200 {object} []models.Subscriber
+// @Router /v1/subscribers [get]
+func SubscribersGetAll(c *gin.Context) {
+	subscribers, err := core.GetSubscribers()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, subscribers)
+}
+
+// SubscriberDelete removes a subscriber
+// @Summary Deletes a subscriber
+// @ID subscriber-delete
+// @Tags subscriber
+// @Param id path integer true "Subscriber id"
+// @Success 200
+// @Router /v1/subscribers/{id} [delete]
+func SubscriberDelete(c *gin.Context) {
+	idParam := c.Param("id")
+
+	if idParam == "" {
+		badRequestHandlerDetailed(c, errors.New("invalid subscriber id passed"))
+		return
+	}
+
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	if err := core.DeleteSubscriber(id); err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// subscribedServicesFromForm converts the form values, either "Service" or "Service|RegionCode", into a selection
+func subscribedServicesFromForm(values []string) []models.SubscribedService {
+	services := make([]models.SubscribedService, 0)
+	indexes := map[string]int{}
+
+	for _, value := range values {
+		parts := strings.SplitN(value, "|", 2)
+
+		i, ok := indexes[parts[0]]
+		if !ok {
+			services = append(services, models.SubscribedService{Name: parts[0]})
+			i = len(services) - 1
+			indexes[parts[0]] = i
+		}
+
+		if len(parts) == 2 {
+			services[i].Regions = append(services[i].Regions, parts[1])
+		}
+	}
+
+	return services
+}
+
+func subscriptionErrorHandler(c *gin.Context, html bool, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, core.ErrTooManySubscriptionRequests) {
+		status = http.StatusTooManyRequests
+	}
+
+	if !html {
+		if status == http.StatusTooManyRequests {
+			c.JSON(status, gin.H{"error": "too many requests", "details": err.Error()})
+			return
+		}
+
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	renderSubscriptionPage(c, status, "We could not process your request: "+err.Error())
+}
+
+func renderSubscriptionPage(c *gin.Context, status int, message string) {
+	c.HTML(status, "subscription.tmpl", gin.H{
+		"owner":           config.Config.Website.Title,
+		"backgroundColor": config.Config.Website.HeaderBgColor,
+		"cacheBreaker":    config.Config.Website.CacheBreaker,
+		"logo":            "static/img/logo.svg",
+		"message":         message,
+	})
+}
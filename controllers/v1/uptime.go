@@ -0,0 +1,133 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/gin-gonic/gin"
+)
+
+const uptimeDefaultWindow = 30 * 24 * time.Hour
+
+// ServicesUptimeGetAll gets the uptime of all the services
+// @Summary Gets the uptime of all the services and their regions
+// @ID services-uptime-getall
+// @Tags services
+// @Param month query string false "Month to calculate, as YYYY-MM"
+// @Param from query string false "Start of the window, RFC3339"
+// @Param to query string false "End of the window, RFC3339"
+// @Param excludeMaintenance query bool false "Leave scheduled maintenance out of the calculation"
+// @Produce json
+// @Success 200 {object} []models.Uptime
+// @Router /v1/uptime [get]
+func ServicesUptimeGetAll(c *gin.Context) {
+	from, to, excludeMaintenance, err := uptimeWindowFromQuery(c)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	uptimes, err := core.GetServicesUptime(from, to, excludeMaintenance)
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, uptimes)
+}
+
+// ServiceUptimeGetOne gets the uptime of one of the services
+// @Summary Gets the uptime of a service and its regions
+// @ID services-uptime-getone
+// @Tags services
+// @Param month query string false "Month to calculate, as YYYY-MM"
+// @Param from query string false "Start of the window, RFC3339"
+// @Param to query string false "End of the window, RFC3339"
+// @Param excludeMaintenance query bool false "Leave scheduled maintenance out of the calculation"
+// @Produce json
+// @Success 200 {object} models.Uptime
+// @Router /v1/services/{id}/uptime [get]
+func ServiceUptimeGetOne(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		badRequestHandlerDetailed(c, errors.New("invalid service id passed"))
+		return
+	}
+
+	from, to, excludeMaintenance, err := uptimeWindowFromQuery(c)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	uptime, err := core.GetServiceUptime(id, from, to, excludeMaintenance)
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	if uptime == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "service not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, uptime)
+}
+
+// uptimeWindowFromQuery reads the window to calculate the uptime over, either a whole month
+// or from and to. The window defaults to the last 30 days.
+func uptimeWindowFromQuery(c *gin.Context) (time.Time, time.Time, bool, error) {
+	var from, to time.Time
+
+	excludeMaintenance := false
+	if value := c.Query("excludeMaintenance"); value != "" {
+		exclude, err := strconv.ParseBool(value)
+		if err != nil {
+			return from, to, false, errors.New("excludeMaintenance must be true or false")
+		}
+
+		excludeMaintenance = exclude
+	}
+
+	if month := c.Query("month"); month != "" {
+		start, err := time.Parse("2006-01", month)
+		if err != nil {
+			return from, to, false, errors.New("month must be formatted as YYYY-MM")
+		}
+
+		from, to = start, start.AddDate(0, 1, 0)
+	} else {
+		to = time.Now()
+		if value := c.Query("to"); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return from, to, false, errors.New("to must be a RFC3339 time")
+			}
+
+			to = t
+		}
+
+		from = to.Add(-uptimeDefaultWindow)
+		if value := c.Query("from"); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return from, to, false, errors.New("from must be a RFC3339 time")
+			}
+
+			from = t
+		}
+	}
+
+	if !from.Before(to) {
+		return from, to, false, errors.New("from must be before to")
+	}
+
+	if !from.Before(time.Now()) {
+		return from, to, false, errors.New("the window can't start in the future")
+	}
+
+	return from, to, excludeMaintenance, nil
+}
@@ -0,0 +1,190 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhooksGetAll gets all of the webhooks, secrets are not returned
+// @Summary Gets list of webhooks
+// @ID webhooks-getall
+// @Tags webhook
+// @Produce json
+// @Success 200 {object} []models.Webhook
+// @Router /v1/webhooks [get]
+func WebhooksGetAll(c *gin.Context) {
+	webhooks, err := core.GetWebhooks()
+	if err != nil {
+		internalErrorHandler(c, err)
+		return
+	}
+
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+// WebhookGetOne gets one webhook by the provided id, the secret is not returned
+// @Summary Gets one webhook
+// @ID webhooks-getone
+// @Tags webhook
+// @Produce json
+// @Success 200 {object} models.Webhook
+// @Router /v1/webhooks/{id} [get]
+func WebhookGetOne(c *gin.Context) {
+	id, err := webhookIDFromParam(c)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	webhook, err := core.GetWebhookByID(id)
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	if webhook == nil {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	webhook.Secret = ""
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// WebhookCreate creates a webhook, the response is the only time the secret is returned
+// @Summary Creates a new webhook
+// @ID webhook-create
+// @Tags webhook
+// @Accept json
+// @Param webhook body models.Webhook true "Webhook object"
+// @Produce json
+// @Success 201 {object} models.Webhook
+// @Router /v1/webhooks [post]
+func WebhookCreate(c *gin.Context) {
+	var webhook models.Webhook
+
+	if err := c.BindJSON(&webhook); err != nil {
+		return
+	}
+
+	created, err := core.CreateWebhook(&webhook)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// WebhookPatch patches a webhook
+// @Summary Patches a webhook
+// @ID webhook-patch
+// @Tags webhook
+// @Accept json
+// @Param webhook body models.Webhook true "Webhook object"
+// @Produce json
+// @Success 200 {object} models.Webhook
+// @Router /v1/webhooks/{id} [patch]
+func WebhookPatch(c *gin.Context) {
+	id, err := webhookIDFromParam(c)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	var webhook models.Webhook
+
+	if err := c.BindJSON(&webhook); err != nil {
+		return
+	}
+
+	if id != webhook.ID {
+		badRequestHandlerDetailed(c, errors.New("invalid webhook id passed"))
+		return
+	}
+
+	if err := core.PatchWebhook(&webhook); err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	webhook.Secret = ""
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// WebhookDelete removes a webhook
+// @Summary Deletes a webhook
+// @ID webhook-delete
+// @Tags webhook
+// @Param id path integer true "Webhook id"
+// @Success 200
+// @Router /v1/webhooks/{id} [delete]
+func WebhookDelete(c *gin.Context) {
+	id, err := webhookIDFromParam(c)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	if err := core.DeleteWebhook(id); err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// WebhookDeliveriesGetAll gets the latest deliveries of a webhook, "?limit=" defaults to 25
+// @Summary Gets the deliveries of a webhook
+// @ID webhook-deliveries-getall
+// @Tags webhook
+// @Param id path integer true "Webhook id"
+// @Produce json
+// @Success 200 {object} []models.WebhookDelivery
+// @Router /v1/webhooks/{id}/deliveries [get]
+func WebhookDeliveriesGetAll(c *gin.Context) {
+	id, err := webhookIDFromParam(c)
+	if err != nil {
+		badRequestHandlerDetailed(c, err)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil {
+		limit = 25
+	}
+
+	deliveries, err := core.GetWebhookDeliveries(id, limit)
+	if err != nil {
+		internalErrorHandlerDetailed(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func webhookIDFromParam(c *gin.Context) (int, error) {
+	idParam := c.Param("id")
+
+	if idParam == "" {
+		return 0, errors.New("invalid webhook id passed")
+	}
+
+	id, err := strconv.Atoi(idParam)
+	if err != nil {
+		return 0, errors.New("invalid webhook id passed")
+	}
+
+	return id, nil
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// AdminSessionDuration is how long the admin ui stays signed in
+const AdminSessionDuration = 12 * time.Hour
+
+// adminSession is a sign in to the admin ui, its token is checked again on every request so revoking it signs out
+type adminSession struct {
+	token     string
+	expiresAt time.Time
+}
+
+// The sessions are only kept in memory, restarting signs everyone out of the admin ui
+var (
+	adminSessionsLock sync.Mutex
+	adminSessions     = make(map[string]adminSession)
+)
+
+// CreateAdminSession signs in to the admin ui with the token and returns the id of the session, which is all
+// the browser gets to see
+func CreateAdminSession(token string) (string, error) {
+	id, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+
+	adminSessionsLock.Lock()
+	defer adminSessionsLock.Unlock()
+
+	now := time.Now()
+	for sessionID, session := range adminSessions {
+		if !now.Before(session.expiresAt) {
+			delete(adminSessions, sessionID)
+		}
+	}
+
+	adminSessions[id] = adminSession{token: token, expiresAt: now.Add(AdminSessionDuration)}
+
+	return id, nil
+}
+
+// GetAdminSessionToken gives the token the session signed in with, it's false when the session doesn't exist or expired
+func GetAdminSessionToken(id string) (string, bool) {
+	adminSessionsLock.Lock()
+	defer adminSessionsLock.Unlock()
+
+	session, ok := adminSessions[id]
+	if !ok {
+		return "", false
+	}
+
+	if !time.Now().Before(session.expiresAt) {
+		delete(adminSessions, id)
+		return "", false
+	}
+
+	return session.token, true
+}
+
+// DeleteAdminSession signs the session out of the admin ui
+func DeleteAdminSession(id string) {
+	adminSessionsLock.Lock()
+	defer adminSessionsLock.Unlock()
+
+	delete(adminSessions, id)
+}
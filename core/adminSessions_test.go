@@ -0,0 +1,33 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAdminSessionsExpire(t *testing.T) {
+	id, err := CreateAdminSession("token")
+	if err != nil {
+		t.Fatalf("unable to create the session: %v", err)
+	}
+
+	if token, ok := GetAdminSessionToken(id); !ok || token != "token" {
+		t.Fatalf("expected the token of the session, got %q", token)
+	}
+
+	adminSessionsLock.Lock()
+	adminSessions[id] = adminSession{token: "token", expiresAt: time.Now().Add(-time.Second)}
+	adminSessionsLock.Unlock()
+
+	if _, ok := GetAdminSessionToken(id); ok {
+		t.Error("expected the expired session to be signed out")
+	}
+
+	adminSessionsLock.Lock()
+	_, kept := adminSessions[id]
+	adminSessionsLock.Unlock()
+
+	if kept {
+		t.Error("expected the expired session to be removed")
+	}
+}
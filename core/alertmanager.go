@@ -0,0 +1,227 @@
+package core
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+const alertmanagerActor = "alertmanager"
+
+// alertmanagerLock keeps notifications of the same group arriving at once from opening two incidents
+var alertmanagerLock sync.Mutex
+
+// HandleAlertmanagerWebhook opens, updates or resolves the incident of the alert group. Every group gets
+// a single incident, alerts already part of it are skipped so repeated notifications don't add updates.
+// The incident is nil when there was nothing to do.
+func HandleAlertmanagerWebhook(webhook *models.AlertmanagerWebhook) (*models.Incident, error) {
+	alertmanagerLock.Lock()
+	defer alertmanagerLock.Unlock()
+
+	source := "alertmanager:" + webhook.GroupKey
+
+	incident, err := getOpenIncidentBySource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if webhook.Status == models.AlertmanagerStatusResolved {
+		// Nothing was opened for the group, or it was resolved already
+		if incident == nil {
+			return nil, nil
+		}
+
+		return CreateIncidentUpdate(incident.ID, &models.StatusUpdate{
+			Status:  models.IncidentStatusResolved,
+			Message: "The alerts have resolved.",
+		}, alertmanagerActor)
+	}
+
+	known := make(map[string]bool)
+	if incident != nil {
+		for _, fingerprint := range incident.Alerts {
+			known[fingerprint] = true
+		}
+	}
+
+	var services []models.ServiceUpdate
+	var fingerprints []string
+	var messages []string
+
+	for _, alert := range webhook.Alerts {
+		if alert.Status != models.AlertmanagerStatusFiring || known[alert.Fingerprint] {
+			continue
+		}
+
+		service, ok := mapAlertToService(alert)
+		if !ok {
+			continue
+		}
+
+		known[alert.Fingerprint] = true
+		fingerprints = append(fingerprints, alert.Fingerprint)
+		messages = append(messages, alertMessage(alert))
+		services = mergeServiceUpdate(services, service)
+	}
+
+	if len(fingerprints) == 0 {
+		return incident, nil
+	}
+
+	message := strings.Join(messages, "\n")
+
+	if incident == nil {
+		now := time.Now()
+
+		return CreateIncident(&models.Incident{
+			Time:     now,
+			Title:    alertGroupTitle(webhook),
+			Status:   models.IncidentStatusInvestigating,
+			Services: services,
+			Source:   source,
+			Alerts:   fingerprints,
+			Updates: []*models.StatusUpdate{
+				{
+					Time:    now,
+					Status:  models.IncidentStatusInvestigating,
+					Message: message,
+				},
+			},
+		}, alertmanagerActor)
+	}
+
+	// The update sets the status of the services it lists, so they get the worst status of the alerts so far
+	// and a warning arriving after a critical alert doesn't downgrade them
+	for i, s := range services {
+		for _, affected := range incident.Services {
+			if affected.Name == s.Name {
+				services[i].Status = models.WorstServiceStatus(s.Status, affected.Status)
+			}
+		}
+	}
+
+	// The incident keeps whatever status it was given, new alerts only add to it
+	incident, err = CreateIncidentUpdate(incident.ID, &models.StatusUpdate{
+		Status:   incident.Status,
+		Message:  message,
+		Services: services,
+	}, alertmanagerActor)
+	if err != nil {
+		return nil, err
+	}
+
+	incident.Alerts = append(incident.Alerts, fingerprints...)
+
+	if err := _dataStore.UpdateIncident(incident); err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+// mapAlertToService finds the service and region of the alert using the first matching rule.
+// Alerts for unknown services or regions are skipped.
+func mapAlertToService(alert models.AlertmanagerAlert) (models.ServiceUpdate, bool) {
+	for _, rule := range config.Config.Alertmanager.Rules {
+		if !alertMatches(alert, rule.Match) {
+			continue
+		}
+
+		serviceName := rule.Service
+		if serviceName == "" {
+			serviceName = alert.Labels[rule.ServiceLabel]
+		}
+
+		regionCode := rule.Region
+		if regionCode == "" && rule.RegionLabel != "" {
+			regionCode = alert.Labels[rule.RegionLabel]
+		}
+
+		status := models.ServiceStatusOutage
+		if rule.Status != "" {
+			status = models.ServiceStatuses[strings.ToLower(rule.Status)]
+		}
+
+		service := models.ServiceUpdate{Name: serviceName, Status: status}
+		if regionCode != "" {
+			service.Regions = []string{regionCode}
+		}
+
+		if err := validateServiceUpdates([]models.ServiceUpdate{service}, true); err != nil {
+			log.Printf("Skipping alert %s for service %q region %q: %v\n", alert.Fingerprint, serviceName, regionCode, err)
+			return models.ServiceUpdate{}, false
+		}
+
+		return service, true
+	}
+
+	return models.ServiceUpdate{}, false
+}
+
+func alertMatches(alert models.AlertmanagerAlert, match map[string]string) bool {
+	for label, value := range match {
+		if alert.Labels[label] != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mergeServiceUpdate adds the service to the list, or its regions and the worst of both statuses when it's already there
+func mergeServiceUpdate(services []models.ServiceUpdate, service models.ServiceUpdate) []models.ServiceUpdate {
+	for i, s := range services {
+		if s.Name != service.Name {
+			continue
+		}
+
+		services[i].Status = models.WorstServiceStatus(s.Status, service.Status)
+
+		for _, regionCode := range service.Regions {
+			if !stringInSlice(regionCode, s.Regions) {
+				services[i].Regions = append(services[i].Regions, regionCode)
+			}
+		}
+
+		return services
+	}
+
+	return append(services, service)
+}
+
+func alertGroupTitle(webhook *models.AlertmanagerWebhook) string {
+	if summary := webhook.CommonAnnotations["summary"]; summary != "" {
+		return summary
+	}
+
+	if name := webhook.CommonLabels["alertname"]; name != "" {
+		return name
+	}
+
+	labels := make([]string, 0, len(webhook.GroupLabels))
+	for label, value := range webhook.GroupLabels {
+		labels = append(labels, fmt.Sprintf("%s=%s", label, value))
+	}
+
+	sort.Strings(labels)
+
+	return "Alert " + strings.Join(labels, ", ")
+}
+
+func alertMessage(alert models.AlertmanagerAlert) string {
+	message := alert.Labels["alertname"]
+
+	for _, annotation := range []string{"summary", "description"} {
+		if text := alert.Annotations[annotation]; text != "" {
+			return fmt.Sprintf("%s: %s", message, text)
+		}
+	}
+
+	return message + " is firing"
+}
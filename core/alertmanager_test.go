@@ -0,0 +1,174 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// useTestAlertmanagerRules maps the alerts to the service by the severity label, with the region from the region label
+func useTestAlertmanagerRules(t *testing.T, service string) {
+	previous := config.Config.Alertmanager.Rules
+	t.Cleanup(func() {
+		config.Config.Alertmanager.Rules = previous
+	})
+
+	config.Config.Alertmanager.Rules = []config.AlertmanagerRuleConfig{
+		{Match: map[string]string{"severity": "warning"}, Service: service, RegionLabel: "region", Status: "Degraded"},
+		{Match: map[string]string{"severity": "critical"}, Service: service, RegionLabel: "region", Status: "Outage"},
+	}
+}
+
+func testAlert(fingerprint string, status string, severity string, region string) models.AlertmanagerAlert {
+	return models.AlertmanagerAlert{
+		Status:      status,
+		Fingerprint: fingerprint,
+		Labels:      map[string]string{"alertname": "HighErrorRate", "severity": severity, "region": region},
+	}
+}
+
+func handleTestAlertmanagerWebhook(t *testing.T, groupKey string, status string, alerts ...models.AlertmanagerAlert) *models.Incident {
+	incident, err := HandleAlertmanagerWebhook(&models.AlertmanagerWebhook{
+		GroupKey:     groupKey,
+		Status:       status,
+		CommonLabels: map[string]string{"alertname": "HighErrorRate"},
+		Alerts:       alerts,
+	})
+	if err != nil {
+		t.Fatalf("unable to handle the webhook: %v", err)
+	}
+
+	if incident != nil {
+		t.Cleanup(func() {
+			_dataStore.DeleteIncident(incident.ID)           //nolint:errcheck
+			deriveStatuses(statusChangeCause{Actor: "test"}) //nolint:errcheck
+		})
+	}
+
+	return incident
+}
+
+func TestHandleAlertmanagerWebhookGroupsAlertsIntoOneIncident(t *testing.T) {
+	newTestService(t, "alerts-group", "eu", "us")
+	useTestAlertmanagerRules(t, "alerts-group")
+
+	const group = `{}:{alertname="HighErrorRate"}`
+
+	incident := handleTestAlertmanagerWebhook(t, group, models.AlertmanagerStatusFiring,
+		testAlert("a1", models.AlertmanagerStatusFiring, "critical", "eu"))
+	if incident == nil {
+		t.Fatal("expected an incident for the firing alert")
+	}
+
+	// Alertmanager notifies again with the same alert, and later with another one joining the group
+	repeated := handleTestAlertmanagerWebhook(t, group, models.AlertmanagerStatusFiring,
+		testAlert("a1", models.AlertmanagerStatusFiring, "critical", "eu"))
+	if repeated == nil || repeated.ID != incident.ID || len(repeated.Updates) != len(incident.Updates) {
+		t.Fatalf("expected the repeated alert to change nothing, got %+v", repeated)
+	}
+
+	joined := handleTestAlertmanagerWebhook(t, group, models.AlertmanagerStatusFiring,
+		testAlert("a1", models.AlertmanagerStatusFiring, "critical", "eu"),
+		testAlert("a2", models.AlertmanagerStatusFiring, "warning", "us"))
+	if joined == nil || joined.ID != incident.ID {
+		t.Fatalf("expected the joining alert to update incident %d, got %+v", incident.ID, joined)
+	}
+
+	if len(joined.Updates) != len(incident.Updates)+1 {
+		t.Errorf("expected a single update for the joining alert, got %d updates", len(joined.Updates))
+	}
+
+	stored, err := GetIncidentByID(incident.ID)
+	if err != nil {
+		t.Fatalf("unable to get the incident: %v", err)
+	}
+
+	if len(stored.Alerts) != 2 || stored.Alerts[0] != "a1" || stored.Alerts[1] != "a2" {
+		t.Errorf("expected the fingerprints a1 and a2 to be tracked, got %v", stored.Alerts)
+	}
+
+	// The warning doesn't downgrade the service the critical alert put out
+	expected := map[string]models.ServiceAndRegionStatus{
+		"":   models.ServiceStatusOutage,
+		"eu": models.ServiceStatusOutage,
+		"us": models.ServiceStatusOutage,
+	}
+
+	for regionCode, status := range expected {
+		if actual := serviceStatus(t, "alerts-group", regionCode); actual != status {
+			t.Errorf("expected %q to be %s, got %s", regionCode, status, actual)
+		}
+	}
+
+	// Another group gets an incident of its own
+	other := handleTestAlertmanagerWebhook(t, `{}:{alertname="HighLatency"}`, models.AlertmanagerStatusFiring,
+		testAlert("b1", models.AlertmanagerStatusFiring, "warning", "eu"))
+	if other == nil || other.ID == incident.ID {
+		t.Errorf("expected another incident for the other group, got %+v", other)
+	}
+}
+
+func TestHandleAlertmanagerWebhookResolvesOnceTheWholeGroupResolves(t *testing.T) {
+	newTestService(t, "alerts-resolve")
+	useTestAlertmanagerRules(t, "alerts-resolve")
+
+	const group = `{}:{alertname="DiskFull"}`
+
+	incident := handleTestAlertmanagerWebhook(t, group, models.AlertmanagerStatusFiring,
+		testAlert("c1", models.AlertmanagerStatusFiring, "critical", ""),
+		testAlert("c2", models.AlertmanagerStatusFiring, "critical", ""))
+	if incident == nil {
+		t.Fatal("expected an incident for the firing alerts")
+	}
+
+	// One of the alerts resolved, the group is still firing
+	handleTestAlertmanagerWebhook(t, group, models.AlertmanagerStatusFiring,
+		testAlert("c1", models.AlertmanagerStatusResolved, "critical", ""),
+		testAlert("c2", models.AlertmanagerStatusFiring, "critical", ""))
+
+	if open, _ := GetIncidentByID(incident.ID); open.Status == models.IncidentStatusResolved {
+		t.Fatal("expected the incident to stay open while an alert of the group fires")
+	}
+
+	resolved := handleTestAlertmanagerWebhook(t, group, models.AlertmanagerStatusResolved,
+		testAlert("c1", models.AlertmanagerStatusResolved, "critical", ""),
+		testAlert("c2", models.AlertmanagerStatusResolved, "critical", ""))
+	if resolved == nil || resolved.ID != incident.ID || resolved.Status != models.IncidentStatusResolved {
+		t.Fatalf("expected incident %d to be resolved, got %+v", incident.ID, resolved)
+	}
+
+	if status := serviceStatus(t, "alerts-resolve", ""); status != models.ServiceStatusNominal {
+		t.Errorf("expected the service to be nominal again, got %s", status)
+	}
+
+	// Resolved again, or resolved for a group which never had an incident, is ignored
+	if again := handleTestAlertmanagerWebhook(t, group, models.AlertmanagerStatusResolved,
+		testAlert("c1", models.AlertmanagerStatusResolved, "critical", "")); again != nil {
+		t.Errorf("expected nothing to be done for the resolved group, got incident %d", again.ID)
+	}
+
+	if unknown := handleTestAlertmanagerWebhook(t, `{}:{alertname="Unknown"}`, models.AlertmanagerStatusResolved,
+		testAlert("d1", models.AlertmanagerStatusResolved, "critical", "")); unknown != nil {
+		t.Errorf("expected nothing to be done for the unknown group, got incident %d", unknown.ID)
+	}
+}
+
+func TestHandleAlertmanagerWebhookSkipsAlertsWithoutAService(t *testing.T) {
+	newTestService(t, "alerts-unmapped", "eu")
+	useTestAlertmanagerRules(t, "alerts-unmapped")
+
+	tests := []struct {
+		name  string
+		alert models.AlertmanagerAlert
+	}{
+		{name: "no matching rule", alert: testAlert("e1", models.AlertmanagerStatusFiring, "info", "")},
+		{name: "unknown region", alert: testAlert("e2", models.AlertmanagerStatusFiring, "critical", "mars")},
+	}
+
+	for _, test := range tests {
+		if incident := handleTestAlertmanagerWebhook(t, "unmapped:"+test.name, models.AlertmanagerStatusFiring, test.alert); incident != nil {
+			t.Errorf("%s: expected no incident, got %d", test.name, incident.ID)
+		}
+	}
+}
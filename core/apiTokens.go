@@ -0,0 +1,135 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// apiTokenPrefix makes the tokens easy to recognize, for people and secret scanners alike
+const apiTokenPrefix = "sct_"
+
+// apiTokenLastUsedInterval limits how often the last use of a token gets written
+const apiTokenLastUsedInterval = time.Minute
+
+// GetAPITokens gets all of the api tokens, including the revoked and expired ones
+func GetAPITokens() ([]*models.APIToken, error) {
+	return _dataStore.GetAPITokens()
+}
+
+// GetAPITokenByID gets the api token by id, both token and error will be nil if none found
+func GetAPITokenByID(id int) (*models.APIToken, error) {
+	return _dataStore.GetAPITokenByID(id)
+}
+
+// CreateAPIToken generates a token with the name, scopes and expiry of the one provided. The
+// returned token is the only time the token itself is known, only its hash is stored.
+func CreateAPIToken(token *models.APIToken, actor string) (*models.APIToken, error) {
+	if token.Name == "" {
+		return nil, errors.New("name property is missing")
+	}
+
+	if len(token.Scopes) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+
+	for i, scope := range token.Scopes {
+		s, ok := models.Scopes[strings.ToLower(string(scope))]
+		if !ok {
+			return nil, errors.New("invalid scope: " + string(scope))
+		}
+
+		token.Scopes[i] = s
+	}
+
+	now := time.Now()
+	if token.ExpiresAt != nil && !token.ExpiresAt.After(now) {
+		return nil, errors.New("expiresAt must be in the future")
+	}
+
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+
+	plain := apiTokenPrefix + secret
+
+	token.ID = 0
+	token.Prefix = plain[:len(apiTokenPrefix)+6]
+	token.Hash = hashAPIToken(plain)
+	token.Token = ""
+	token.CreatedBy = actor
+	token.CreatedAt = now
+	token.LastUsedAt = nil
+	token.RevokedAt = nil
+
+	if err := _dataStore.CreateAPIToken(token); err != nil {
+		return nil, err
+	}
+
+	token.Token = plain
+
+	return token, nil
+}
+
+// RevokeAPIToken revokes the api token, both token and error will be nil if none found
+func RevokeAPIToken(id int) (*models.APIToken, error) {
+	token, err := _dataStore.GetAPITokenByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if token == nil {
+		return nil, nil
+	}
+
+	if token.RevokedAt == nil {
+		now := time.Now()
+		token.RevokedAt = &now
+
+		if err := _dataStore.UpdateAPIToken(token); err != nil {
+			return nil, err
+		}
+	}
+
+	return token, nil
+}
+
+// AuthenticateAPIToken finds the active api token, nil when it's unknown, expired or revoked
+func AuthenticateAPIToken(plain string) (*models.APIToken, error) {
+	if !strings.HasPrefix(plain, apiTokenPrefix) {
+		return nil, nil
+	}
+
+	token, err := _dataStore.GetAPITokenByHash(hashAPIToken(plain))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if token == nil || !token.IsActive(now) {
+		return nil, nil
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) > apiTokenLastUsedInterval {
+		token.LastUsedAt = &now
+
+		if err := _dataStore.UpdateAPIToken(token); err != nil {
+			log.Println("Error while storing the last use of the api token:", err)
+		}
+	}
+
+	return token, nil
+}
+
+// hashAPIToken hashes the token for storage. The tokens are random enough for a plain sha256 to do.
+func hashAPIToken(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+func newTestAPIToken(t *testing.T, name string) *models.APIToken {
+	token, err := CreateAPIToken(&models.APIToken{Name: name, Scopes: []models.Scope{"incidents:write"}}, "test")
+	if err != nil {
+		t.Fatalf("unable to create the api token: %v", err)
+	}
+
+	return token
+}
+
+func TestCreateAPITokenOnlyStoresTheHash(t *testing.T) {
+	token := newTestAPIToken(t, "stored")
+
+	stored, err := _dataStore.GetAPITokenByID(token.ID)
+	if err != nil || stored == nil {
+		t.Fatalf("unable to get the api token: %v", err)
+	}
+
+	if stored.Token != "" || stored.Hash == token.Token || stored.Hash != hashAPIToken(token.Token) {
+		t.Errorf("expected only the hash of the token to be stored, got token %q and hash %q", stored.Token, stored.Hash)
+	}
+}
+
+func TestAuthenticateAPIToken(t *testing.T) {
+	revoked := newTestAPIToken(t, "revoked")
+	if _, err := RevokeAPIToken(revoked.ID); err != nil {
+		t.Fatalf("unable to revoke the api token: %v", err)
+	}
+
+	expired := newTestAPIToken(t, "expired")
+	stored, _ := _dataStore.GetAPITokenByID(expired.ID)
+	past := time.Now().Add(-time.Second)
+	stored.ExpiresAt = &past
+	if err := _dataStore.UpdateAPIToken(stored); err != nil {
+		t.Fatalf("unable to expire the api token: %v", err)
+	}
+
+	active := newTestAPIToken(t, "active")
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{name: "active", token: active.Token, valid: true},
+		{name: "revoked", token: revoked.Token},
+		{name: "expired", token: expired.Token},
+		{name: "unknown", token: apiTokenPrefix + "0000000000"},
+		{name: "hash instead of the token", token: apiTokenPrefix + hashAPIToken(active.Token)},
+		{name: "without the prefix", token: active.Token[len(apiTokenPrefix):]},
+	}
+
+	for _, test := range tests {
+		token, err := AuthenticateAPIToken(test.token)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+
+		if (token != nil) != test.valid {
+			t.Errorf("%s: expected valid %v, got %+v", test.name, test.valid, token)
+		}
+	}
+}
+
+func TestAuthenticateAPITokenThrottlesTheLastUse(t *testing.T) {
+	token := newTestAPIToken(t, "throttled")
+
+	lastUsedAt := func() time.Time {
+		stored, err := _dataStore.GetAPITokenByID(token.ID)
+		if err != nil || stored == nil || stored.LastUsedAt == nil {
+			t.Fatalf("expected the last use of the api token to be stored: %v", err)
+		}
+
+		return *stored.LastUsedAt
+	}
+
+	if _, err := AuthenticateAPIToken(token.Token); err != nil {
+		t.Fatalf("unable to authenticate: %v", err)
+	}
+
+	first := lastUsedAt()
+
+	if _, err := AuthenticateAPIToken(token.Token); err != nil {
+		t.Fatalf("unable to authenticate: %v", err)
+	}
+
+	if !lastUsedAt().Equal(first) {
+		t.Error("expected the last use not to be written again within the interval")
+	}
+
+	// Used longer ago than the interval
+	stored, _ := _dataStore.GetAPITokenByID(token.ID)
+	earlier := time.Now().Add(-apiTokenLastUsedInterval - time.Second)
+	stored.LastUsedAt = &earlier
+	if err := _dataStore.UpdateAPIToken(stored); err != nil {
+		t.Fatalf("unable to update the api token: %v", err)
+	}
+
+	if _, err := AuthenticateAPIToken(token.Token); err != nil {
+		t.Fatalf("unable to authenticate: %v", err)
+	}
+
+	if !lastUsedAt().After(earlier) {
+		t.Error("expected the last use to be written once the interval passed")
+	}
+}
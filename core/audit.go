@@ -0,0 +1,130 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+type auditSnapshotLoader func(id int) (interface{}, error)
+
+// auditSnapshotLoaders load the record of each target type as it is returned by the api, the
+// loaders give a nil interface, not a nil pointer, when the record doesn't exist
+var auditSnapshotLoaders = map[string]auditSnapshotLoader{
+	models.AuditTargetService: func(id int) (interface{}, error) {
+		service, err := _dataStore.GetServiceByID(id)
+		if err != nil || service == nil {
+			return nil, err
+		}
+
+		return service, nil
+	},
+	models.AuditTargetRegion: func(id int) (interface{}, error) {
+		regions, err := _dataStore.GetRegions()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, region := range regions {
+			if region.ID == id {
+				return region, nil
+			}
+		}
+
+		return nil, nil
+	},
+	models.AuditTargetIncident: func(id int) (interface{}, error) {
+		incident, err := _dataStore.GetIncidentByID(id)
+		if err != nil || incident == nil {
+			return nil, err
+		}
+
+		return incident, nil
+	},
+	models.AuditTargetIncidentTemplate: func(id int) (interface{}, error) {
+		incidentTemplate, err := _dataStore.GetIncidentTemplateByID(id)
+		if err != nil || incidentTemplate == nil {
+			return nil, err
+		}
+
+		return incidentTemplate, nil
+	},
+	models.AuditTargetScheduledMaintenance: func(id int) (interface{}, error) {
+		scheduledMaintenance, err := _dataStore.GetScheduledMaintenanceByID(id)
+		if err != nil || scheduledMaintenance == nil {
+			return nil, err
+		}
+
+		return scheduledMaintenance, nil
+	},
+	models.AuditTargetRecurringMaintenance: func(id int) (interface{}, error) {
+		recurringMaintenance, err := _dataStore.GetRecurringMaintenanceByID(id)
+		if err != nil || recurringMaintenance == nil {
+			return nil, err
+		}
+
+		return recurringMaintenance, nil
+	},
+	models.AuditTargetSubscriber: func(id int) (interface{}, error) {
+		subscriber, err := _dataStore.GetSubscriberByID(id)
+		if err != nil || subscriber == nil {
+			return nil, err
+		}
+
+		return subscriber, nil
+	},
+	models.AuditTargetWebhook: func(id int) (interface{}, error) {
+		webhook, err := _dataStore.GetWebhookByID(id)
+		if err != nil || webhook == nil {
+			return nil, err
+		}
+
+		// The secret doesn't belong in the audit log, only whether it changed
+		if webhook.Secret != "" {
+			webhook.Secret = "[redacted]"
+		}
+
+		return webhook, nil
+	},
+	models.AuditTargetAPIToken: func(id int) (interface{}, error) {
+		token, err := _dataStore.GetAPITokenByID(id)
+		if err != nil || token == nil {
+			return nil, err
+		}
+
+		token.Hash = ""
+
+		return token, nil
+	},
+}
+
+// GetAuditSnapshot gets the record as json so it can be kept in the audit log, nil when it doesn't exist
+func GetAuditSnapshot(targetType string, id int) (json.RawMessage, error) {
+	load, ok := auditSnapshotLoaders[targetType]
+	if !ok {
+		return nil, fmt.Errorf("unknown audit target type: %s", targetType)
+	}
+
+	record, err := load(id)
+	if err != nil || record == nil {
+		return nil, err
+	}
+
+	return json.Marshal(record)
+}
+
+// RecordAudit appends the entry to the audit log
+func RecordAudit(entry *models.AuditEntry) error {
+	return _dataStore.CreateAuditEntry(entry)
+}
+
+// GetAuditEntries gets the entries of the audit log passing the filter, newest first
+func GetAuditEntries(filter models.AuditFilter) ([]*models.AuditEntry, error) {
+	return _dataStore.GetAuditEntries(filter)
+}
+
+// GetAuditEntryByID gets the entry of the audit log by id, both entry and error will be nil if none found
+func GetAuditEntryByID(id int) (*models.AuditEntry, error) {
+	return _dataStore.GetAuditEntryByID(id)
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+func TestGetAuditSnapshotLeavesOutSecrets(t *testing.T) {
+	webhook, err := CreateWebhook(&models.Webhook{
+		Name:    "audited",
+		URL:     "https://hooks.example.com/statuscentral",
+		Secret:  "webhook-secret-value",
+		Enabled: true,
+	})
+	if err != nil {
+		t.Fatalf("unable to create the webhook: %v", err)
+	}
+
+	token := newTestAPIToken(t, "audited")
+
+	stored, err := _dataStore.GetAPITokenByID(token.ID)
+	if err != nil || stored == nil {
+		t.Fatalf("unable to get the api token: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		targetType string
+		id         int
+		secrets    []string
+	}{
+		{name: "webhook", targetType: models.AuditTargetWebhook, id: webhook.ID, secrets: []string{"webhook-secret-value"}},
+		{name: "api token", targetType: models.AuditTargetAPIToken, id: token.ID, secrets: []string{stored.Hash, token.Token}},
+	}
+
+	for _, test := range tests {
+		snapshot, err := GetAuditSnapshot(test.targetType, test.id)
+		if err != nil || snapshot == nil {
+			t.Errorf("%s: expected a snapshot, got %v", test.name, err)
+			continue
+		}
+
+		for _, secret := range test.secrets {
+			if strings.Contains(string(snapshot), secret) {
+				t.Errorf("%s: expected the snapshot to leave out %q, got %s", test.name, secret, snapshot)
+			}
+		}
+
+		// The record itself is untouched, only the snapshot leaves the secrets out
+		if test.targetType == models.AuditTargetWebhook {
+			if stored, _ := _dataStore.GetWebhookByID(webhook.ID); stored.Secret != "webhook-secret-value" {
+				t.Errorf("expected the secret of the webhook to be kept, got %q", stored.Secret)
+			}
+		}
+	}
+}
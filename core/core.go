@@ -32,6 +32,31 @@ func TwistItUp() error {
 		log.Fatalln(err)
 	}
 
+	if err := runStartupMigrations(); err != nil {
+		log.Fatalln(err)
+	}
+
+	if err := createWebhooksFromConfig(); err != nil {
+		log.Fatalln(err)
+	}
+
+	if err := setupOIDC(); err != nil {
+		log.Fatalln(err)
+	}
+
+	if err := setupNotifiers(); err != nil {
+		log.Fatalln(err)
+	}
+
+	startNotificationWorker()
+	startMailWorker()
+	startWebhookDeliveryWorker()
+	startMaintenanceScheduler()
+
+	if err := startProbes(); err != nil {
+		log.Fatalln(err)
+	}
+
 	return nil
 }
 
@@ -50,6 +75,7 @@ func RunMigrations() error {
 				Title:           "Scheduled Maintenance",
 				Description:     incident.Title,
 				Services:        incident.Services,
+				Notifications:   incident.Notifications,
 				OriginalTweetID: incident.OriginalTweetID,
 				LatestTweetID:   incident.LatestTweetID,
 				PlannedStart:    incident.Maintenance.Start,
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/RocketChat/statuscentral/store/boltstore"
+)
+
+func TestMain(m *testing.M) {
+	// The templates are loaded relative to the root of the repository, like the server does
+	if err := os.Chdir(".."); err != nil {
+		panic(err)
+	}
+
+	// Tests needing stored data share a database which is thrown away afterwards
+	dataPath, err := ioutil.TempDir("", "statuscentral-")
+	if err != nil {
+		panic(err)
+	}
+
+	configFile, err := ioutil.TempFile("", "statuscentral-*.yaml")
+	if err != nil {
+		panic(err)
+	}
+
+	if _, err := configFile.WriteString("dataPath: " + dataPath + "/\nwebsite:\n  url: https://status.example.com/\n"); err != nil {
+		panic(err)
+	}
+
+	configFile.Close()
+
+	err = config.Load(configFile.Name())
+	os.Remove(configFile.Name())
+
+	if err != nil {
+		panic(err)
+	}
+
+	_dataStore, err = boltstore.New()
+	if err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+
+	os.RemoveAll(dataPath)
+	os.Exit(code)
+}
+
+// newTestService creates an enabled service with the regions in the shared database, tests use services of their
+// own so the statuses they derive don't affect each other
+func newTestService(t *testing.T, name string, regionCodes ...string) *models.Service {
+	service := &models.Service{
+		Name:    name,
+		Status:  models.ServiceStatusNominal,
+		Enabled: true,
+		Tags:    make([]string, 0),
+	}
+
+	if err := CreateService(service); err != nil {
+		t.Fatalf("unable to create the service %s: %v", name, err)
+	}
+
+	for _, regionCode := range regionCodes {
+		region := &models.Region{
+			Name:        regionCode,
+			RegionCode:  regionCode,
+			ServiceID:   service.ID,
+			ServiceName: service.Name,
+			Status:      models.ServiceStatusNominal,
+			Enabled:     true,
+			Tags:        make([]string, 0),
+		}
+
+		if err := CreateRegion(region); err != nil {
+			t.Fatalf("unable to create the region %s of %s: %v", regionCode, name, err)
+		}
+	}
+
+	return service
+}
+
+// newTestIncident creates the incident, it's deleted again once the test is done
+func newTestIncident(t *testing.T, incident *models.Incident) *models.Incident {
+	incident, err := CreateIncident(incident, "test")
+	if err != nil {
+		t.Fatalf("unable to create the incident: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_dataStore.DeleteIncident(incident.ID)           //nolint:errcheck
+		deriveStatuses(statusChangeCause{Actor: "test"}) //nolint:errcheck
+	})
+
+	return incident
+}
+
+// serviceStatus gets the stored status of the service, or of its region when a region code is given
+func serviceStatus(t *testing.T, name string, regionCode string) models.ServiceAndRegionStatus {
+	if regionCode != "" {
+		region, err := _dataStore.GetRegionByCodeAndServiceName(regionCode, name)
+		if err != nil || region == nil {
+			t.Fatalf("unable to get the region %s of %s: %v", regionCode, name, err)
+		}
+
+		return region.Status
+	}
+
+	service, err := _dataStore.GetServiceByName(name)
+	if err != nil || service == nil {
+		t.Fatalf("unable to get the service %s: %v", name, err)
+	}
+
+	return service.Status
+}
@@ -0,0 +1,176 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+const feedMaxEntries = 50
+
+// GetFeed builds the feed of the latest incident and scheduled maintenance updates, optionally only those affecting the service.
+// The links are built from the given base url. Both feed and error will be nil if the service is not found.
+func GetFeed(baseURL string, serviceName string) (*models.Feed, error) {
+	if serviceName != "" {
+		service, err := _dataStore.GetServiceByName(serviceName)
+		if err != nil {
+			return nil, err
+		}
+
+		if service == nil {
+			return nil, nil
+		}
+
+		serviceName = service.Name
+	}
+
+	incidents, err := feedIncidents(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduledMaintenances, err := _dataStore.GetScheduledMaintenance(false)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &models.Feed{
+		Title:       fmt.Sprintf("%s Status", config.Config.Website.Title),
+		Description: fmt.Sprintf("Incidents and scheduled maintenance of %s", config.Config.Website.Title),
+		Link:        baseURL + "/",
+		Entries:     make([]models.FeedEntry, 0),
+	}
+
+	if serviceName != "" {
+		feed.Title = fmt.Sprintf("%s Status - %s", config.Config.Website.Title, serviceName)
+		feed.Description = fmt.Sprintf("Incidents and scheduled maintenance affecting %s", serviceName)
+	}
+
+	for _, incident := range incidents {
+		link := fmt.Sprintf("%s/incidents/%d", baseURL, incident.ID)
+
+		for _, update := range incident.Updates {
+			feed.Entries = append(feed.Entries, models.FeedEntry{
+				GUID:      fmt.Sprintf("urn:statuscentral:incident:%d:update:%d", incident.ID, update.ID),
+				Title:     fmt.Sprintf("%s - %s", incident.Title, update.Status),
+				Link:      link,
+				Content:   feedUpdateContent(update, incident.Services),
+				Published: update.Time,
+			})
+		}
+	}
+
+	for _, scheduledMaintenance := range scheduledMaintenances {
+		if !feedAffectsService(scheduledMaintenance.Services, serviceName) {
+			continue
+		}
+
+		link := fmt.Sprintf("%s/scheduled-maintenance/%d", baseURL, scheduledMaintenance.ID)
+
+		feed.Entries = append(feed.Entries, models.FeedEntry{
+			GUID:  fmt.Sprintf("urn:statuscentral:maintenance:%d", scheduledMaintenance.ID),
+			Title: fmt.Sprintf("Scheduled Maintenance - %s", scheduledMaintenance.Title),
+			Link:  link,
+			Content: fmt.Sprintf("%s\n\nPlanned from %s until %s.\n\nAffected services: %s",
+				scheduledMaintenance.Description,
+				scheduledMaintenance.PlannedStart.UTC().Format(time.RFC1123),
+				scheduledMaintenance.PlannedEnd.UTC().Format(time.RFC1123),
+				feedServiceNames(scheduledMaintenance.Services)),
+			Published: scheduledMaintenance.CreatedAt,
+		})
+
+		for _, update := range scheduledMaintenance.Updates {
+			feed.Entries = append(feed.Entries, models.FeedEntry{
+				GUID:      fmt.Sprintf("urn:statuscentral:maintenance:%d:update:%d", scheduledMaintenance.ID, update.ID),
+				Title:     fmt.Sprintf("%s - %s", scheduledMaintenance.Title, update.Status),
+				Link:      link,
+				Content:   feedUpdateContent(update, scheduledMaintenance.Services),
+				Published: update.Time,
+			})
+		}
+	}
+
+	// Newest first, the same as the status page itself
+	sort.SliceStable(feed.Entries, func(i, j int) bool {
+		return feed.Entries[i].Published.After(feed.Entries[j].Published)
+	})
+
+	if len(feed.Entries) > feedMaxEntries {
+		feed.Entries = feed.Entries[:feedMaxEntries]
+	}
+
+	if len(feed.Entries) > 0 {
+		feed.Updated = feed.Entries[0].Published
+	} else {
+		feed.Updated = time.Now()
+	}
+
+	return feed, nil
+}
+
+// feedIncidents gets the latest incidents affecting the service, or all of them without a service name. The incidents
+// are paged through until enough affect the service, as it may not be affected by any of the latest ones.
+func feedIncidents(serviceName string) ([]*models.Incident, error) {
+	incidents := make([]*models.Incident, 0, feedMaxEntries)
+
+	for offset := 0; ; offset += feedMaxEntries {
+		page, err := _dataStore.GetIncidents(false, models.Pagination{Limit: feedMaxEntries, Offset: offset})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, incident := range page {
+			if !feedAffectsService(incident.Services, serviceName) {
+				continue
+			}
+
+			incidents = append(incidents, incident)
+			if len(incidents) == feedMaxEntries {
+				return incidents, nil
+			}
+		}
+
+		if len(page) < feedMaxEntries {
+			return incidents, nil
+		}
+	}
+}
+
+func feedAffectsService(services []models.ServiceUpdate, serviceName string) bool {
+	if serviceName == "" {
+		return true
+	}
+
+	for _, s := range services {
+		if s.Name == serviceName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func feedUpdateContent(update *models.StatusUpdate, services []models.ServiceUpdate) string {
+	if len(update.Services) > 0 {
+		services = update.Services
+	}
+
+	if len(services) == 0 {
+		return update.Message
+	}
+
+	return fmt.Sprintf("%s\n\nAffected services: %s", update.Message, feedServiceNames(services))
+}
+
+func feedServiceNames(services []models.ServiceUpdate) string {
+	names := make([]string, 0, len(services))
+	for _, s := range services {
+		names = append(names, s.Name)
+	}
+
+	return strings.Join(names, ", ")
+}
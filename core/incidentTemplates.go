@@ -0,0 +1,216 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// ErrInvalidIncidentTemplate is returned when an incident template isn't valid or can't be rendered with the variables
+var ErrInvalidIncidentTemplate = errors.New("invalid incident template")
+
+// GetIncidentTemplates gets all of the incident templates
+func GetIncidentTemplates() ([]*models.IncidentTemplate, error) {
+	return _dataStore.GetIncidentTemplates()
+}
+
+// GetIncidentTemplateByID gets the incident template by id, both will be nil if none found
+func GetIncidentTemplateByID(id int) (*models.IncidentTemplate, error) {
+	return _dataStore.GetIncidentTemplateByID(id)
+}
+
+// GetIncidentTemplateByName gets the incident template by name, both will be nil if none found
+func GetIncidentTemplateByName(name string) (*models.IncidentTemplate, error) {
+	incidentTemplates, err := _dataStore.GetIncidentTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range incidentTemplates {
+		if strings.EqualFold(t.Name, name) {
+			return t, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// CreateIncidentTemplate creates the incident template, its name has to be unique
+func CreateIncidentTemplate(incidentTemplate *models.IncidentTemplate) (*models.IncidentTemplate, error) {
+	if err := validateIncidentTemplate(incidentTemplate); err != nil {
+		return nil, err
+	}
+
+	if err := _dataStore.CreateIncidentTemplate(incidentTemplate); err != nil {
+		return nil, err
+	}
+
+	return incidentTemplate, nil
+}
+
+// UpdateIncidentTemplate replaces the incident template, which is nil when it doesn't exist
+func UpdateIncidentTemplate(incidentTemplate *models.IncidentTemplate) (*models.IncidentTemplate, error) {
+	existing, err := _dataStore.GetIncidentTemplateByID(incidentTemplate.ID)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	if err := validateIncidentTemplate(incidentTemplate); err != nil {
+		return nil, err
+	}
+
+	incidentTemplate.CreatedAt = existing.CreatedAt
+
+	if err := _dataStore.UpdateIncidentTemplate(incidentTemplate); err != nil {
+		return nil, err
+	}
+
+	return incidentTemplate, nil
+}
+
+// DeleteIncidentTemplate removes the incident template
+func DeleteIncidentTemplate(id int) error {
+	return _dataStore.DeleteIncidentTemplate(id)
+}
+
+// RenderIncidentTemplate fills in the variables of the template, giving the incident it describes with its message
+// as the first update. A variable the template uses but which isn't given is an error.
+func RenderIncidentTemplate(incidentTemplate *models.IncidentTemplate, variables map[string]string) (*models.Incident, error) {
+	// Variables are given like "region=EU" and used like {{.Region}}
+	data := make(map[string]string, len(variables))
+	for key, value := range variables {
+		if key == "" {
+			return nil, fmt.Errorf("%w: variables must have a name, like region=EU", ErrInvalidIncidentTemplate)
+		}
+
+		first, size := utf8.DecodeRuneInString(key)
+		data[string(unicode.ToUpper(first))+key[size:]] = value
+	}
+
+	render := func(name string, text string) (string, error) {
+		var buf bytes.Buffer
+
+		tmpl, err := parseIncidentTemplateText(name, text)
+		if err == nil {
+			err = tmpl.Execute(&buf, data)
+		}
+
+		if err != nil {
+			return "", fmt.Errorf("%w: %v", ErrInvalidIncidentTemplate, err)
+		}
+
+		return strings.TrimSpace(buf.String()), nil
+	}
+
+	title, err := render("title", incidentTemplate.Title)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := render("message", incidentTemplate.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	services := make([]models.ServiceUpdate, 0, len(incidentTemplate.Services))
+	for _, s := range incidentTemplate.Services {
+		name, err := render("service", s.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		regions := make([]string, 0, len(s.Regions))
+		for _, regionCode := range s.Regions {
+			region, err := render("region", regionCode)
+			if err != nil {
+				return nil, err
+			}
+
+			// A region left empty by the variables affects the whole service
+			if region != "" {
+				regions = append(regions, region)
+			}
+		}
+
+		services = append(services, models.ServiceUpdate{
+			Name:    name,
+			Status:  s.Status,
+			Regions: regions,
+		})
+	}
+
+	if err := validateServiceUpdates(services, true); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIncidentTemplate, err)
+	}
+
+	return &models.Incident{
+		Title:    title,
+		Status:   incidentTemplate.Status,
+		Services: services,
+		Updates: []*models.StatusUpdate{
+			{
+				Status:  incidentTemplate.Status,
+				Message: message,
+			},
+		},
+	}, nil
+}
+
+func parseIncidentTemplateText(name string, text string) (*template.Template, error) {
+	return template.New(name).Option("missingkey=error").Parse(text)
+}
+
+func validateIncidentTemplate(incidentTemplate *models.IncidentTemplate) error {
+	incidentTemplate.Name = strings.TrimSpace(incidentTemplate.Name)
+	if incidentTemplate.Name == "" {
+		return fmt.Errorf("%w: name is missing", ErrInvalidIncidentTemplate)
+	}
+
+	existing, err := GetIncidentTemplateByName(incidentTemplate.Name)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil && existing.ID != incidentTemplate.ID {
+		return fmt.Errorf("%w: there is a template named %s already", ErrInvalidIncidentTemplate, existing.Name)
+	}
+
+	if incidentTemplate.Title == "" || incidentTemplate.Message == "" {
+		return fmt.Errorf("%w: title and message must be provided", ErrInvalidIncidentTemplate)
+	}
+
+	if incidentTemplate.Status == "" {
+		incidentTemplate.Status = models.IncidentDefaultStatus
+	}
+
+	status, ok := models.IncidentStatuses[strings.ToLower(incidentTemplate.Status.String())]
+	if !ok || status == models.IncidentStatusScheduledMaintenance {
+		return fmt.Errorf("%w: invalid status %s", ErrInvalidIncidentTemplate, incidentTemplate.Status)
+	}
+
+	incidentTemplate.Status = status
+
+	texts := []string{incidentTemplate.Title, incidentTemplate.Message}
+	for _, s := range incidentTemplate.Services {
+		if _, ok := models.ServiceStatuses[s.Status.ToLower()]; !ok {
+			return fmt.Errorf("%w: invalid status %s of service %s", ErrInvalidIncidentTemplate, s.Status, s.Name)
+		}
+
+		texts = append(texts, s.Name)
+		texts = append(texts, s.Regions...)
+	}
+
+	for _, text := range texts {
+		if _, err := parseIncidentTemplateText("template", text); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidIncidentTemplate, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+func newTestIncidentTemplate(t *testing.T, incidentTemplate *models.IncidentTemplate) *models.IncidentTemplate {
+	incidentTemplate, err := CreateIncidentTemplate(incidentTemplate)
+	if err != nil {
+		t.Fatalf("unable to create the incident template: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_dataStore.DeleteIncidentTemplate(incidentTemplate.ID) //nolint:errcheck
+	})
+
+	return incidentTemplate
+}
+
+func TestRenderIncidentTemplate(t *testing.T) {
+	newTestService(t, "template-render", "eu", "us")
+
+	incidentTemplate := &models.IncidentTemplate{
+		Title:   "{{.Service}} is slow in {{if .Region}}{{.Region}}{{else}}every region{{end}}",
+		Message: "We are looking into {{.Service}}.",
+		Status:  models.IncidentStatusIdentified,
+		Services: []models.ServiceUpdate{
+			{Name: "{{.Service}}", Status: models.ServiceStatusDegraded, Regions: []string{"{{.Region}}"}},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		variables map[string]string
+		title     string
+		regions   []string
+		valid     bool
+	}{
+		{
+			name:      "region",
+			variables: map[string]string{"service": "template-render", "region": "eu"},
+			title:     "template-render is slow in eu",
+			regions:   []string{"eu"},
+			valid:     true,
+		},
+		{
+			name:      "empty region is the whole service",
+			variables: map[string]string{"service": "template-render", "region": ""},
+			title:     "template-render is slow in every region",
+			regions:   []string{},
+			valid:     true,
+		},
+		{name: "missing variable", variables: map[string]string{"service": "template-render"}},
+		{name: "variable without a name", variables: map[string]string{"service": "template-render", "region": "eu", "": "us"}},
+		{name: "unknown service", variables: map[string]string{"service": "template-unknown", "region": ""}},
+		{name: "unknown region", variables: map[string]string{"service": "template-render", "region": "mars"}},
+	}
+
+	for _, test := range tests {
+		incident, err := RenderIncidentTemplate(incidentTemplate, test.variables)
+		if !test.valid {
+			if !errors.Is(err, ErrInvalidIncidentTemplate) {
+				t.Errorf("%s: expected the template to be rejected, got %v", test.name, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: unable to render the template: %v", test.name, err)
+			continue
+		}
+
+		if incident.Title != test.title {
+			t.Errorf("%s: expected the title %q, got %q", test.name, test.title, incident.Title)
+		}
+
+		if incident.Status != models.IncidentStatusIdentified || len(incident.Updates) != 1 || incident.Updates[0].Message != "We are looking into template-render." {
+			t.Errorf("%s: expected the message as an identified update, got %+v", test.name, incident.Updates)
+		}
+
+		if len(incident.Services) != 1 || incident.Services[0].Name != "template-render" || len(incident.Services[0].Regions) != len(test.regions) {
+			t.Errorf("%s: expected the service with the regions %v, got %+v", test.name, test.regions, incident.Services)
+			continue
+		}
+
+		for i, regionCode := range test.regions {
+			if incident.Services[0].Regions[i] != regionCode {
+				t.Errorf("%s: expected the regions %v, got %v", test.name, test.regions, incident.Services[0].Regions)
+			}
+		}
+	}
+}
+
+func TestValidateIncidentTemplate(t *testing.T) {
+	existing := newTestIncidentTemplate(t, &models.IncidentTemplate{Name: "Database down", Title: "Database down", Message: "Looking into it"})
+	other := newTestIncidentTemplate(t, &models.IncidentTemplate{Name: "Queue full", Title: "Queue full", Message: "Looking into it"})
+
+	if existing.Status != models.IncidentDefaultStatus {
+		t.Errorf("expected the status to default to %s, got %s", models.IncidentDefaultStatus, existing.Status)
+	}
+
+	tests := []struct {
+		name             string
+		incidentTemplate models.IncidentTemplate
+	}{
+		{name: "no name", incidentTemplate: models.IncidentTemplate{Name: " ", Title: "Down", Message: "Down"}},
+		{name: "duplicate name", incidentTemplate: models.IncidentTemplate{Name: "database DOWN", Title: "Down", Message: "Down"}},
+		{name: "no message", incidentTemplate: models.IncidentTemplate{Name: "No message", Title: "Down"}},
+		{name: "maintenance status", incidentTemplate: models.IncidentTemplate{Name: "Maintenance", Title: "Down", Message: "Down", Status: models.IncidentStatusScheduledMaintenance}},
+		{name: "invalid service status", incidentTemplate: models.IncidentTemplate{Name: "Service status", Title: "Down", Message: "Down", Services: []models.ServiceUpdate{{Name: "db", Status: "Broken"}}}},
+		{name: "invalid template", incidentTemplate: models.IncidentTemplate{Name: "Template", Title: "{{.Service", Message: "Down"}},
+	}
+
+	for _, test := range tests {
+		if _, err := CreateIncidentTemplate(&test.incidentTemplate); !errors.Is(err, ErrInvalidIncidentTemplate) {
+			t.Errorf("%s: expected the template to be rejected, got %v", test.name, err)
+		}
+	}
+
+	// Updated under the name of another template is rejected, keeping its own name is not
+	renamed := *other
+	renamed.Name = "Database Down"
+
+	if _, err := UpdateIncidentTemplate(&renamed); !errors.Is(err, ErrInvalidIncidentTemplate) {
+		t.Errorf("expected the update to the name of another template to be rejected, got %v", err)
+	}
+
+	updated := *other
+	updated.Message = "The queue is full"
+
+	if _, err := UpdateIncidentTemplate(&updated); err != nil {
+		t.Errorf("expected the template to keep its own name, got %v", err)
+	}
+}
@@ -1,18 +1,15 @@
 package core
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
+	"net/url"
 	"sort"
 	"strings"
-	"text/template"
 	"time"
 
 	"github.com/RocketChat/statuscentral/config"
 	"github.com/RocketChat/statuscentral/models"
-	"github.com/dghubble/go-twitter/twitter"
-	"github.com/dghubble/oauth1"
 )
 
 // GetIncidents retrieves the incidents from the storage layer
@@ -25,70 +22,13 @@ func GetIncidentByID(id int) (*models.Incident, error) {
 	return _dataStore.GetIncidentByID(id)
 }
 
-// SendIncidentTwitter sends the incident info to the offical Rocket.Chat Cloud twitter account.
-func SendIncidentTwitter(incident *models.Incident) (int64, error) {
-	conf := oauth1.NewConfig(config.Config.Twitter.ConsumerKey, config.Config.Twitter.ConsumerSecret)
-	token := oauth1.NewToken(config.Config.Twitter.AccessToken, config.Config.Twitter.AccessSecret)
-	http := conf.Client(oauth1.NoContext, token)
-	http.Timeout = 5 * time.Second
-
-	client := twitter.NewClient(http)
-	tmpl, err := template.ParseFiles("templates/incident/tweet/create.tmpl")
-	if err != nil {
-		return 0, err
-	}
-
-	b := &bytes.Buffer{}
-	if err := tmpl.ExecuteTemplate(b, tmpl.Name(), incident); err != nil {
-		return 0, err
-	}
-
-	tweet, _, err := client.Statuses.Update(b.String(), nil)
-	if err != nil {
-		return 0, err
-	}
-
-	return tweet.ID, nil
-}
-
-// SendIncidentUpdateTwitter sends the incident update info to the offical Rocket.Chat Cloud twitter account.
-func SendIncidentUpdateTwitter(incident *models.Incident, update *models.StatusUpdate) (int64, error) {
-	conf := oauth1.NewConfig(config.Config.Twitter.ConsumerKey, config.Config.Twitter.ConsumerSecret)
-	token := oauth1.NewToken(config.Config.Twitter.AccessToken, config.Config.Twitter.AccessSecret)
-	http := conf.Client(oauth1.NoContext, token)
-	http.Timeout = 5 * time.Second
-
-	client := twitter.NewClient(http)
-	tmpl, err := template.ParseFiles("templates/incident/tweet/update.tmpl")
-	if err != nil {
-		return 0, err
-	}
-
-	b := &bytes.Buffer{}
-	if err := tmpl.ExecuteTemplate(b, tmpl.Name(), map[string]interface{}{
-		"update":   update,
-		"incident": incident,
-	}); err != nil {
-		return 0, err
-	}
-
-	var params = &twitter.StatusUpdateParams{}
-	if incident.OriginalTweetID != 0 {
-		params.InReplyToStatusID = incident.OriginalTweetID
-	}
-
-	tweet, _, err := client.Statuses.Update(fmt.Sprintf("%s", b.String()), params)
-	if err != nil {
-		return 0, err
-	}
-
-	return tweet.ID, nil
-}
-
-// CreateIncident creates the incident in the storage layer
-func CreateIncident(incident *models.Incident) (*models.Incident, error) {
+// CreateIncident creates the incident in the storage layer, the actor is who created it
+func CreateIncident(incident *models.Incident, actor string) (*models.Incident, error) {
 	ensureIncidentDefaults(incident)
 
+	// Postmortems are written once the incident is resolved
+	incident.Postmortem = nil
+
 	if incident.Status == models.IncidentStatusScheduledMaintenance {
 		incident.IsMaintenance = true
 	}
@@ -115,58 +55,125 @@ func CreateIncident(incident *models.Incident) (*models.Incident, error) {
 		}
 	}
 
-	if incident.IsMaintenance {
-		for _, s := range incident.Services {
+	// Updates given with the incident, like the message of a template, happen when it does
+	for _, update := range incident.Updates {
+		if update.Time.IsZero() {
+			update.Time = incident.Time
+		}
+	}
 
-			if err := updateServiceToStatus(s.Name, models.ServiceStatusScheduledMaintenance); err != nil {
-				return nil, err
-			}
+	// The services are checked up front, their statuses can only be updated once the incident has an id
+	if err := validateServiceUpdates(incident.Services, !incident.IsMaintenance); err != nil {
+		return nil, err
+	}
 
-			for _, regionCode := range s.Regions {
-				if err := updateRegionToStatus(regionCode, s.Name, models.ServiceStatusScheduledMaintenance); err != nil {
-					return nil, err
-				}
-			}
+	if err := _dataStore.CreateIncident(incident); err != nil {
+		return nil, err
+	}
+
+	if err := deriveStatuses(statusChangeCause{IncidentID: incident.ID, Actor: actor}); err != nil {
+		return nil, err
+	}
+
+	notify(&models.Event{
+		Type:     models.EventIncidentCreated,
+		Incident: incident,
+	})
+
+	return incident, nil
+}
+
+// GetOpenIncidents gets the incidents which aren't resolved, newest first
+func GetOpenIncidents() ([]*models.Incident, error) {
+	return _dataStore.GetOpenIncidents()
+}
+
+// ErrInvalidIncidentPatch is returned when the changes to an incident aren't valid
+var ErrInvalidIncidentPatch = errors.New("invalid incident")
+
+// PatchIncident changes the title, start time, affected services and postmortem link of the incident, the
+// fields left empty stay as they were. The incident is nil when it doesn't exist.
+func PatchIncident(patch *models.Incident, actor string) (*models.Incident, error) {
+	incident, err := _dataStore.GetIncidentByID(patch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if incident == nil {
+		return nil, nil
+	}
+
+	if patch.Title != "" {
+		incident.Title = patch.Title
+	}
+
+	if !patch.Time.IsZero() {
+		if patch.Time.After(time.Now()) {
+			return nil, fmt.Errorf("%w: the time can't be in the future", ErrInvalidIncidentPatch)
 		}
 
-	} else {
-		for _, s := range incident.Services {
-			if err := updateServiceToStatus(s.Name, s.Status); err != nil {
-				return nil, err
-			}
+		incident.Time = patch.Time
+	}
 
-			for _, regionCode := range s.Regions {
-				if err := updateRegionToStatus(regionCode, s.Name, s.Status); err != nil {
-					return nil, err
-				}
-			}
+	if patch.PostmortemURL != "" {
+		u, err := url.Parse(patch.PostmortemURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("%w: the postmortem url must be an http or https url", ErrInvalidIncidentPatch)
 		}
+
+		incident.PostmortemURL = patch.PostmortemURL
 	}
 
-	if err := _dataStore.CreateIncident(incident); err != nil {
+	if patch.Services != nil {
+		if err := validateServiceUpdates(patch.Services, !incident.IsMaintenance); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidIncidentPatch, err)
+		}
+
+		incident.Services = patch.Services
+	}
+
+	incident.UpdatedAt = time.Now()
+
+	if err := _dataStore.UpdateIncident(incident); err != nil {
 		return nil, err
 	}
 
-	if config.Config.Twitter.Enabled {
-		tweetID, err := SendIncidentTwitter(incident)
-		if err == nil {
-			incident.OriginalTweetID = tweetID
-			if err := _dataStore.UpdateIncident(incident); err != nil {
-				return nil, err
-			}
+	if patch.Services != nil {
+		if err := deriveStatuses(statusChangeCause{IncidentID: incident.ID, Actor: actor}); err != nil {
+			return nil, err
 		}
 	}
 
 	return incident, nil
 }
 
-// DeleteIncident removes the incident from the storage layer
-func DeleteIncident(id int) error {
-	return _dataStore.DeleteIncident(id)
+// getOpenIncidentBySource gets the unresolved incident which was opened by the source, nil if there is none
+func getOpenIncidentBySource(source string) (*models.Incident, error) {
+	incidents, err := _dataStore.GetOpenIncidents()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, incident := range incidents {
+		if incident.Source == source {
+			return incident, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// DeleteIncident removes the incident from the storage layer, the services it affected no longer are
+func DeleteIncident(id int, actor string) error {
+	if err := _dataStore.DeleteIncident(id); err != nil {
+		return err
+	}
+
+	return deriveStatuses(statusChangeCause{IncidentID: id, Actor: actor})
 }
 
-// CreateIncidentUpdate creates an update for an incident
-func CreateIncidentUpdate(incidentID int, update *models.StatusUpdate) (*models.Incident, error) {
+// CreateIncidentUpdate creates an update for an incident, the actor is who posted it
+func CreateIncidentUpdate(incidentID int, update *models.StatusUpdate, actor string) (*models.Incident, error) {
 	if incidentID <= 0 {
 		return nil, errors.New("invalid incident id")
 	}
@@ -186,6 +193,10 @@ func CreateIncidentUpdate(incidentID int, update *models.StatusUpdate) (*models.
 
 	update.Status = status
 
+	if err := validateServiceUpdates(update.Services, true); err != nil {
+		return nil, err
+	}
+
 	if err := _dataStore.CreateIncidentUpdate(incidentID, update); err != nil {
 		return nil, err
 	}
@@ -195,55 +206,30 @@ func CreateIncidentUpdate(incidentID int, update *models.StatusUpdate) (*models.
 		return nil, err
 	}
 
+	// Once resolved the incident no longer affects its services, whatever the update says about them
 	if status != models.IncidentStatusResolved {
-		for _, s := range update.Services {
-			if err := updateServiceToStatus(s.Name, s.Status); err != nil {
-				return nil, err
-			}
-
-			// Update the status on the incident.  Makes it easier for those utilizing api
-			for i, si := range incident.Services {
-				if s.Name == si.Name {
-					incident.Services[i].Status = s.Status
-				}
-			}
-
-			for _, regionCode := range s.Regions {
-				if err := updateRegionToStatus(regionCode, s.Name, s.Status); err != nil {
-					return nil, err
-				}
-			}
-		}
-	} else {
-		for _, s := range incident.Services {
-			s.Status = models.ServiceStatusNominal
-
-			if err := updateServiceToStatus(s.Name, models.ServiceStatusNominal); err != nil {
-				return nil, err
-			}
-
-			for _, regionCode := range s.Regions {
-				if err := updateRegionToStatus(regionCode, s.Name, models.ServiceStatusNominal); err != nil {
-					return nil, err
-				}
-			}
-		}
+		incident.Services = mergeUpdateServices(incident.Services, update.Services)
 	}
 
 	if err := _dataStore.UpdateIncident(incident); err != nil {
 		return nil, err
 	}
 
-	if config.Config.Twitter.Enabled {
-		tweetID, err := SendIncidentUpdateTwitter(incident, update)
-		if err == nil && tweetID != 0 {
-			incident.LatestTweetID = tweetID
-			if err := _dataStore.UpdateIncident(incident); err != nil {
-				return nil, err
-			}
-		}
+	if err := deriveStatuses(statusChangeCause{IncidentID: incidentID, Actor: actor}); err != nil {
+		return nil, err
+	}
+
+	eventType := models.EventIncidentUpdated
+	if status == models.IncidentStatusResolved {
+		eventType = models.EventIncidentResolved
 	}
 
+	notify(&models.Event{
+		Type:     eventType,
+		Incident: incident,
+		Update:   update,
+	})
+
 	return incident, nil
 }
 
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net/smtp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/RocketChat/statuscentral/config"
+)
+
+// sendMail sends a plain text email through the configured smtp server
+func sendMail(to string, subject string, body string) error {
+	port := config.Config.SMTP.Port
+	if port == 0 {
+		port = 25
+	}
+
+	var auth smtp.Auth
+	if config.Config.SMTP.Username != "" {
+		auth = smtp.PlainAuth("", config.Config.SMTP.Username, config.Config.SMTP.Password, config.Config.SMTP.Host)
+	}
+
+	addr := fmt.Sprintf("%s:%d", config.Config.SMTP.Host, port)
+
+	return smtp.SendMail(addr, auth, config.Config.SMTP.From, []string{to}, composeMail(config.Config.SMTP.From, to, subject, body))
+}
+
+// composeMail builds the message with its headers. The subject comes from incident titles, so it's encoded and
+// none of the headers can carry a line break adding headers of its own.
+func composeMail(from string, to string, subject string, body string) []byte {
+	msg := &bytes.Buffer{}
+	fmt.Fprintf(msg, "From: %s\r\n", mailHeaderValue(from))
+	fmt.Fprintf(msg, "To: %s\r\n", mailHeaderValue(to))
+	fmt.Fprintf(msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", mailHeaderValue(subject)))
+	fmt.Fprintf(msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return msg.Bytes()
+}
+
+// mailHeaderValue puts the value on a single line
+func mailHeaderValue(value string) string {
+	return strings.Join(strings.FieldsFunc(value, func(r rune) bool { return r == '\r' || r == '\n' }), " ")
+}
+
+// renderMailTemplate renders one of the email templates with the provided data
+func renderMailTemplate(name string, data map[string]interface{}) (string, error) {
+	tmpl, err := template.ParseFiles("templates/subscriber/email/" + name)
+	if err != nil {
+		return "", err
+	}
+
+	b := &bytes.Buffer{}
+	if err := tmpl.ExecuteTemplate(b, tmpl.Name(), data); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+type queuedMail struct {
+	to      string
+	subject string
+	body    string
+}
+
+// mailQueue holds the emails waiting to be sent, so the requests causing them don't wait for the smtp server
+var mailQueue = make(chan queuedMail, 1000)
+
+// startMailWorker sends the queued emails in the background, one at a time
+func startMailWorker() {
+	go func() {
+		for m := range mailQueue {
+			if err := sendMail(m.to, m.subject, m.body); err != nil {
+				log.Printf("Error while emailing %s: %v\n", m.to, err)
+			}
+		}
+	}()
+}
+
+// queueMail queues the email to be sent, it fails when too many are already waiting
+func queueMail(to string, subject string, body string) error {
+	select {
+	case mailQueue <- queuedMail{to: to, subject: subject, body: body}:
+		return nil
+	default:
+		return errors.New("too many emails are waiting to be sent, try again later")
+	}
+}
+
+// websiteURL returns the public url of the status page without a trailing slash
+func websiteURL() string {
+	return strings.TrimSuffix(config.Config.Website.URL, "/")
+}
@@ -0,0 +1,40 @@
+package core
+
+import (
+	"bytes"
+	"mime"
+	"net/mail"
+	"testing"
+)
+
+func TestComposeMailKeepsDataOutOfTheHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		to       string
+		subject  string
+		expected string
+	}{
+		{name: "plain subject", to: "user@example.com", subject: "Incident: API down", expected: "Incident: API down"},
+		{name: "unicode subject", to: "user@example.com", subject: "Störung der API", expected: "Störung der API"},
+		{name: "header in the subject", to: "user@example.com", subject: "API down\r\nBcc: victim@example.com", expected: "API down Bcc: victim@example.com"},
+		{name: "bare line feed in the subject", to: "user@example.com", subject: "API down\nBcc: victim@example.com", expected: "API down Bcc: victim@example.com"},
+		{name: "header in the recipient", to: "user@example.com\r\nBcc: victim@example.com", subject: "API down", expected: "API down"},
+	}
+
+	for _, test := range tests {
+		msg, err := mail.ReadMessage(bytes.NewReader(composeMail("status@example.com", test.to, test.subject, "body\n")))
+		if err != nil {
+			t.Errorf("%s: unable to read the mail: %v", test.name, err)
+			continue
+		}
+
+		if bcc := msg.Header.Get("Bcc"); bcc != "" {
+			t.Errorf("%s: expected no Bcc header, got %q", test.name, bcc)
+		}
+
+		subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+		if err != nil || subject != test.expected {
+			t.Errorf("%s: expected the subject %q, got %q (%v)", test.name, test.expected, subject, err)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// maintenanceIncidentConflictHorizon is how far ahead open incidents conflict with maintenance. They have no end,
+// but are expected to be resolved by the time maintenance planned further out starts.
+const maintenanceIncidentConflictHorizon = 24 * time.Hour
+
+// ScheduledMaintenanceConflictError is returned when the window of a scheduled maintenance overlaps with other
+// maintenance or open incidents on the same services, and it wasn't forced
+type ScheduledMaintenanceConflictError struct {
+	Conflicts []models.MaintenanceConflict
+}
+
+func (e *ScheduledMaintenanceConflictError) Error() string {
+	conflicts := make([]string, 0, len(e.Conflicts))
+	for _, conflict := range e.Conflicts {
+		kind := "maintenance"
+		if conflict.Type == models.MaintenanceConflictIncident {
+			kind = "incident"
+		}
+
+		conflicts = append(conflicts, fmt.Sprintf("%s %d on %s", kind, conflict.ID, strings.Join(conflict.Services, ", ")))
+	}
+
+	return fmt.Sprintf("the scheduled maintenance conflicts with %s", strings.Join(conflicts, "; "))
+}
+
+// GetScheduledMaintenanceConflicts gives the pending maintenance planned at the same time and, when it starts
+// within the conflict horizon, the open incidents which affect any of the services or regions of the scheduled maintenance
+func GetScheduledMaintenanceConflicts(scheduledMaintenance *models.ScheduledMaintenance) ([]models.MaintenanceConflict, error) {
+	conflicts := make([]models.MaintenanceConflict, 0)
+
+	pending, err := GetPendingScheduledMaintenance()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range pending {
+		if m.ID == scheduledMaintenance.ID || !m.PlannedStart.Before(scheduledMaintenance.PlannedEnd) || !scheduledMaintenance.PlannedStart.Before(m.PlannedEnd) {
+			continue
+		}
+
+		if services := overlappingServices(scheduledMaintenance.Services, m.Services); len(services) > 0 {
+			conflicts = append(conflicts, models.MaintenanceConflict{
+				Type:     models.MaintenanceConflictScheduledMaintenance,
+				ID:       m.ID,
+				Title:    m.Title,
+				Services: services,
+				Start:    m.PlannedStart,
+				End:      m.PlannedEnd,
+			})
+		}
+	}
+
+	// Open incidents have no end, so they are taken to last through maintenance starting soon
+	if !scheduledMaintenance.PlannedStart.Before(time.Now().Add(maintenanceIncidentConflictHorizon)) {
+		return conflicts, nil
+	}
+
+	incidents, err := GetOpenIncidents()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, incident := range incidents {
+		if services := overlappingServices(scheduledMaintenance.Services, incident.Services); len(services) > 0 {
+			conflicts = append(conflicts, models.MaintenanceConflict{
+				Type:     models.MaintenanceConflictIncident,
+				ID:       incident.ID,
+				Title:    incident.Title,
+				Services: services,
+				Start:    incident.Time,
+			})
+		}
+	}
+
+	return conflicts, nil
+}
+
+// checkScheduledMaintenanceConflicts gets the conflicts of the maintenance, which are an error unless forced
+func checkScheduledMaintenanceConflicts(scheduledMaintenance *models.ScheduledMaintenance, force bool) ([]models.MaintenanceConflict, error) {
+	conflicts, err := GetScheduledMaintenanceConflicts(scheduledMaintenance)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(conflicts) > 0 && !force {
+		return nil, &ScheduledMaintenanceConflictError{Conflicts: conflicts}
+	}
+
+	return conflicts, nil
+}
+
+// logScheduledMaintenanceConflicts logs the conflicts of maintenance which was planned without anyone to confirm them
+func logScheduledMaintenanceConflicts(id int, conflicts []models.MaintenanceConflict) {
+	if len(conflicts) > 0 {
+		log.Printf("Scheduled maintenance %d was planned despite conflicts: %v\n", id, &ScheduledMaintenanceConflictError{Conflicts: conflicts})
+	}
+}
+
+// overlappingServices gives the services both lists affect, a service without regions affects all of its regions
+func overlappingServices(a []models.ServiceUpdate, b []models.ServiceUpdate) []string {
+	overlapping := make([]string, 0)
+
+	for _, s := range a {
+		for _, o := range b {
+			if s.Name != o.Name {
+				continue
+			}
+
+			if len(s.Regions) == 0 || len(o.Regions) == 0 {
+				overlapping = append(overlapping, s.Name)
+				continue
+			}
+
+			regions := make([]string, 0)
+			for _, regionCode := range s.Regions {
+				if stringInSlice(regionCode, o.Regions) {
+					regions = append(regions, regionCode)
+				}
+			}
+
+			if len(regions) > 0 {
+				sort.Strings(regions)
+				overlapping = append(overlapping, fmt.Sprintf("%s (%s)", s.Name, strings.Join(regions, ", ")))
+			}
+		}
+	}
+
+	return overlapping
+}
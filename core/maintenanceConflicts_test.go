@@ -0,0 +1,184 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+func TestOverlappingServices(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        []models.ServiceUpdate
+		b        []models.ServiceUpdate
+		expected []string
+	}{
+		{
+			name:     "other services",
+			a:        []models.ServiceUpdate{{Name: "api"}},
+			b:        []models.ServiceUpdate{{Name: "web"}},
+			expected: []string{},
+		},
+		{
+			name:     "same service",
+			a:        []models.ServiceUpdate{{Name: "api"}, {Name: "web"}},
+			b:        []models.ServiceUpdate{{Name: "web"}},
+			expected: []string{"web"},
+		},
+		{
+			name:     "whole service and one of its regions",
+			a:        []models.ServiceUpdate{{Name: "api"}},
+			b:        []models.ServiceUpdate{{Name: "api", Regions: []string{"eu"}}},
+			expected: []string{"api"},
+		},
+		{
+			name:     "one region and the whole service",
+			a:        []models.ServiceUpdate{{Name: "api", Regions: []string{"eu"}}},
+			b:        []models.ServiceUpdate{{Name: "api"}},
+			expected: []string{"api"},
+		},
+		{
+			name:     "other regions",
+			a:        []models.ServiceUpdate{{Name: "api", Regions: []string{"eu"}}},
+			b:        []models.ServiceUpdate{{Name: "api", Regions: []string{"us", "ap"}}},
+			expected: []string{},
+		},
+		{
+			name:     "shared regions",
+			a:        []models.ServiceUpdate{{Name: "api", Regions: []string{"us", "eu", "ap"}}},
+			b:        []models.ServiceUpdate{{Name: "api", Regions: []string{"ap", "us"}}},
+			expected: []string{"api (ap, us)"},
+		},
+		{
+			name:     "same region code on other services",
+			a:        []models.ServiceUpdate{{Name: "api", Regions: []string{"eu"}}},
+			b:        []models.ServiceUpdate{{Name: "web", Regions: []string{"eu"}}},
+			expected: []string{},
+		},
+		{
+			name:     "nothing affected",
+			a:        nil,
+			b:        []models.ServiceUpdate{{Name: "api"}},
+			expected: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		if overlapping := overlappingServices(test.a, test.b); !reflect.DeepEqual(overlapping, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, overlapping)
+		}
+	}
+}
+
+func TestGetScheduledMaintenanceConflicts(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+
+	create := func(m *models.ScheduledMaintenance) *models.ScheduledMaintenance {
+		if err := _dataStore.CreateScheduledMaintenance(m); err != nil {
+			t.Fatalf("unable to create the maintenance: %v", err)
+		}
+
+		t.Cleanup(func() {
+			_dataStore.DeleteScheduledMaintenance(m.ID) //nolint:errcheck
+		})
+
+		return m
+	}
+
+	overlapping := create(&models.ScheduledMaintenance{
+		Title:        "Database upgrade",
+		Services:     []models.ServiceUpdate{{Name: "api", Regions: []string{"eu"}}},
+		PlannedStart: start.Add(time.Hour),
+		PlannedEnd:   start.Add(3 * time.Hour),
+	})
+
+	// Right after the window, in the same region
+	create(&models.ScheduledMaintenance{
+		Title:        "Cache upgrade",
+		Services:     []models.ServiceUpdate{{Name: "api", Regions: []string{"eu"}}},
+		PlannedStart: start.Add(2 * time.Hour),
+		PlannedEnd:   start.Add(4 * time.Hour),
+	})
+
+	// During the window, in another region
+	create(&models.ScheduledMaintenance{
+		Title:        "Network change",
+		Services:     []models.ServiceUpdate{{Name: "api", Regions: []string{"us"}}},
+		PlannedStart: start,
+		PlannedEnd:   start.Add(2 * time.Hour),
+	})
+
+	// During the window, but already cancelled
+	create(&models.ScheduledMaintenance{
+		Title:        "Cancelled",
+		Services:     []models.ServiceUpdate{{Name: "api"}},
+		PlannedStart: start,
+		PlannedEnd:   start.Add(2 * time.Hour),
+		Cancelled:    true,
+	})
+
+	conflicts, err := GetScheduledMaintenanceConflicts(&models.ScheduledMaintenance{
+		Services:     []models.ServiceUpdate{{Name: "api", Regions: []string{"eu", "ap"}}},
+		PlannedStart: start,
+		PlannedEnd:   start.Add(2 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("unable to get the conflicts: %v", err)
+	}
+
+	if len(conflicts) != 1 {
+		t.Fatalf("expected only the overlapping maintenance to conflict, got %+v", conflicts)
+	}
+
+	if conflicts[0].Type != models.MaintenanceConflictScheduledMaintenance || conflicts[0].ID != overlapping.ID || !reflect.DeepEqual(conflicts[0].Services, []string{"api (eu)"}) {
+		t.Errorf("expected maintenance %d to conflict on api (eu), got %+v", overlapping.ID, conflicts[0])
+	}
+}
+
+func TestGetScheduledMaintenanceConflictsWithOpenIncidents(t *testing.T) {
+	newTestService(t, "conflict-incident", "eu", "us")
+
+	incident := newTestIncident(t, &models.Incident{
+		Title:    "Errors in eu",
+		Status:   models.IncidentStatusInvestigating,
+		Services: []models.ServiceUpdate{{Name: "conflict-incident", Status: models.ServiceStatusDegraded, Regions: []string{"eu"}}},
+	})
+
+	tests := []struct {
+		name     string
+		start    time.Duration
+		regions  []string
+		conflict bool
+	}{
+		{name: "starting soon in the region", start: time.Hour, regions: []string{"eu"}, conflict: true},
+		{name: "starting soon on the whole service", start: time.Hour, conflict: true},
+		{name: "starting soon in another region", start: time.Hour, regions: []string{"us"}},
+		{name: "starting past the horizon", start: maintenanceIncidentConflictHorizon + time.Hour, regions: []string{"eu"}},
+	}
+
+	for _, test := range tests {
+		start := time.Now().Add(test.start)
+
+		conflicts, err := GetScheduledMaintenanceConflicts(&models.ScheduledMaintenance{
+			Services:     []models.ServiceUpdate{{Name: "conflict-incident", Regions: test.regions}},
+			PlannedStart: start,
+			PlannedEnd:   start.Add(time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("%s: unable to get the conflicts: %v", test.name, err)
+		}
+
+		found := false
+		for _, conflict := range conflicts {
+			if conflict.Type == models.MaintenanceConflictIncident && conflict.ID == incident.ID {
+				found = true
+			}
+		}
+
+		if found != test.conflict {
+			t.Errorf("%s: expected conflict %v with the incident, got %+v", test.name, test.conflict, conflicts)
+		}
+	}
+}
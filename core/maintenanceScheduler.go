@@ -0,0 +1,163 @@
+package core
+
+import (
+	"log"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+const (
+	maintenanceSchedulerActor = "scheduler"
+
+	// maintenanceSchedulerInterval is the longest the scheduler sleeps, even when nothing is due before
+	maintenanceSchedulerInterval = time.Minute
+)
+
+// maintenanceSchedulerWakeUp makes the scheduler look at the maintenance windows again after one of them changed
+var maintenanceSchedulerWakeUp = make(chan struct{}, 1)
+
+// startMaintenanceScheduler starts and completes the scheduled maintenance at its planned start and end in
+// the background. The first run happens right away, so whatever was due while the server was down catches up.
+func startMaintenanceScheduler() {
+	go func() {
+		for {
+			next := processScheduledMaintenanceWindows(time.Now())
+
+			wait := maintenanceSchedulerInterval
+			if !next.IsZero() && time.Until(next) < wait {
+				wait = time.Until(next)
+			}
+
+			timer := time.NewTimer(wait)
+
+			select {
+			case <-timer.C:
+			case <-maintenanceSchedulerWakeUp:
+				timer.Stop()
+			}
+		}
+	}()
+}
+
+// wakeUpMaintenanceScheduler is called after the window of a maintenance changed, so it's acted on in time
+func wakeUpMaintenanceScheduler() {
+	select {
+	case maintenanceSchedulerWakeUp <- struct{}{}:
+	default:
+	}
+}
+
+// processScheduledMaintenanceWindows schedules the upcoming occurrences of recurring maintenance, starts the maintenance
+// whose planned start passed and completes the maintenance whose planned end passed. It gives the next time one of
+// them is due, zero when none is.
+func processScheduledMaintenanceWindows(now time.Time) time.Time {
+	scheduleRecurringMaintenance(now)
+
+	pending, err := GetPendingScheduledMaintenance()
+	if err != nil {
+		log.Println("Error while getting the pending scheduled maintenance:", err)
+		return time.Time{}
+	}
+
+	next := time.Time{}
+	due := func(t time.Time) {
+		if next.IsZero() || t.Before(next) {
+			next = t
+		}
+	}
+
+	for _, m := range pending {
+		if !m.PlannedEnd.After(now) {
+			if err := completeScheduledMaintenance(m.ID, now); err != nil {
+				log.Printf("Error while completing the scheduled maintenance %d: %v\n", m.ID, err)
+			}
+
+			continue
+		}
+
+		// Maintenance started early is picked up as is, the updates posted before the start don't count
+		if !m.Started {
+			if m.PlannedStart.After(now) {
+				due(m.PlannedStart)
+				continue
+			}
+
+			if err := startDueScheduledMaintenance(m.ID, now); err != nil {
+				log.Printf("Error while starting the scheduled maintenance %d: %v\n", m.ID, err)
+			}
+		}
+
+		due(m.PlannedEnd)
+	}
+
+	return next
+}
+
+// startDueScheduledMaintenance starts the maintenance once its planned start passed. It's loaded again under the
+// maintenance lock, as it may have been cancelled, started early or moved since the scheduler looked at it.
+func startDueScheduledMaintenance(id int, now time.Time) error {
+	maintenanceLock.Lock()
+	defer maintenanceLock.Unlock()
+
+	m, err := _dataStore.GetScheduledMaintenanceByID(id)
+	if err != nil || m == nil {
+		return err
+	}
+
+	if m.Started || m.Completed || m.Cancelled || m.PlannedStart.After(now) {
+		return nil
+	}
+
+	return startScheduledMaintenance(m, "The scheduled maintenance has started.", maintenanceSchedulerActor)
+}
+
+// startScheduledMaintenance posts the update starting the maintenance, which puts its services under maintenance.
+// The maintenance lock has to be held.
+func startScheduledMaintenance(m *models.ScheduledMaintenance, message string, actor string) error {
+	services := make([]models.ServiceUpdate, 0, len(m.Services))
+	for _, s := range m.Services {
+		s.Status = models.ServiceStatusScheduledMaintenance
+		services = append(services, s)
+	}
+
+	_, err := createScheduledMaintenanceUpdate(m.ID, &models.StatusUpdate{
+		Time:     time.Now(),
+		Status:   models.IncidentStatusUpdate,
+		Message:  message,
+		Services: services,
+	}, actor, true)
+
+	return err
+}
+
+// completeScheduledMaintenance posts the resolved update of the maintenance once its planned end passed, which gives
+// its services back. Maintenance whose whole window passed while the server was down is started first, so it's
+// recorded like any other.
+func completeScheduledMaintenance(id int, now time.Time) error {
+	maintenanceLock.Lock()
+	defer maintenanceLock.Unlock()
+
+	m, err := _dataStore.GetScheduledMaintenanceByID(id)
+	if err != nil || m == nil {
+		return err
+	}
+
+	if m.Completed || m.Cancelled || m.PlannedEnd.After(now) {
+		return nil
+	}
+
+	if !m.Started {
+		if err := startScheduledMaintenance(m, "The scheduled maintenance has started.", maintenanceSchedulerActor); err != nil {
+			return err
+		}
+	}
+
+	_, err = createScheduledMaintenanceUpdate(m.ID, &models.StatusUpdate{
+		Time:    time.Now(),
+		Status:  models.IncidentStatusResolved,
+		Message: "The scheduled maintenance has been completed.",
+	}, maintenanceSchedulerActor, false)
+
+	return err
+}
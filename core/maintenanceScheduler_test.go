@@ -0,0 +1,220 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// newTestScheduledMaintenance stores the maintenance as is, so its window can already have passed like after downtime
+func newTestScheduledMaintenance(t *testing.T, service string, start time.Time, end time.Time) *models.ScheduledMaintenance {
+	m := &models.ScheduledMaintenance{
+		Title:        "Maintenance of " + service,
+		PlannedStart: start,
+		PlannedEnd:   end,
+		Services:     []models.ServiceUpdate{{Name: service}},
+		Updates:      make([]*models.StatusUpdate, 0),
+	}
+
+	if err := _dataStore.CreateScheduledMaintenance(m); err != nil {
+		t.Fatalf("unable to create the maintenance: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_dataStore.DeleteScheduledMaintenance(m.ID)      //nolint:errcheck
+		deriveStatuses(statusChangeCause{Actor: "test"}) //nolint:errcheck
+	})
+
+	return m
+}
+
+func getTestScheduledMaintenance(t *testing.T, id int) *models.ScheduledMaintenance {
+	m, err := _dataStore.GetScheduledMaintenanceByID(id)
+	if err != nil || m == nil {
+		t.Fatalf("unable to get the maintenance %d: %v", id, err)
+	}
+
+	return m
+}
+
+func TestProcessScheduledMaintenanceWindows(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		start     time.Time
+		end       time.Time
+		started   bool
+		completed bool
+		status    models.ServiceAndRegionStatus
+	}{
+		{
+			name:   "not due yet",
+			start:  now.Add(time.Hour),
+			end:    now.Add(2 * time.Hour),
+			status: models.ServiceStatusNominal,
+		},
+		{
+			name:    "start passed",
+			start:   now.Add(-time.Minute),
+			end:     now.Add(time.Hour),
+			started: true,
+			status:  models.ServiceStatusScheduledMaintenance,
+		},
+		{
+			name:      "whole window passed while down",
+			start:     now.Add(-2 * time.Hour),
+			end:       now.Add(-time.Hour),
+			started:   true,
+			completed: true,
+			status:    models.ServiceStatusNominal,
+		},
+	}
+
+	for i, test := range tests {
+		service := newTestService(t, "scheduler-"+string(rune('a'+i)))
+		m := newTestScheduledMaintenance(t, service.Name, test.start, test.end)
+
+		processScheduledMaintenanceWindows(now)
+
+		m = getTestScheduledMaintenance(t, m.ID)
+		if m.Started != test.started || m.Completed != test.completed {
+			t.Errorf("%s: expected started %v and completed %v, got %v and %v", test.name, test.started, test.completed, m.Started, m.Completed)
+		}
+
+		if status := serviceStatus(t, service.Name, ""); status != test.status {
+			t.Errorf("%s: expected the service to be %s, got %s", test.name, test.status, status)
+		}
+	}
+}
+
+func TestProcessScheduledMaintenanceWindowsCompletesStartedMaintenance(t *testing.T) {
+	service := newTestService(t, "scheduler-complete")
+	m := newTestScheduledMaintenance(t, service.Name, time.Now().Add(-time.Minute), time.Now().Add(time.Hour))
+
+	processScheduledMaintenanceWindows(time.Now())
+
+	if status := serviceStatus(t, service.Name, ""); status != models.ServiceStatusScheduledMaintenance {
+		t.Fatalf("expected the service to be under maintenance once started, got %s", status)
+	}
+
+	// The planned end passed on the next run
+	processScheduledMaintenanceWindows(time.Now().Add(2 * time.Hour))
+
+	m = getTestScheduledMaintenance(t, m.ID)
+	if !m.Completed {
+		t.Error("expected the maintenance to be completed")
+	}
+
+	// Completed once, the started update isn't posted again
+	if len(m.Updates) != 2 {
+		t.Errorf("expected the started and completed updates, got %d updates", len(m.Updates))
+	}
+
+	if status := serviceStatus(t, service.Name, ""); status != models.ServiceStatusNominal {
+		t.Errorf("expected the service to be given back, got %s", status)
+	}
+}
+
+func TestSchedulerSkipsMaintenanceCancelledDuringTheTick(t *testing.T) {
+	service := newTestService(t, "scheduler-cancelled")
+	m := newTestScheduledMaintenance(t, service.Name, time.Now().Add(-time.Minute), time.Now().Add(time.Hour))
+
+	// Cancelled after the scheduler got the pending maintenance, but before it started it
+	if _, err := CancelScheduledMaintenance(m.ID, "", "test"); err != nil {
+		t.Fatalf("unable to cancel the maintenance: %v", err)
+	}
+
+	now := time.Now()
+	if err := startDueScheduledMaintenance(m.ID, now); err != nil {
+		t.Fatalf("unable to start the due maintenance: %v", err)
+	}
+
+	if err := completeScheduledMaintenance(m.ID, now.Add(2*time.Hour)); err != nil {
+		t.Fatalf("unable to complete the due maintenance: %v", err)
+	}
+
+	m = getTestScheduledMaintenance(t, m.ID)
+	if m.Started || m.Completed {
+		t.Errorf("expected the cancelled maintenance to stay as it was, got started %v and completed %v", m.Started, m.Completed)
+	}
+
+	if status := serviceStatus(t, service.Name, ""); status != models.ServiceStatusNominal {
+		t.Errorf("expected the service to stay nominal, got %s", status)
+	}
+}
+
+func TestCreateScheduledMaintenanceUpdateRejectsInvalidStates(t *testing.T) {
+	service := newTestService(t, "scheduler-updates")
+
+	pending := newTestScheduledMaintenance(t, service.Name, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+
+	cancelled := newTestScheduledMaintenance(t, service.Name, time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+	if _, err := CancelScheduledMaintenance(cancelled.ID, "", "test"); err != nil {
+		t.Fatalf("unable to cancel the maintenance: %v", err)
+	}
+
+	completed := newTestScheduledMaintenance(t, service.Name, time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour))
+	if err := completeScheduledMaintenance(completed.ID, time.Now()); err != nil {
+		t.Fatalf("unable to complete the maintenance: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		id     int
+		status models.IncidentStatus
+		valid  bool
+	}{
+		{name: "reminder before the start", id: pending.ID, status: models.IncidentStatusUpdate, valid: true},
+		{name: "resolved before the start", id: pending.ID, status: models.IncidentStatusResolved},
+		{name: "update on cancelled maintenance", id: cancelled.ID, status: models.IncidentStatusUpdate},
+		{name: "update on completed maintenance", id: completed.ID, status: models.IncidentStatusUpdate},
+	}
+
+	for _, test := range tests {
+		_, err := CreateScheduledMaintenanceUpdate(test.id, &models.StatusUpdate{
+			Time:    time.Now(),
+			Status:  test.status,
+			Message: test.name,
+		}, "test")
+
+		if test.valid && err != nil {
+			t.Errorf("%s: expected the update to be posted, got %v", test.name, err)
+		}
+
+		if !test.valid && !errors.Is(err, ErrInvalidScheduledMaintenanceAction) {
+			t.Errorf("%s: expected an invalid action, got %v", test.name, err)
+		}
+	}
+}
+
+func TestScheduledMaintenanceEndMustBeAfterStart(t *testing.T) {
+	service := newTestService(t, "scheduler-window")
+	start := time.Now().Add(time.Hour)
+
+	for _, end := range []time.Time{start, start.Add(-time.Minute)} {
+		_, _, err := CreateScheduledMaintenance(&models.ScheduledMaintenance{
+			Title:        "Backwards",
+			PlannedStart: start,
+			PlannedEnd:   end,
+			Services:     []models.ServiceUpdate{{Name: service.Name}},
+		}, true)
+
+		if !errors.Is(err, ErrInvalidScheduledMaintenance) {
+			t.Errorf("expected creating maintenance ending at %s to be invalid, got %v", end.Sub(start), err)
+		}
+	}
+
+	m := newTestScheduledMaintenance(t, service.Name, start, start.Add(time.Hour))
+
+	_, err := PatchScheduledMaintenance(&models.ScheduledMaintenance{
+		ID:         m.ID,
+		PlannedEnd: start.Add(-time.Minute),
+	}, true, "test")
+
+	if !errors.Is(err, ErrInvalidScheduledMaintenance) {
+		t.Errorf("expected moving the end before the start to be invalid, got %v", err)
+	}
+}
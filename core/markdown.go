@@ -0,0 +1,157 @@
+package core
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// markdownHeadingOffset moves the headings of postmortems below the headings of the page they are shown on
+const markdownHeadingOffset = 2
+
+var (
+	markdownOrderedItem = regexp.MustCompile(`^\d+\.\s+`)
+	markdownLink        = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^\s)]+)\)`)
+	markdownBold        = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	markdownItalic      = regexp.MustCompile(`\*([^*]+)\*`)
+)
+
+// RenderMarkdown turns markdown, like the body of a postmortem, into html. It supports headings, paragraphs, lists,
+// code blocks, bold, italic, inline code and http links. The text is escaped first, so no html of its own gets through.
+func RenderMarkdown(text string) template.HTML {
+	var b strings.Builder
+
+	paragraph := make([]string, 0)
+	list := ""
+	code := false
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>" + renderMarkdownInline(strings.Join(paragraph, " ")) + "</p>\n")
+			paragraph = paragraph[:0]
+		}
+	}
+
+	closeList := func() {
+		if list != "" {
+			b.WriteString("</" + list + ">\n")
+			list = ""
+		}
+	}
+
+	openList := func(kind string) {
+		flushParagraph()
+
+		if list != kind {
+			closeList()
+			b.WriteString("<" + kind + ">\n")
+			list = kind
+		}
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		if code {
+			if strings.HasPrefix(trimmed, "```") {
+				b.WriteString("</code></pre>\n")
+				code = false
+				continue
+			}
+
+			b.WriteString(html.EscapeString(line) + "\n")
+			continue
+		}
+
+		level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+
+		switch {
+		case strings.HasPrefix(trimmed, "```"):
+			flushParagraph()
+			closeList()
+			b.WriteString("<pre><code>")
+			code = true
+		case trimmed == "":
+			flushParagraph()
+			closeList()
+		case level > 0 && level <= 6 && strings.HasPrefix(trimmed[level:], " "):
+			flushParagraph()
+			closeList()
+
+			heading := level + markdownHeadingOffset
+			if heading > 6 {
+				heading = 6
+			}
+
+			tag := fmt.Sprintf("h%d", heading)
+			b.WriteString("<" + tag + ">" + renderMarkdownInline(strings.TrimSpace(trimmed[level:])) + "</" + tag + ">\n")
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			openList("ul")
+			b.WriteString("<li>" + renderMarkdownInline(strings.TrimSpace(trimmed[2:])) + "</li>\n")
+		case markdownOrderedItem.MatchString(trimmed):
+			openList("ol")
+			b.WriteString("<li>" + renderMarkdownInline(markdownOrderedItem.ReplaceAllString(trimmed, "")) + "</li>\n")
+		default:
+			closeList()
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+
+	if code {
+		b.WriteString("</code></pre>\n")
+	}
+
+	flushParagraph()
+	closeList()
+
+	// The text was escaped above, only the tags added here are html
+	return template.HTML(b.String())
+}
+
+// renderMarkdownInline renders the inline code, links and emphasis of a line, nothing is rendered inside inline code
+func renderMarkdownInline(text string) string {
+	parts := strings.Split(text, "`")
+
+	for i, part := range parts {
+		part = html.EscapeString(part)
+
+		if i%2 == 1 && i < len(parts)-1 {
+			parts[i] = "<code>" + part + "</code>"
+			continue
+		}
+
+		parts[i] = renderMarkdownLinks(part)
+	}
+
+	// An unmatched backtick is kept as it is
+	if len(parts)%2 == 0 {
+		last := len(parts) - 1
+		return strings.Join(parts[:last], "") + "`" + parts[last]
+	}
+
+	return strings.Join(parts, "")
+}
+
+// renderMarkdownLinks renders the http links of the escaped text and the emphasis around and inside of them,
+// the urls are left as they are so no tags end up in the href
+func renderMarkdownLinks(text string) string {
+	var b strings.Builder
+
+	last := 0
+	for _, m := range markdownLink.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(renderMarkdownEmphasis(text[last:m[0]]))
+		b.WriteString(`<a href="` + text[m[4]:m[5]] + `">` + renderMarkdownEmphasis(text[m[2]:m[3]]) + "</a>")
+		last = m[1]
+	}
+
+	b.WriteString(renderMarkdownEmphasis(text[last:]))
+
+	return b.String()
+}
+
+func renderMarkdownEmphasis(text string) string {
+	text = markdownBold.ReplaceAllString(text, "<strong>$1</strong>")
+	return markdownItalic.ReplaceAllString(text, "<em>$1</em>")
+}
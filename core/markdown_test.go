@@ -0,0 +1,80 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestRenderMarkdownEscapesHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		expected string
+	}{
+		{
+			name:     "raw html",
+			markdown: `<script>alert(1)</script> and <img src=x onerror=alert(1)>`,
+			expected: "<p>&lt;script&gt;alert(1)&lt;/script&gt; and &lt;img src=x onerror=alert(1)&gt;</p>\n",
+		},
+		{
+			name:     "javascript link",
+			markdown: `[click](javascript:alert(1))`,
+			expected: "<p>[click](javascript:alert(1))</p>\n",
+		},
+		{
+			name:     "link without a scheme",
+			markdown: `[click](//evil.example.com)`,
+			expected: "<p>[click](//evil.example.com)</p>\n",
+		},
+		{
+			name:     "quote in the url",
+			markdown: `[click](https://example.com/"onmouseover="alert(1))`,
+			expected: "<p><a href=\"https://example.com/&#34;onmouseover=&#34;alert(1\">click</a>)</p>\n",
+		},
+		{
+			name:     "nested brackets",
+			markdown: `[[inner](https://example.com)](javascript:alert(1))`,
+			expected: "<p><a href=\"https://example.com\">[inner</a>](javascript:alert(1))</p>\n",
+		},
+		{
+			name:     "emphasis in the url",
+			markdown: `[docs](https://example.com/*a*/**b**) *c*`,
+			expected: "<p><a href=\"https://example.com/*a*/**b**\">docs</a> <em>c</em></p>\n",
+		},
+		{
+			name:     "emphasis in the link text",
+			markdown: `[the **status** page](https://example.com/?a=1&b=2)`,
+			expected: "<p><a href=\"https://example.com/?a=1&amp;b=2\">the <strong>status</strong> page</a></p>\n",
+		},
+		{
+			name:     "html in inline code",
+			markdown: "Run `<b onclick=\"x\">` and **`*a*`**",
+			expected: "<p>Run <code>&lt;b onclick=&#34;x&#34;&gt;</code> and **<code>*a*</code>**</p>\n",
+		},
+		{
+			name:     "link in inline code",
+			markdown: "`[a](https://example.com)`",
+			expected: "<p><code>[a](https://example.com)</code></p>\n",
+		},
+		{
+			name:     "unmatched backtick",
+			markdown: "a `<b>",
+			expected: "<p>a `&lt;b&gt;</p>\n",
+		},
+		{
+			name:     "html in a code block",
+			markdown: "```\n<script>alert(1)</script>\n```",
+			expected: "<pre><code>&lt;script&gt;alert(1)&lt;/script&gt;\n</code></pre>\n",
+		},
+		{
+			name:     "html in a heading and a list",
+			markdown: "# <h1>Title\n- <li>item",
+			expected: "<h3>&lt;h1&gt;Title</h3>\n<ul>\n<li>&lt;li&gt;item</li>\n</ul>\n",
+		},
+	}
+
+	for _, test := range tests {
+		if actual := string(RenderMarkdown(test.markdown)); actual != test.expected {
+			t.Errorf("%s: expected %q, got %q", test.name, test.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+func init() {
+	RegisterNotifierType("mastodon", newMastodonNotifier)
+}
+
+// mastodonNotifier posts statuses about the incidents and scheduled maintenance, with the updates as replies to the original status.
+// It uses the same templates as the tweets.
+type mastodonNotifier struct {
+	name        string
+	server      string
+	accessToken string
+	visibility  string
+	client      *http.Client
+}
+
+func newMastodonNotifier(name string, settings NotifierSettings) (Notifier, error) {
+	if err := requireSettings(settings, "server", "accessToken"); err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(settings.String("server"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New("server needs to be a valid http or https url")
+	}
+
+	return &mastodonNotifier{
+		name:        name,
+		server:      strings.TrimSuffix(u.String(), "/"),
+		accessToken: settings.String("accessToken"),
+		visibility:  settings.String("visibility"),
+		client:      &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+func (m *mastodonNotifier) Name() string {
+	return m.name
+}
+
+func (m *mastodonNotifier) Notify(event *models.Event) (string, error) {
+	text, err := RenderTweet(event)
+	if err != nil || text == "" {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("status", text)
+
+	if replyTo := originalNotificationID(event, m.name); replyTo != "" {
+		form.Set("in_reply_to_id", replyTo)
+	}
+
+	if m.visibility != "" {
+		form.Set("visibility", m.visibility)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, m.server+"/api/v1/statuses", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+m.accessToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// Makes sure a retried event doesn't end up being posted twice
+	req.Header.Set("Idempotency-Key", event.ID)
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return "", err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("mastodon responded with status %d", resp.StatusCode)
+	}
+
+	var status struct {
+		ID string `json:"id"`
+	}
+
+	if err := json.Unmarshal(body, &status); err != nil {
+		return "", err
+	}
+
+	return status.ID, nil
+}
@@ -0,0 +1,170 @@
+package core
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/RocketChat/statuscentral/metrics"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// incidentResolutionBuckets are the upper bounds, in seconds, of the time to resolve histogram
+var incidentResolutionBuckets = []float64{
+	(5 * time.Minute).Seconds(),
+	(15 * time.Minute).Seconds(),
+	(30 * time.Minute).Seconds(),
+	time.Hour.Seconds(),
+	(2 * time.Hour).Seconds(),
+	(4 * time.Hour).Seconds(),
+	(8 * time.Hour).Seconds(),
+	(24 * time.Hour).Seconds(),
+	(72 * time.Hour).Seconds(),
+}
+
+var notificationsSent = metrics.NewCounterVec(
+	"statuscentral_notifications_total",
+	"Notifications handed to the notifiers, by whether they succeeded.",
+	"notifier", "event", "result",
+)
+
+func init() {
+	metrics.Register(metrics.CollectorFunc(collectMetrics))
+}
+
+// collectMetrics gives the metrics which are read from the store whenever they are scraped
+func collectMetrics() []*metrics.Family {
+	if _dataStore == nil {
+		return nil
+	}
+
+	families := make([]*metrics.Family, 0)
+
+	services, err := _dataStore.GetServices()
+	if err != nil {
+		log.Println("Error while collecting the service metrics:", err)
+	} else {
+		f := &metrics.Family{
+			Name: "statuscentral_service_status",
+			Help: "Current status of the service: 0 nominal, 1 degraded, 2 partial outage, 3 outage, 4 scheduled maintenance, 5 unknown.",
+			Type: metrics.TypeGauge,
+		}
+
+		for _, s := range services {
+			f.Samples = append(f.Samples, metrics.Sample{
+				Labels: []metrics.Label{{Name: "service", Value: s.Name}},
+				Value:  float64(models.ServiceStatusValues[s.Status.String()]),
+			})
+		}
+
+		families = append(families, f)
+	}
+
+	regions, err := _dataStore.GetRegions()
+	if err != nil {
+		log.Println("Error while collecting the region metrics:", err)
+	} else {
+		f := &metrics.Family{
+			Name: "statuscentral_region_status",
+			Help: "Current status of the region of a service, using the same values as statuscentral_service_status.",
+			Type: metrics.TypeGauge,
+		}
+
+		for _, r := range regions {
+			f.Samples = append(f.Samples, metrics.Sample{
+				Labels: []metrics.Label{{Name: "service", Value: r.ServiceName}, {Name: "region", Value: r.RegionCode}},
+				Value:  float64(models.ServiceStatusValues[r.Status.String()]),
+			})
+		}
+
+		families = append(families, f)
+	}
+
+	incidents, err := _dataStore.GetIncidentsSince(time.Time{})
+	if err != nil {
+		log.Println("Error while collecting the incident metrics:", err)
+	} else {
+		open := 0
+		resolution := metrics.NewHistogramVec(
+			"statuscentral_incident_resolution_seconds",
+			"Time from the start of an incident until it was resolved.",
+			incidentResolutionBuckets,
+		)
+
+		for _, incident := range incidents {
+			resolvedAt, resolved := incidentResolvedAt(incident)
+			if !resolved {
+				open++
+				continue
+			}
+
+			resolution.Observe(resolvedAt.Sub(incident.Time).Seconds())
+		}
+
+		families = append(families, metrics.Gauge("statuscentral_open_incidents", "Number of incidents which aren't resolved.", float64(open)))
+		families = append(families, resolution.Collect()...)
+	}
+
+	scheduledMaintenances, err := _dataStore.GetScheduledMaintenance(false)
+	if err != nil {
+		log.Println("Error while collecting the scheduled maintenance metrics:", err)
+	} else {
+		active := 0
+
+		for _, m := range scheduledMaintenances {
+			if isScheduledMaintenanceInProgress(m) {
+				active++
+			}
+		}
+
+		families = append(families, metrics.Gauge("statuscentral_active_maintenance", "Number of scheduled maintenance windows which started and aren't completed.", float64(active)))
+	}
+
+	families = append(families, dbMetrics()...)
+
+	return families
+}
+
+// incidentResolvedAt gets the time of the update which resolved the incident
+func incidentResolvedAt(incident *models.Incident) (time.Time, bool) {
+	if incident.Status != models.IncidentStatusResolved {
+		return time.Time{}, false
+	}
+
+	for i := len(incident.Updates) - 1; i >= 0; i-- {
+		if incident.Updates[i].Status == models.IncidentStatusResolved {
+			return incident.Updates[i].Time, true
+		}
+	}
+
+	return incident.UpdatedAt, true
+}
+
+func dbMetrics() []*metrics.Family {
+	stats := _dataStore.DBStats()
+
+	names := make([]string, 0, len(stats))
+	for name := range stats {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	families := make([]*metrics.Family, 0, len(names))
+	for _, name := range names {
+		metricType := metrics.TypeGauge
+		if strings.HasSuffix(name, "_total") {
+			metricType = metrics.TypeCounter
+		}
+
+		families = append(families, &metrics.Family{
+			Name:    "statuscentral_db_" + name,
+			Help:    "Database statistic " + strings.Replace(name, "_", " ", -1) + ".",
+			Type:    metricType,
+			Samples: []metrics.Sample{{Value: stats[name]}},
+		})
+	}
+
+	return families
+}
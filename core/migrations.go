@@ -0,0 +1,170 @@
+package core
+
+import (
+	"log"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// startupMigration changes the stored data once, when the server is upgraded to the version needing it
+type startupMigration struct {
+	name string
+	run  func() error
+}
+
+// startupMigrations run in order, new ones go at the end
+var startupMigrations = []startupMigration{
+	{name: "status-history-start", run: migrateStatusHistoryStart},
+	{name: "service-status-override", run: migrateServiceStatusOverride},
+	{name: "scheduled-maintenance-started", run: migrateScheduledMaintenanceStarted},
+}
+
+// runStartupMigrations runs the migrations which didn't run on this database yet
+func runStartupMigrations() error {
+	for _, m := range startupMigrations {
+		applied, err := _dataStore.IsMigrationApplied(m.name)
+		if err != nil {
+			return err
+		}
+
+		if applied {
+			continue
+		}
+
+		log.Printf("Running the %s migration\n", m.name)
+
+		if err := m.run(); err != nil {
+			return err
+		}
+
+		if err := _dataStore.SetMigrationApplied(m.name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// migrateStatusHistoryStart starts the status history of the services and regions which never changed status, so
+// their days have data from now on instead of staying without any
+func migrateStatusHistoryStart() error {
+	services, err := _dataStore.GetServices()
+	if err != nil {
+		return err
+	}
+
+	regions, err := _dataStore.GetRegions()
+	if err != nil {
+		return err
+	}
+
+	for _, service := range services {
+		changes, err := _dataStore.GetStatusChangesByServiceID(service.ID)
+		if err != nil {
+			return err
+		}
+
+		recorded := make(map[string]bool)
+		for _, c := range changes {
+			recorded[c.RegionCode] = true
+		}
+
+		if !recorded[""] {
+			if err := recordInitialServiceStatus(service); err != nil {
+				return err
+			}
+		}
+
+		for _, region := range regions {
+			if region.ServiceID != service.ID || recorded[region.RegionCode] {
+				continue
+			}
+
+			if err := recordInitialRegionStatus(region); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// migrateServiceStatusOverride keeps the statuses set by hand before they were derived. The status of a service
+// which is worse than what its open incidents and maintenance in progress give it was set by hand, so it becomes
+// its manual override.
+func migrateServiceStatusOverride() error {
+	affecting, err := affectingServiceUpdates()
+	if err != nil {
+		return err
+	}
+
+	services, err := _dataStore.GetServices()
+	if err != nil {
+		return err
+	}
+
+	for _, service := range services {
+		// Scheduled maintenance only ever comes from the maintenance, even one which ended while the server was down
+		switch service.Status {
+		case models.ServiceStatusNominal, models.ServiceStatusUnknown, models.ServiceStatusScheduledMaintenance:
+			continue
+		}
+
+		if service.StatusOverride != "" {
+			continue
+		}
+
+		statuses := make([]models.ServiceAndRegionStatus, 0)
+		for _, s := range affecting {
+			if s.Name == service.Name {
+				statuses = append(statuses, s.Status)
+			}
+		}
+
+		if models.WorstServiceStatus(append(statuses, service.Status)...) == models.WorstServiceStatus(statuses...) {
+			continue
+		}
+
+		service.StatusOverride = service.Status
+
+		if err := _dataStore.UpdateService(service); err != nil {
+			return err
+		}
+
+		log.Printf("Kept the %s status of %s as its manual override\n", service.Status, service.Name)
+	}
+
+	return nil
+}
+
+// migrateScheduledMaintenanceStarted marks the maintenance which was started before it was recorded as such, back
+// when the first update started it. Maintenance whose window didn't open yet only got updates ahead of it.
+func migrateScheduledMaintenanceStarted() error {
+	scheduledMaintenances, err := _dataStore.GetScheduledMaintenance(false)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	for _, m := range scheduledMaintenances {
+		if m.Started || len(m.Updates) == 0 || m.PlannedStart.After(now) {
+			continue
+		}
+
+		// Maintenance cancelled before its window only got the update cancelling it
+		if m.Cancelled && !m.CancelledAt.After(m.PlannedStart) {
+			continue
+		}
+
+		m.Started = true
+		m.StartedAt = m.Updates[0].Time
+
+		if err := _dataStore.UpdateScheduledMaintenance(m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,349 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// Notifier gets told about everything which happens to the incidents, scheduled maintenance and services
+type Notifier interface {
+	// Name is the configured name of the notifier, its results are stored under it
+	Name() string
+
+	// Notify sends out the event. The returned id (tweet id, message id, etc) is stored on the
+	// incident or scheduled maintenance so later events can reference it. Notifiers which don't
+	// care about the event simply return an empty id and no error.
+	Notify(event *models.Event) (string, error)
+}
+
+// NotifierFactory creates a notifier from its configured name and settings
+type NotifierFactory func(name string, settings NotifierSettings) (Notifier, error)
+
+// NotifierSettings holds the free form settings of a notifier as defined in the config
+type NotifierSettings map[string]interface{}
+
+// String gets the setting as a string, empty when it isn't set
+func (s NotifierSettings) String(key string) string {
+	value, ok := s[key]
+	if !ok || value == nil {
+		return ""
+	}
+
+	if str, ok := value.(string); ok {
+		return str
+	}
+
+	return fmt.Sprint(value)
+}
+
+// Strings gets the setting as a list of strings, a single string becomes a list of one
+func (s NotifierSettings) Strings(key string) []string {
+	switch value := s[key].(type) {
+	case []string:
+		return value
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			values = append(values, fmt.Sprint(v))
+		}
+
+		return values
+	case string:
+		if value == "" {
+			return nil
+		}
+
+		return []string{value}
+	}
+
+	return nil
+}
+
+type registeredNotifier struct {
+	Notifier
+	events []models.EventType
+}
+
+func (n *registeredNotifier) wantsEvent(eventType models.EventType) bool {
+	if len(n.events) == 0 {
+		return true
+	}
+
+	for _, e := range n.events {
+		if e == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+var (
+	notifierFactories = make(map[string]NotifierFactory)
+	_notifiers        []*registeredNotifier
+)
+
+// RegisterNotifierType makes the notifier type available to be used in the config
+func RegisterNotifierType(notifierType string, factory NotifierFactory) {
+	notifierFactories[notifierType] = factory
+}
+
+// setupNotifiers creates the notifiers defined in the config. The older twitter, rocketchat,
+// subscriptions and webhooks sections still work and each imply a notifier of their type,
+// unless a notifier of the same type is defined explicitly.
+func setupNotifiers() error {
+	notifierConfigs := make([]config.NotifierConfig, 0, len(config.Config.Notifiers)+4)
+	configuredTypes := make(map[string]bool)
+
+	for _, n := range config.Config.Notifiers {
+		configuredTypes[n.Type] = true
+
+		if n.Enabled {
+			notifierConfigs = append(notifierConfigs, n)
+		}
+	}
+
+	if config.Config.Twitter.Enabled && !configuredTypes["twitter"] {
+		notifierConfigs = append(notifierConfigs, config.NotifierConfig{
+			Type: "twitter",
+			Settings: map[string]interface{}{
+				"consumerKey":    config.Config.Twitter.ConsumerKey,
+				"consumerSecret": config.Config.Twitter.ConsumerSecret,
+				"accessToken":    config.Config.Twitter.AccessToken,
+				"accessSecret":   config.Config.Twitter.AccessSecret,
+			},
+		})
+	}
+
+	if config.Config.RocketChat.Enabled && !configuredTypes["rocketchat"] {
+		notifierConfigs = append(notifierConfigs, config.NotifierConfig{
+			Type: "rocketchat",
+			Settings: map[string]interface{}{
+				"webhookUrls": config.Config.RocketChat.WebhookURLs,
+			},
+		})
+	}
+
+	if config.Config.Subscriptions.Enabled && !configuredTypes["email"] {
+		notifierConfigs = append(notifierConfigs, config.NotifierConfig{Type: "email"})
+	}
+
+	if config.Config.Webhooks.Enabled && !configuredTypes["webhooks"] {
+		notifierConfigs = append(notifierConfigs, config.NotifierConfig{Type: "webhooks"})
+	}
+
+	notifiers := make([]*registeredNotifier, 0, len(notifierConfigs))
+	names := make(map[string]bool)
+
+	for _, n := range notifierConfigs {
+		factory, ok := notifierFactories[n.Type]
+		if !ok {
+			return fmt.Errorf("unknown notifier type: %s", n.Type)
+		}
+
+		name := n.Name
+		if name == "" {
+			name = n.Type
+		}
+
+		if names[name] {
+			return fmt.Errorf("duplicate notifier name: %s", name)
+		}
+
+		names[name] = true
+
+		events := make([]models.EventType, 0, len(n.Events))
+		for _, e := range n.Events {
+			eventType, ok := models.EventTypes[e]
+			if !ok {
+				return fmt.Errorf("invalid event type for notifier %s: %s", name, e)
+			}
+
+			events = append(events, eventType)
+		}
+
+		notifier, err := factory(name, NotifierSettings(n.Settings))
+		if err != nil {
+			return fmt.Errorf("unable to create notifier %s: %v", name, err)
+		}
+
+		notifiers = append(notifiers, &registeredNotifier{Notifier: notifier, events: events})
+		log.Printf("Notifier %s (%s) enabled\n", name, n.Type)
+	}
+
+	_notifiers = notifiers
+
+	return nil
+}
+
+// notificationQueue holds the events waiting for the notifiers, big enough that events are only dropped when the
+// notifiers are stuck
+var notificationQueue = make(chan *models.Event, 1000)
+
+// startNotificationWorker hands the queued events to the notifiers in the background, one event at a time
+// so the replies to an incident or scheduled maintenance find the id of what was posted first
+func startNotificationWorker() {
+	go func() {
+		for event := range notificationQueue {
+			dispatchEvent(event)
+		}
+	}()
+}
+
+// notify queues the event for the notifiers. They get a copy of it, so the incident or scheduled maintenance
+// can keep changing while they run.
+func notify(event *models.Event) {
+	if event.ID == "" {
+		id, err := randomHex(16)
+		if err != nil {
+			log.Println("Error while generating the event id:", err)
+			return
+		}
+
+		event.ID = id
+	}
+
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	buf, err := json.Marshal(event)
+	if err != nil {
+		log.Println("Error while copying the event:", err)
+		return
+	}
+
+	queued := &models.Event{}
+	if err := json.Unmarshal(buf, queued); err != nil {
+		log.Println("Error while copying the event:", err)
+		return
+	}
+
+	// Called while the statuses are being derived, so a backlog of slow notifiers must not hold up every change
+	select {
+	case notificationQueue <- queued:
+	default:
+		log.Printf("Too many notifications are waiting, dropped the %s event %s\n", queued.Type, queued.ID)
+	}
+}
+
+// dispatchEvent hands the event to every notifier which wants it and records what they reported back
+// on the incident or scheduled maintenance of the event
+func dispatchEvent(event *models.Event) {
+	// The earlier events may have been notified after the copy was made
+	if err := refreshEventNotifications(event); err != nil {
+		log.Println("Error while getting the notification results:", err)
+	}
+
+	type outcome struct {
+		notified bool
+		id       string
+		err      error
+	}
+
+	outcomes := make([]outcome, len(_notifiers))
+
+	var wg sync.WaitGroup
+	for i, n := range _notifiers {
+		if !n.wantsEvent(event.Type) {
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, n *registeredNotifier) {
+			defer wg.Done()
+
+			id, err := n.Notify(event)
+			if err != nil {
+				log.Printf("Error while sending the %s notification via %s: %v\n", event.Type, n.Name(), err)
+				notificationsSent.Inc(n.Name(), string(event.Type), "failure")
+			} else {
+				notificationsSent.Inc(n.Name(), string(event.Type), "success")
+			}
+
+			outcomes[i] = outcome{notified: id != "" || err != nil, id: id, err: err}
+		}(i, n)
+	}
+
+	wg.Wait()
+
+	notified := false
+	for _, o := range outcomes {
+		notified = notified || o.notified
+	}
+
+	if !notified {
+		return
+	}
+
+	record := func(results models.NotificationResults) {
+		for i, o := range outcomes {
+			if o.notified {
+				results.Record(_notifiers[i].Name(), o.id, o.err)
+			}
+		}
+	}
+
+	var err error
+	switch {
+	case event.Incident != nil:
+		err = _dataStore.UpdateIncidentNotifications(event.Incident.ID, record)
+	case event.ScheduledMaintenance != nil:
+		err = _dataStore.UpdateScheduledMaintenanceNotifications(event.ScheduledMaintenance.ID, record)
+	}
+
+	if err != nil {
+		log.Println("Error while storing the notification results:", err)
+	}
+}
+
+// refreshEventNotifications gets the stored notification results of the incident or scheduled maintenance of the event
+func refreshEventNotifications(event *models.Event) error {
+	switch {
+	case event.Incident != nil:
+		incident, err := _dataStore.GetIncidentByID(event.Incident.ID)
+		if err != nil || incident == nil {
+			return err
+		}
+
+		event.Incident.Notifications = incident.Notifications
+	case event.ScheduledMaintenance != nil:
+		scheduledMaintenance, err := _dataStore.GetScheduledMaintenanceByID(event.ScheduledMaintenance.ID)
+		if err != nil || scheduledMaintenance == nil {
+			return err
+		}
+
+		event.ScheduledMaintenance.Notifications = scheduledMaintenance.Notifications
+	}
+
+	return nil
+}
+
+// originalNotificationID gets the id of the first thing the notifier created for the incident or scheduled maintenance of the event
+func originalNotificationID(event *models.Event, name string) string {
+	switch {
+	case event.Incident != nil:
+		return event.Incident.Notifications.OriginalID(name)
+	case event.ScheduledMaintenance != nil:
+		return event.ScheduledMaintenance.Notifications.OriginalID(name)
+	}
+
+	return ""
+}
+
+// requireSettings makes sure all of the given settings have a value
+func requireSettings(settings NotifierSettings, keys ...string) error {
+	for _, key := range keys {
+		if settings.String(key) == "" && len(settings.Strings(key)) == 0 {
+			return fmt.Errorf("missing required setting: %s", key)
+		}
+	}
+
+	return nil
+}
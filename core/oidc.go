@@ -0,0 +1,479 @@
+package core
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	_ "crypto/sha512" // Hashes of the 384 and 512 signature algorithms
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+const (
+	// oidcClockSkew is how far the clocks of the issuer and statuscentral may drift apart
+	oidcClockSkew = time.Minute
+
+	// oidcKeysMaxAge is how long the signing keys are used before they are fetched again
+	oidcKeysMaxAge = time.Hour
+
+	// oidcKeysMinRefresh limits how often tokens signed by an unknown key make the keys get fetched again
+	oidcKeysMinRefresh = time.Minute
+)
+
+// ErrInvalidOIDCToken is returned when an access token can not be verified or has expired
+var ErrInvalidOIDCToken = errors.New("invalid oidc token")
+
+// OIDCIdentity is the user an access token was issued to, with the scopes of the roles they have
+type OIDCIdentity struct {
+	Subject string
+	Scopes  []models.Scope
+}
+
+var jwtHashes = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+	"PS256": crypto.SHA256,
+	"PS384": crypto.SHA384,
+	"PS512": crypto.SHA512,
+	"ES256": crypto.SHA256,
+	"ES384": crypto.SHA384,
+	"ES512": crypto.SHA512,
+}
+
+// jwtCurves are the curves the keys of the ecdsa signature algorithms have to be on
+var jwtCurves = map[string]string{
+	"ES256": "P-256",
+	"ES384": "P-384",
+	"ES512": "P-521",
+}
+
+var oidcHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// oidcKeySet holds the signing keys of the issuer, which are discovered the first time a token is verified
+type oidcKeySet struct {
+	lock        sync.Mutex
+	jwksURI     string
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+var oidcKeys = &oidcKeySet{}
+
+// setupOIDC checks the roles only give valid scopes, the issuer itself is only contacted once a token has to be verified
+func setupOIDC() error {
+	if !config.Config.OIDC.Enabled {
+		return nil
+	}
+
+	for _, role := range config.Config.OIDC.Roles {
+		for _, scope := range role.Scopes {
+			if _, ok := models.Scopes[scope]; !ok {
+				return fmt.Errorf("oidc role %s has an invalid scope: %s", role.Name, scope)
+			}
+		}
+	}
+
+	log.Println("OIDC authentication enabled for issuer", config.Config.OIDC.Issuer)
+
+	return nil
+}
+
+// IsOIDCToken tells whether the token should be verified as an oidc access token, which are all jwts
+func IsOIDCToken(token string) bool {
+	return config.Config.OIDC.Enabled && strings.Count(token, ".") == 2
+}
+
+// AuthenticateOIDCToken verifies the signature, issuer, audience and expiry of the access token and maps the
+// claims of the user to scopes. Tokens which don't pass give an error wrapping ErrInvalidOIDCToken.
+func AuthenticateOIDCToken(token string) (*OIDCIdentity, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidOIDCToken)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+
+	if err := decodeJWTSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidOIDCToken)
+	}
+
+	var claims map[string]interface{}
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims", ErrInvalidOIDCToken)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidOIDCToken)
+	}
+
+	key, err := oidcKeys.get(header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	if key == nil {
+		return nil, fmt.Errorf("%w: unknown signing key %q", ErrInvalidOIDCToken, header.Kid)
+	}
+
+	if err := verifyJWTSignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOIDCToken, err)
+	}
+
+	if err := validateOIDCClaims(claims, time.Now()); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOIDCToken, err)
+	}
+
+	subject, _ := claims["sub"].(string)
+
+	return &OIDCIdentity{Subject: subject, Scopes: oidcScopes(claims)}, nil
+}
+
+func decodeJWTSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func verifyJWTSignature(alg string, key crypto.PublicKey, signed string, signature []byte) error {
+	hash, ok := jwtHashes[alg]
+	if !ok {
+		return fmt.Errorf("unsupported algorithm %q", alg)
+	}
+
+	h := hash.New()
+	h.Write([]byte(signed)) //nolint:errcheck // Writing to a hash never fails
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "RS", "PS":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("key can't be used with %s", alg)
+		}
+
+		if alg[:2] == "PS" {
+			return rsa.VerifyPSS(rsaKey, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+
+		return rsa.VerifyPKCS1v15(rsaKey, hash, digest, signature)
+	default:
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || ecKey.Curve.Params().Name != jwtCurves[alg] {
+			return fmt.Errorf("key can't be used with %s", alg)
+		}
+
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return errors.New("invalid signature")
+		}
+
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return errors.New("invalid signature")
+		}
+
+		return nil
+	}
+}
+
+func validateOIDCClaims(claims map[string]interface{}, now time.Time) error {
+	if issuer, _ := claims["iss"].(string); issuer != config.Config.OIDC.Issuer {
+		return fmt.Errorf("issued by %q", issuer)
+	}
+
+	if !stringInSlice(config.Config.OIDC.Audience, claimStrings(claims, "aud")) {
+		return errors.New("not issued for this audience")
+	}
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return errors.New("no expiry")
+	}
+
+	if now.After(time.Unix(int64(exp), 0).Add(oidcClockSkew)) {
+		return errors.New("expired")
+	}
+
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(oidcClockSkew).Before(time.Unix(int64(nbf), 0)) {
+		return errors.New("not valid yet")
+	}
+
+	if subject, _ := claims["sub"].(string); subject == "" {
+		return errors.New("no subject")
+	}
+
+	return nil
+}
+
+// oidcScopes gives the scopes of every role the claims match
+func oidcScopes(claims map[string]interface{}) []models.Scope {
+	groups := claimStrings(claims, config.Config.OIDC.GroupsClaim)
+
+	scopes := make([]models.Scope, 0)
+
+	for _, role := range config.Config.OIDC.Roles {
+		if role.Group != "" && !stringInSlice(role.Group, groups) {
+			continue
+		}
+
+		if role.Claim != "" && !stringInSlice(role.Value, claimStrings(claims, role.Claim)) {
+			continue
+		}
+
+		for _, scope := range role.Scopes {
+			if !models.HasScope(scopes, models.Scopes[scope]) {
+				scopes = append(scopes, models.Scopes[scope])
+			}
+		}
+	}
+
+	return scopes
+}
+
+// claimStrings gets the string, or the strings of the list, of the claim. Nested claims are separated by dots.
+func claimStrings(claims map[string]interface{}, name string) []string {
+	var value interface{} = claims
+
+	for _, key := range strings.Split(name, ".") {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		value = m[key]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+
+		return values
+	}
+
+	return nil
+}
+
+// get gives the key with the id, nil when the issuer doesn't have it. The keys are fetched again when
+// they're old or when the id is unknown, as the issuer might have rotated them.
+func (s *oidcKeySet) get(kid string) (crypto.PublicKey, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	key, ok := s.find(kid)
+
+	now := time.Now()
+	stale := now.Sub(s.fetchedAt) > oidcKeysMaxAge
+
+	if stale || (!ok && now.Sub(s.attemptedAt) > oidcKeysMinRefresh) {
+		s.attemptedAt = now
+
+		if err := s.fetch(); err != nil {
+			// The keys still work while the issuer can't be reached
+			if ok {
+				log.Println("Error while refreshing the oidc signing keys:", err)
+				return key, nil
+			}
+
+			return nil, err
+		}
+
+		key, ok = s.find(kid)
+	}
+
+	if !ok {
+		return nil, nil
+	}
+
+	return key, nil
+}
+
+func (s *oidcKeySet) find(kid string) (crypto.PublicKey, bool) {
+	// Issuers with a single key don't always give it an id
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+
+	key, ok := s.keys[kid]
+
+	return key, ok
+}
+
+func (s *oidcKeySet) fetch() error {
+	if s.jwksURI == "" {
+		if err := s.discover(); err != nil {
+			return err
+		}
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+
+	if err := getOIDCDocument(s.jwksURI, &jwks); err != nil {
+		return err
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			log.Printf("Skipping oidc signing key %q: %v\n", k.Kid, err)
+			continue
+		}
+
+		keys[k.Kid] = key
+	}
+
+	s.keys = keys
+	s.fetchedAt = time.Now()
+
+	return nil
+}
+
+func (s *oidcKeySet) discover() error {
+	issuer := config.Config.OIDC.Issuer
+
+	var discovery struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+
+	if err := getOIDCDocument(strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+		return err
+	}
+
+	if discovery.Issuer != issuer {
+		return fmt.Errorf("oidc discovery gives issuer %q instead of %q", discovery.Issuer, issuer)
+	}
+
+	if discovery.JWKSURI == "" {
+		return errors.New("oidc discovery gives no jwks_uri")
+	}
+
+	s.jwksURI = discovery.JWKSURI
+
+	return nil
+}
+
+func getOIDCDocument(url string, v interface{}) error {
+	resp, err := oidcHTTPClient.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s returned status code %d", url, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// jsonWebKey is a public key of the issuer as described by RFC 7517
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeJWKInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeJWKInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid exponent")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeJWKInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeJWKInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func decodeJWKInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,143 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// ErrInvalidPostmortem is returned when a postmortem isn't valid or the incident can't have one
+var ErrInvalidPostmortem = errors.New("invalid postmortem")
+
+// SavePostmortem creates or replaces the postmortem of the resolved incident, it stays published or a draft as it
+// was. The incident is nil when it doesn't exist.
+func SavePostmortem(incidentID int, postmortem *models.Postmortem) (*models.Incident, error) {
+	incident, err := _dataStore.GetIncidentByID(incidentID)
+	if err != nil || incident == nil {
+		return nil, err
+	}
+
+	if incident.Status != models.IncidentStatusResolved {
+		return nil, fmt.Errorf("%w: only resolved incidents get a postmortem", ErrInvalidPostmortem)
+	}
+
+	if postmortem.Summary == "" {
+		return nil, fmt.Errorf("%w: summary must be provided", ErrInvalidPostmortem)
+	}
+
+	// The impact defaults to the whole incident, from its start until it was resolved
+	if postmortem.ImpactStart.IsZero() {
+		postmortem.ImpactStart = incident.Time
+	}
+
+	if postmortem.ImpactEnd.IsZero() {
+		postmortem.ImpactEnd, _ = incidentResolvedAt(incident)
+	}
+
+	if postmortem.ImpactEnd.Before(postmortem.ImpactStart) {
+		return nil, fmt.Errorf("%w: the impact can't end before it starts", ErrInvalidPostmortem)
+	}
+
+	for _, item := range postmortem.ActionItems {
+		if item.Description == "" {
+			return nil, fmt.Errorf("%w: action items must have a description", ErrInvalidPostmortem)
+		}
+	}
+
+	postmortem.Published = false
+	postmortem.PublishedAt = time.Time{}
+	postmortem.CreatedAt = time.Now()
+
+	if existing := incident.Postmortem; existing != nil {
+		postmortem.Published = existing.Published
+		postmortem.PublishedAt = existing.PublishedAt
+		postmortem.CreatedAt = existing.CreatedAt
+	}
+
+	postmortem.UpdatedAt = time.Now()
+	incident.Postmortem = postmortem
+
+	if err := _dataStore.UpdateIncident(incident); err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+// PublishPostmortem shows the postmortem on the page of the incident, which has to still be resolved. The first time
+// it's published it's announced through the notifiers. The incident is nil when it doesn't exist.
+func PublishPostmortem(incidentID int) (*models.Incident, error) {
+	incident, err := _dataStore.GetIncidentByID(incidentID)
+	if err != nil || incident == nil {
+		return nil, err
+	}
+
+	if incident.Postmortem == nil {
+		return nil, fmt.Errorf("%w: the incident has no postmortem", ErrInvalidPostmortem)
+	}
+
+	// The incident may have been reopened since the postmortem was saved
+	if incident.Status != models.IncidentStatusResolved {
+		return nil, fmt.Errorf("%w: only postmortems of resolved incidents can be published", ErrInvalidPostmortem)
+	}
+
+	if incident.Postmortem.Published {
+		return incident, nil
+	}
+
+	announce := incident.Postmortem.PublishedAt.IsZero()
+
+	incident.Postmortem.Published = true
+	incident.Postmortem.PublishedAt = time.Now()
+
+	if err := _dataStore.UpdateIncident(incident); err != nil {
+		return nil, err
+	}
+
+	if announce {
+		notify(&models.Event{
+			Type:     models.EventIncidentPostmortemPublished,
+			Incident: incident,
+		})
+	}
+
+	return incident, nil
+}
+
+// UnpublishPostmortem makes the postmortem a draft again. The incident is nil when it doesn't exist.
+func UnpublishPostmortem(incidentID int) (*models.Incident, error) {
+	incident, err := _dataStore.GetIncidentByID(incidentID)
+	if err != nil || incident == nil {
+		return nil, err
+	}
+
+	if incident.Postmortem == nil {
+		return nil, fmt.Errorf("%w: the incident has no postmortem", ErrInvalidPostmortem)
+	}
+
+	incident.Postmortem.Published = false
+
+	if err := _dataStore.UpdateIncident(incident); err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+// DeletePostmortem removes the postmortem of the incident. The incident is nil when it doesn't exist.
+func DeletePostmortem(incidentID int) (*models.Incident, error) {
+	incident, err := _dataStore.GetIncidentByID(incidentID)
+	if err != nil || incident == nil {
+		return nil, err
+	}
+
+	incident.Postmortem = nil
+
+	if err := _dataStore.UpdateIncident(incident); err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// queuedPostmortemAnnouncements takes the queued events off the notification queue, which no worker reads from in
+// the tests, and counts the postmortem announcements of the incident
+func queuedPostmortemAnnouncements(incidentID int) int {
+	count := 0
+
+	for {
+		select {
+		case event := <-notificationQueue:
+			if event.Type == models.EventIncidentPostmortemPublished && event.Incident != nil && event.Incident.ID == incidentID {
+				count++
+			}
+		default:
+			return count
+		}
+	}
+}
+
+func resolveTestIncident(t *testing.T, incident *models.Incident) {
+	if _, err := CreateIncidentUpdate(incident.ID, &models.StatusUpdate{Status: models.IncidentStatusResolved, Message: "Fixed"}, "test"); err != nil {
+		t.Fatalf("unable to resolve the incident: %v", err)
+	}
+}
+
+func TestSavePostmortem(t *testing.T) {
+	incident := newTestIncident(t, &models.Incident{Title: "Login failures", Status: models.IncidentStatusInvestigating})
+
+	if _, err := SavePostmortem(incident.ID, &models.Postmortem{Summary: "Too early"}); !errors.Is(err, ErrInvalidPostmortem) {
+		t.Errorf("expected the open incident to be rejected, got %v", err)
+	}
+
+	resolveTestIncident(t, incident)
+
+	// The customers were affected before the incident was posted
+	start := incident.Time.Add(-time.Minute)
+
+	tests := []struct {
+		name       string
+		postmortem models.Postmortem
+		valid      bool
+	}{
+		{name: "no summary", postmortem: models.Postmortem{Body: "What happened"}},
+		{name: "impact ends before it starts", postmortem: models.Postmortem{Summary: "Logins failed", ImpactStart: start, ImpactEnd: start.Add(-time.Second)}},
+		{name: "action item without a description", postmortem: models.Postmortem{Summary: "Logins failed", ActionItems: []models.PostmortemActionItem{{Owner: "ops"}}}},
+		{name: "valid", postmortem: models.Postmortem{Summary: "Logins failed", ImpactStart: start}, valid: true},
+	}
+
+	for _, test := range tests {
+		if _, err := SavePostmortem(incident.ID, &test.postmortem); (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %v, got %v", test.name, test.valid, err)
+		}
+	}
+
+	saved, err := GetIncidentByID(incident.ID)
+	if err != nil || saved.Postmortem == nil {
+		t.Fatalf("expected the postmortem to be saved, got %v", err)
+	}
+
+	// The impact ends by default when the incident was resolved
+	if !saved.Postmortem.ImpactStart.Equal(start) || saved.Postmortem.ImpactEnd.Before(start) {
+		t.Errorf("expected the impact to go from %v until the incident was resolved, got %v to %v", start, saved.Postmortem.ImpactStart, saved.Postmortem.ImpactEnd)
+	}
+
+	if saved.Postmortem.Published {
+		t.Error("expected a new postmortem to be a draft")
+	}
+
+	if _, err := SavePostmortem(-1, &models.Postmortem{Summary: "Nothing"}); err != nil {
+		t.Errorf("expected no error for a missing incident, got %v", err)
+	}
+}
+
+func TestPublishPostmortem(t *testing.T) {
+	incident := newTestIncident(t, &models.Incident{Title: "Push delayed", Status: models.IncidentStatusInvestigating})
+	resolveTestIncident(t, incident)
+
+	if _, err := PublishPostmortem(incident.ID); !errors.Is(err, ErrInvalidPostmortem) {
+		t.Errorf("expected an incident without a postmortem to be rejected, got %v", err)
+	}
+
+	if _, err := SavePostmortem(incident.ID, &models.Postmortem{Summary: "Push was delayed"}); err != nil {
+		t.Fatalf("unable to save the postmortem: %v", err)
+	}
+
+	queuedPostmortemAnnouncements(incident.ID)
+
+	published, err := PublishPostmortem(incident.ID)
+	if err != nil || !published.Postmortem.Published || published.Postmortem.PublishedAt.IsZero() {
+		t.Fatalf("expected the postmortem to be published, got %+v (%v)", published, err)
+	}
+
+	// Publishing again, saving it again or publishing it after it was a draft for a while is announced only once
+	if _, err := PublishPostmortem(incident.ID); err != nil {
+		t.Fatalf("unable to publish the postmortem again: %v", err)
+	}
+
+	saved, err := SavePostmortem(incident.ID, &models.Postmortem{Summary: "Push was delayed by the queue"})
+	if err != nil || !saved.Postmortem.Published {
+		t.Fatalf("expected the saved postmortem to stay published, got %+v (%v)", saved, err)
+	}
+
+	if _, err := UnpublishPostmortem(incident.ID); err != nil {
+		t.Fatalf("unable to unpublish the postmortem: %v", err)
+	}
+
+	if _, err := PublishPostmortem(incident.ID); err != nil {
+		t.Fatalf("unable to publish the postmortem once more: %v", err)
+	}
+
+	if announcements := queuedPostmortemAnnouncements(incident.ID); announcements != 1 {
+		t.Errorf("expected the postmortem to be announced once, got %d", announcements)
+	}
+}
+
+func TestPublishPostmortemOfAReopenedIncident(t *testing.T) {
+	incident := newTestIncident(t, &models.Incident{Title: "Uploads failing", Status: models.IncidentStatusInvestigating})
+	resolveTestIncident(t, incident)
+
+	if _, err := SavePostmortem(incident.ID, &models.Postmortem{Summary: "Uploads failed"}); err != nil {
+		t.Fatalf("unable to save the postmortem: %v", err)
+	}
+
+	if _, err := CreateIncidentUpdate(incident.ID, &models.StatusUpdate{Status: models.IncidentStatusInvestigating, Message: "Failing again"}, "test"); err != nil {
+		t.Fatalf("unable to reopen the incident: %v", err)
+	}
+
+	queuedPostmortemAnnouncements(incident.ID)
+
+	if _, err := PublishPostmortem(incident.ID); !errors.Is(err, ErrInvalidPostmortem) {
+		t.Errorf("expected the postmortem of the reopened incident to be rejected, got %v", err)
+	}
+
+	stored, err := GetIncidentByID(incident.ID)
+	if err != nil || stored.Postmortem == nil || stored.Postmortem.Published {
+		t.Errorf("expected the postmortem to stay a draft, got %+v (%v)", stored.Postmortem, err)
+	}
+
+	if announcements := queuedPostmortemAnnouncements(incident.ID); announcements != 0 {
+		t.Errorf("expected no announcement, got %d", announcements)
+	}
+}
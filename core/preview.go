@@ -0,0 +1,109 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// PreviewIncident gives the text which would be posted about the incident, nothing is stored. The
+// incident gets the same defaults it would get when created.
+func PreviewIncident(incident *models.Incident) (string, error) {
+	ensureIncidentDefaults(incident)
+
+	if err := validateServiceUpdates(incident.Services, true); err != nil {
+		return "", err
+	}
+
+	return RenderTweet(&models.Event{
+		Type:     models.EventIncidentCreated,
+		Incident: incident,
+	})
+}
+
+// PreviewIncidentUpdate gives the incident as it would be with the update, and the text which would be
+// posted about the update. Nothing is stored.
+func PreviewIncidentUpdate(incidentID int, update *models.StatusUpdate) (*models.Incident, string, error) {
+	if err := previewStatusUpdate(update); err != nil {
+		return nil, "", err
+	}
+
+	incident, err := _dataStore.GetIncidentByID(incidentID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if incident == nil {
+		return nil, "", errors.New("invalid incident")
+	}
+
+	incident.Status = update.Status
+	incident.Updates = append(incident.Updates, update)
+
+	eventType := models.EventIncidentUpdated
+	if update.Status == models.IncidentStatusResolved {
+		eventType = models.EventIncidentResolved
+	}
+
+	tweet, err := RenderTweet(&models.Event{
+		Type:     eventType,
+		Incident: incident,
+		Update:   update,
+	})
+
+	return incident, tweet, err
+}
+
+// PreviewScheduledMaintenanceUpdate gives the scheduled maintenance as it would be with the update, and
+// the text which would be posted about the update. Nothing is stored.
+func PreviewScheduledMaintenanceUpdate(maintenanceID int, update *models.StatusUpdate) (*models.ScheduledMaintenance, string, error) {
+	if err := previewStatusUpdate(update); err != nil {
+		return nil, "", err
+	}
+
+	scheduledMaintenance, err := _dataStore.GetScheduledMaintenanceByID(maintenanceID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if scheduledMaintenance == nil {
+		return nil, "", errors.New("invalid scheduledMaintenance")
+	}
+
+	scheduledMaintenance.Updates = append(scheduledMaintenance.Updates, update)
+
+	eventType := models.EventMaintenanceUpdated
+	if update.Status == models.IncidentStatusResolved {
+		eventType = models.EventMaintenanceCompleted
+	}
+
+	tweet, err := RenderTweet(&models.Event{
+		Type:                 eventType,
+		ScheduledMaintenance: scheduledMaintenance,
+		Update:               update,
+	})
+
+	return scheduledMaintenance, tweet, err
+}
+
+// previewStatusUpdate checks the update like it's checked when created and gives it its status and time
+func previewStatusUpdate(update *models.StatusUpdate) error {
+	if update.Message == "" {
+		return errors.New("message property is missing")
+	}
+
+	status, ok := models.IncidentStatuses[strings.ToLower(update.Status.String())]
+	if !ok {
+		return errors.New("invalid status value")
+	}
+
+	update.Status = status
+
+	if update.Time.IsZero() {
+		update.Time = time.Now()
+	}
+
+	return validateServiceUpdates(update.Services, true)
+}
@@ -0,0 +1,268 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// probeMaxBodySize is how much of the response body a http probe looks at for the expected body
+const probeMaxBodySize = 1 << 20
+
+type probeCheck func(probe *config.ProbeConfig) error
+
+var probeChecks = map[string]probeCheck{
+	"http": checkHTTPProbe,
+	"tcp":  checkTCPProbe,
+	"dns":  checkDNSProbe,
+}
+
+// startProbes runs every configured probe on its own schedule
+func startProbes() error {
+	for i := range config.Config.Probes {
+		probe := &config.Config.Probes[i]
+
+		service, err := GetServiceByName(probe.Service)
+		if err != nil {
+			return err
+		}
+
+		if service == nil {
+			return fmt.Errorf("probe %s is for an unknown service: %s", probe.Name, probe.Service)
+		}
+
+		if probe.Region != "" {
+			region, err := GetRegionByCodeAndServiceName(probe.Region, probe.Service)
+			if err != nil {
+				return err
+			}
+
+			if region == nil {
+				return fmt.Errorf("probe %s is for an unknown region: %s", probe.Name, probe.Region)
+			}
+		}
+
+		go runProbe(probe)
+		log.Printf("Probe %s (%s) enabled\n", probe.Name, probe.Type)
+	}
+
+	return nil
+}
+
+func runProbe(probe *config.ProbeConfig) {
+	check := probeChecks[probe.Type]
+	run := &probeRun{}
+
+	ticker := time.NewTicker(probe.Interval)
+	defer ticker.Stop()
+
+	for ; true; <-ticker.C {
+		run.record(probe, check(probe))
+	}
+}
+
+// probeRun keeps count of the checks of a probe which failed or passed in a row
+type probeRun struct {
+	failures  int
+	successes int
+
+	// Whether the current run of failures or successes already got its incident opened or resolved. Acting once
+	// per run keeps an incident resolved by hand while the probe keeps failing from being opened again right away,
+	// while an attempt which failed is retried on the next check.
+	opened   bool
+	resolved bool
+}
+
+// record counts the result of a check, opening or resolving the incident of the probe once enough are in a row
+func (r *probeRun) record(probe *config.ProbeConfig, checkErr error) {
+	if checkErr != nil {
+		r.failures++
+		r.successes = 0
+		r.resolved = false
+
+		log.Printf("Probe %s failed (%d in a row): %v\n", probe.Name, r.failures, checkErr)
+
+		if r.failures >= probe.FailureThreshold && !r.opened {
+			if err := openProbeIncident(probe, checkErr); err != nil {
+				log.Printf("Error while opening the incident of probe %s: %v\n", probe.Name, err)
+			} else {
+				r.opened = true
+			}
+		}
+
+		return
+	}
+
+	r.failures = 0
+	r.successes++
+	r.opened = false
+
+	if r.successes >= probe.SuccessThreshold && probe.AutoResolve && !r.resolved {
+		if err := resolveProbeIncident(probe); err != nil {
+			log.Printf("Error while resolving the incident of probe %s: %v\n", probe.Name, err)
+		} else {
+			r.resolved = true
+		}
+	}
+}
+
+func probeSource(probe *config.ProbeConfig) string {
+	return "probe:" + probe.Name
+}
+
+// openProbeIncident opens an incident for the failing probe, unless it still has one open
+func openProbeIncident(probe *config.ProbeConfig, checkErr error) error {
+	source := probeSource(probe)
+
+	existing, err := getOpenIncidentBySource(source)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		return nil
+	}
+
+	status := models.ServiceStatusOutage
+	if probe.FailureStatus != "" {
+		status = models.ServiceStatuses[strings.ToLower(probe.FailureStatus)]
+	}
+
+	service := models.ServiceUpdate{Name: probe.Service, Status: status}
+	title := probe.Service + " is failing health checks"
+
+	if probe.Region != "" {
+		service.Regions = []string{probe.Region}
+		title = fmt.Sprintf("%s (%s) is failing health checks", probe.Service, probe.Region)
+	}
+
+	now := time.Now()
+	incident := &models.Incident{
+		Time:     now,
+		Title:    title,
+		Status:   models.IncidentStatusInvestigating,
+		Services: []models.ServiceUpdate{service},
+		Source:   source,
+		Updates: []*models.StatusUpdate{
+			{
+				Time:    now,
+				Status:  models.IncidentStatusInvestigating,
+				Message: fmt.Sprintf("Our automated health checks detected a problem and we are investigating. The last %d checks failed.", probe.FailureThreshold),
+			},
+		},
+	}
+
+	log.Printf("Probe %s failed %d times in a row, opening an incident: %v\n", probe.Name, probe.FailureThreshold, checkErr)
+
+	_, err = CreateIncident(incident, source)
+
+	return err
+}
+
+// resolveProbeIncident resolves the incident opened for the probe, if it still has one open
+func resolveProbeIncident(probe *config.ProbeConfig) error {
+	source := probeSource(probe)
+
+	incident, err := getOpenIncidentBySource(source)
+	if err != nil {
+		return err
+	}
+
+	if incident == nil {
+		return nil
+	}
+
+	log.Printf("Probe %s passed %d times in a row, resolving incident %d\n", probe.Name, probe.SuccessThreshold, incident.ID)
+
+	_, err = CreateIncidentUpdate(incident.ID, &models.StatusUpdate{
+		Status:  models.IncidentStatusResolved,
+		Message: fmt.Sprintf("Our automated health checks are passing again. The last %d checks succeeded.", probe.SuccessThreshold),
+	}, source)
+
+	return err
+}
+
+func checkHTTPProbe(probe *config.ProbeConfig) error {
+	client := &http.Client{Timeout: probe.Timeout}
+
+	start := time.Now()
+
+	resp, err := client.Get(probe.Target)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, probeMaxBodySize))
+	if err != nil {
+		return err
+	}
+
+	latency := time.Since(start)
+
+	if probe.ExpectedStatus != 0 {
+		if resp.StatusCode != probe.ExpectedStatus {
+			return fmt.Errorf("unexpected status code %d, expected %d", resp.StatusCode, probe.ExpectedStatus)
+		}
+	} else if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	if probe.ExpectedBody != "" && !strings.Contains(string(body), probe.ExpectedBody) {
+		return errors.New("response body doesn't contain the expected body")
+	}
+
+	return checkProbeLatency(probe, latency)
+}
+
+func checkTCPProbe(probe *config.ProbeConfig) error {
+	start := time.Now()
+
+	conn, err := net.DialTimeout("tcp", probe.Target, probe.Timeout)
+	if err != nil {
+		return err
+	}
+
+	latency := time.Since(start)
+	conn.Close()
+
+	return checkProbeLatency(probe, latency)
+}
+
+func checkDNSProbe(probe *config.ProbeConfig) error {
+	ctx, cancel := context.WithTimeout(context.Background(), probe.Timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	addresses, err := net.DefaultResolver.LookupHost(ctx, probe.Target)
+	if err != nil {
+		return err
+	}
+
+	latency := time.Since(start)
+
+	if probe.ExpectedAddress != "" && !stringInSlice(probe.ExpectedAddress, addresses) {
+		return fmt.Errorf("resolved to %s, expected %s", strings.Join(addresses, ", "), probe.ExpectedAddress)
+	}
+
+	return checkProbeLatency(probe, latency)
+}
+
+func checkProbeLatency(probe *config.ProbeConfig, latency time.Duration) error {
+	if probe.MaxLatency > 0 && latency > probe.MaxLatency {
+		return fmt.Errorf("took %s, more than the max latency of %s", latency, probe.MaxLatency)
+	}
+
+	return nil
+}
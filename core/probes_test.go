@@ -0,0 +1,228 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+var errTestProbeFailed = errors.New("connection refused")
+
+func newTestProbe(t *testing.T, name string) *config.ProbeConfig {
+	newTestService(t, name)
+
+	probe := &config.ProbeConfig{
+		Name:             name,
+		Service:          name,
+		Type:             "http",
+		FailureThreshold: 3,
+		SuccessThreshold: 2,
+		AutoResolve:      true,
+	}
+
+	t.Cleanup(func() {
+		if incident, _ := getOpenIncidentBySource(probeSource(probe)); incident != nil {
+			_dataStore.DeleteIncident(incident.ID) //nolint:errcheck
+		}
+
+		deriveStatuses(statusChangeCause{Actor: "test"}) //nolint:errcheck
+	})
+
+	return probe
+}
+
+func openTestProbeIncident(t *testing.T, probe *config.ProbeConfig) *models.Incident {
+	incident, err := getOpenIncidentBySource(probeSource(probe))
+	if err != nil {
+		t.Fatalf("unable to get the incident of the probe: %v", err)
+	}
+
+	return incident
+}
+
+func TestProbeRunOpensTheIncidentAfterTheFailureThreshold(t *testing.T) {
+	probe := newTestProbe(t, "probe-flapping")
+	run := &probeRun{}
+
+	// Flapping never reaches the threshold
+	for _, err := range []error{errTestProbeFailed, errTestProbeFailed, nil, errTestProbeFailed, errTestProbeFailed, nil} {
+		run.record(probe, err)
+	}
+
+	if incident := openTestProbeIncident(t, probe); incident != nil {
+		t.Fatalf("expected no incident while the probe flaps, got %d", incident.ID)
+	}
+
+	for i := 0; i < 3; i++ {
+		run.record(probe, errTestProbeFailed)
+	}
+
+	incident := openTestProbeIncident(t, probe)
+	if incident == nil {
+		t.Fatal("expected an incident after three failures in a row")
+	}
+
+	if status := serviceStatus(t, probe.Service, ""); status != models.ServiceStatusOutage {
+		t.Errorf("expected the service to be out, got %s", status)
+	}
+
+	// Failing on opens no other incident
+	for i := 0; i < 5; i++ {
+		run.record(probe, errTestProbeFailed)
+	}
+
+	if again := openTestProbeIncident(t, probe); again == nil || again.ID != incident.ID {
+		t.Errorf("expected incident %d to stay the only one, got %+v", incident.ID, again)
+	}
+
+	// A single success is not enough to resolve it
+	run.record(probe, nil)
+
+	if openTestProbeIncident(t, probe) == nil {
+		t.Fatal("expected the incident to stay open below the success threshold")
+	}
+
+	run.record(probe, nil)
+
+	if open := openTestProbeIncident(t, probe); open != nil {
+		t.Errorf("expected the incident to be resolved after two successes in a row, got %d open", open.ID)
+	}
+
+	if status := serviceStatus(t, probe.Service, ""); status != models.ServiceStatusNominal {
+		t.Errorf("expected the service to be nominal again, got %s", status)
+	}
+}
+
+func TestProbeRunOnlyResolvesItsOwnIncident(t *testing.T) {
+	probe := newTestProbe(t, "probe-own")
+
+	other := newTestIncident(t, &models.Incident{
+		Title:    "Degraded by hand",
+		Status:   models.IncidentStatusInvestigating,
+		Services: []models.ServiceUpdate{{Name: probe.Service, Status: models.ServiceStatusDegraded}},
+	})
+
+	run := &probeRun{}
+	for i := 0; i < 3; i++ {
+		run.record(probe, errTestProbeFailed)
+	}
+
+	own := openTestProbeIncident(t, probe)
+	if own == nil || own.ID == other.ID {
+		t.Fatalf("expected the probe to open an incident of its own, got %+v", own)
+	}
+
+	for i := 0; i < 2; i++ {
+		run.record(probe, nil)
+	}
+
+	resolved, err := GetIncidentByID(own.ID)
+	if err != nil || resolved.Status != models.IncidentStatusResolved {
+		t.Errorf("expected the incident of the probe to be resolved, got %v (%v)", resolved.Status, err)
+	}
+
+	stillOpen, err := GetIncidentByID(other.ID)
+	if err != nil || stillOpen.Status == models.IncidentStatusResolved {
+		t.Errorf("expected the other incident to stay open, got %v (%v)", stillOpen.Status, err)
+	}
+
+	if status := serviceStatus(t, probe.Service, ""); status != models.ServiceStatusDegraded {
+		t.Errorf("expected the other incident to keep the service degraded, got %s", status)
+	}
+}
+
+func TestProbeRunPicksUpItsIncidentAfterARestart(t *testing.T) {
+	probe := newTestProbe(t, "probe-restart")
+
+	before := &probeRun{}
+	for i := 0; i < 3; i++ {
+		before.record(probe, errTestProbeFailed)
+	}
+
+	incident := openTestProbeIncident(t, probe)
+	if incident == nil {
+		t.Fatal("expected an incident before the restart")
+	}
+
+	// Restarted with the incident still open, the counts start over
+	after := &probeRun{}
+	for i := 0; i < 3; i++ {
+		after.record(probe, errTestProbeFailed)
+	}
+
+	if again := openTestProbeIncident(t, probe); again == nil || again.ID != incident.ID {
+		t.Fatalf("expected the open incident %d to be kept, got %+v", incident.ID, again)
+	}
+
+	for i := 0; i < 2; i++ {
+		after.record(probe, nil)
+	}
+
+	if open := openTestProbeIncident(t, probe); open != nil {
+		t.Errorf("expected the incident opened before the restart to be resolved, got %d open", open.ID)
+	}
+}
+
+func TestCheckHTTPProbe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthy":
+			fmt.Fprint(w, `{"status":"ok"}`)
+		case "/slow":
+			time.Sleep(50 * time.Millisecond)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name  string
+		probe config.ProbeConfig
+		valid bool
+	}{
+		{name: "healthy", probe: config.ProbeConfig{Target: server.URL + "/healthy"}, valid: true},
+		{name: "expected body", probe: config.ProbeConfig{Target: server.URL + "/healthy", ExpectedBody: `"ok"`}, valid: true},
+		{name: "other body", probe: config.ProbeConfig{Target: server.URL + "/healthy", ExpectedBody: "maintenance"}},
+		{name: "error status", probe: config.ProbeConfig{Target: server.URL + "/down"}},
+		{name: "expected error status", probe: config.ProbeConfig{Target: server.URL + "/down", ExpectedStatus: http.StatusServiceUnavailable}, valid: true},
+		{name: "slower than the max latency", probe: config.ProbeConfig{Target: server.URL + "/slow", MaxLatency: 10 * time.Millisecond}},
+		{name: "timed out", probe: config.ProbeConfig{Target: server.URL + "/slow", Timeout: 10 * time.Millisecond}},
+	}
+
+	for _, test := range tests {
+		if test.probe.Timeout == 0 {
+			test.probe.Timeout = time.Second
+		}
+
+		if err := checkHTTPProbe(&test.probe); (err == nil) != test.valid {
+			t.Errorf("%s: expected valid %v, got %v", test.name, test.valid, err)
+		}
+	}
+}
+
+func TestCheckTCPProbe(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+
+	address := listener.Addr().String()
+
+	if err := checkTCPProbe(&config.ProbeConfig{Target: address, Timeout: time.Second}); err != nil {
+		t.Errorf("expected the listening port to pass, got %v", err)
+	}
+
+	listener.Close()
+
+	if err := checkTCPProbe(&config.ProbeConfig{Target: address, Timeout: time.Second}); err == nil {
+		t.Error("expected the closed port to fail")
+	}
+}
@@ -0,0 +1,59 @@
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter allows a number of hits per key within a sliding window, like the subscription requests of an address
+type rateLimiter struct {
+	mu        sync.Mutex
+	limit     int
+	window    time.Duration
+	hits      map[string][]time.Time
+	lastSweep time.Time
+}
+
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:  limit,
+		window: window,
+		hits:   make(map[string][]time.Time),
+	}
+}
+
+// allow records a hit for the key, it's false when the key already had its limit of hits within the window
+func (l *rateLimiter) allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	since := now.Add(-l.window)
+
+	// Keys which weren't hit for a whole window are forgotten, so the map doesn't keep growing
+	if now.Sub(l.lastSweep) > l.window {
+		for k, hits := range l.hits {
+			if !hits[len(hits)-1].After(since) {
+				delete(l.hits, k)
+			}
+		}
+
+		l.lastSweep = now
+	}
+
+	recent := make([]time.Time, 0, l.limit)
+	for _, hit := range l.hits[key] {
+		if hit.After(since) {
+			recent = append(recent, hit)
+		}
+	}
+
+	if len(recent) >= l.limit {
+		l.hits[key] = recent
+		return false
+	}
+
+	l.hits[key] = append(recent, now)
+
+	return true
+}
@@ -0,0 +1,190 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// recurrenceMaxPeriods keeps a rule which never matches, like the 31st of every other February, from looping forever
+const recurrenceMaxPeriods = 100000
+
+var recurrenceWeekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// recurrenceRule is the part of an RRULE (RFC 5545) recurring maintenance supports
+type recurrenceRule struct {
+	Frequency string
+	Interval  int
+	Weekdays  []time.Weekday
+	Count     int
+	Until     time.Time
+}
+
+// parseRecurrenceRule parses rules like "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU,TH", the "RRULE:" prefix is optional
+func parseRecurrenceRule(rule string) (*recurrenceRule, error) {
+	r := &recurrenceRule{Interval: 1}
+
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:"), ";") {
+		if part == "" {
+			continue
+		}
+
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid rule part: %s", part)
+		}
+
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		switch key {
+		case "FREQ":
+			switch value {
+			case "DAILY", "WEEKLY", "MONTHLY":
+				r.Frequency = value
+			default:
+				return nil, errors.New("FREQ must be DAILY, WEEKLY or MONTHLY")
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval <= 0 {
+				return nil, errors.New("INTERVAL must be a positive number")
+			}
+
+			r.Interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil || count <= 0 {
+				return nil, errors.New("COUNT must be a positive number")
+			}
+
+			r.Count = count
+		case "UNTIL":
+			until, err := parseRecurrenceUntil(value)
+			if err != nil {
+				return nil, err
+			}
+
+			r.Until = until
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := recurrenceWeekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY day: %s", day)
+				}
+
+				r.Weekdays = append(r.Weekdays, weekday)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported rule part: %s", key)
+		}
+	}
+
+	if r.Frequency == "" {
+		return nil, errors.New("FREQ is missing")
+	}
+
+	if len(r.Weekdays) > 0 && r.Frequency != "WEEKLY" {
+		return nil, errors.New("BYDAY is only supported with FREQ=WEEKLY")
+	}
+
+	if r.Count > 0 && !r.Until.IsZero() {
+		return nil, errors.New("COUNT and UNTIL can't be used together")
+	}
+
+	// Weeks start on monday, as they do by default in RFC 5545
+	sort.Slice(r.Weekdays, func(i, j int) bool {
+		return weekdayOffset(r.Weekdays[i]) < weekdayOffset(r.Weekdays[j])
+	})
+
+	return r, nil
+}
+
+func parseRecurrenceUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if until, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return until, nil
+		}
+	}
+
+	return time.Time{}, errors.New("UNTIL must look like 20260131T000000Z or 20260131")
+}
+
+// occurrences gives the occurrences of the rule which start from `from` and before `to`, the first one being start
+func (r *recurrenceRule) occurrences(start, from, to time.Time) []time.Time {
+	occurrences := make([]time.Time, 0)
+	count := 0
+
+	for period := 0; period < recurrenceMaxPeriods; period++ {
+		for _, occurrence := range r.periodOccurrences(start, period) {
+			if occurrence.Before(start) {
+				continue
+			}
+
+			if !occurrence.Before(to) || (!r.Until.IsZero() && occurrence.After(r.Until)) {
+				return occurrences
+			}
+
+			count++
+			if r.Count > 0 && count > r.Count {
+				return occurrences
+			}
+
+			if !occurrence.Before(from) {
+				occurrences = append(occurrences, occurrence)
+			}
+		}
+	}
+
+	return occurrences
+}
+
+// isOccurrence tells whether the rule has an occurrence starting at exactly that time
+func (r *recurrenceRule) isOccurrence(start, t time.Time) bool {
+	return len(r.occurrences(start, t, t.Add(time.Nanosecond))) == 1
+}
+
+// periodOccurrences gives the occurrences of the nth day, week or month of the rule, in order
+func (r *recurrenceRule) periodOccurrences(start time.Time, period int) []time.Time {
+	switch r.Frequency {
+	case "DAILY":
+		return []time.Time{start.AddDate(0, 0, period*r.Interval)}
+	case "WEEKLY":
+		weekdays := r.Weekdays
+		if len(weekdays) == 0 {
+			weekdays = []time.Weekday{start.Weekday()}
+		}
+
+		week := start.AddDate(0, 0, 7*period*r.Interval-weekdayOffset(start.Weekday()))
+
+		occurrences := make([]time.Time, 0, len(weekdays))
+		for _, weekday := range weekdays {
+			occurrences = append(occurrences, week.AddDate(0, 0, weekdayOffset(weekday)))
+		}
+
+		return occurrences
+	default:
+		// Months without the day of the start are skipped, like RFC 5545 does
+		occurrence := start.AddDate(0, period*r.Interval, 0)
+		if occurrence.Day() != start.Day() {
+			return nil
+		}
+
+		return []time.Time{occurrence}
+	}
+}
+
+// weekdayOffset is the number of days since monday
+func weekdayOffset(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
@@ -0,0 +1,208 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecurrenceRuleOccurrences(t *testing.T) {
+	// A tuesday
+	start := time.Date(2026, 3, 3, 2, 0, 0, 0, time.UTC)
+	day := func(month time.Month, day int) time.Time {
+		return time.Date(2026, month, day, 2, 0, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		rule     string
+		start    time.Time
+		from     time.Time
+		to       time.Time
+		expected []time.Time
+	}{
+		{
+			name:     "daily",
+			rule:     "FREQ=DAILY",
+			start:    start,
+			from:     start,
+			to:       day(3, 6),
+			expected: []time.Time{day(3, 3), day(3, 4), day(3, 5)},
+		},
+		{
+			name:     "every other day from later on",
+			rule:     "RRULE:FREQ=DAILY;INTERVAL=2",
+			start:    start,
+			from:     day(3, 6),
+			to:       day(3, 12),
+			expected: []time.Time{day(3, 7), day(3, 9), day(3, 11)},
+		},
+		{
+			name:     "weekly on the day of the start",
+			rule:     "FREQ=WEEKLY",
+			start:    start,
+			from:     start,
+			to:       day(3, 25),
+			expected: []time.Time{day(3, 3), day(3, 10), day(3, 17), day(3, 24)},
+		},
+		{
+			name:     "weekly on days given out of order",
+			rule:     "FREQ=WEEKLY;BYDAY=TH,TU",
+			start:    start,
+			from:     start,
+			to:       day(3, 13),
+			expected: []time.Time{day(3, 3), day(3, 5), day(3, 10), day(3, 12)},
+		},
+		{
+			name:     "weekly on days before the start in its week",
+			rule:     "FREQ=WEEKLY;BYDAY=MO,WE",
+			start:    start,
+			from:     start,
+			to:       day(3, 12),
+			expected: []time.Time{day(3, 4), day(3, 9), day(3, 11)},
+		},
+		{
+			name:     "every other week on sunday",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;BYDAY=SU",
+			start:    start,
+			from:     start,
+			to:       day(3, 30),
+			expected: []time.Time{day(3, 8), day(3, 22)},
+		},
+		{
+			name:     "monthly skips the months without the day",
+			rule:     "FREQ=MONTHLY",
+			start:    day(1, 31),
+			from:     day(1, 31),
+			to:       day(9, 1),
+			expected: []time.Time{day(1, 31), day(3, 31), day(5, 31), day(7, 31), day(8, 31)},
+		},
+		{
+			name:     "monthly on the 29th outside of leap years",
+			rule:     "FREQ=MONTHLY;INTERVAL=1",
+			start:    day(1, 29),
+			from:     day(1, 29),
+			to:       day(4, 1),
+			expected: []time.Time{day(1, 29), day(3, 29)},
+		},
+		{
+			name:     "count",
+			rule:     "FREQ=DAILY;COUNT=3",
+			start:    start,
+			from:     start,
+			to:       day(4, 1),
+			expected: []time.Time{day(3, 3), day(3, 4), day(3, 5)},
+		},
+		{
+			name:     "count includes the occurrences before from",
+			rule:     "FREQ=DAILY;COUNT=3",
+			start:    start,
+			from:     day(3, 5),
+			to:       day(4, 1),
+			expected: []time.Time{day(3, 5)},
+		},
+		{
+			name:     "count with the days of the week",
+			rule:     "FREQ=WEEKLY;BYDAY=TU,FR;COUNT=3",
+			start:    start,
+			from:     start,
+			to:       day(4, 1),
+			expected: []time.Time{day(3, 3), day(3, 6), day(3, 10)},
+		},
+		{
+			name:     "until is inclusive",
+			rule:     "FREQ=DAILY;UNTIL=20260305T020000Z",
+			start:    start,
+			from:     start,
+			to:       day(4, 1),
+			expected: []time.Time{day(3, 3), day(3, 4), day(3, 5)},
+		},
+		{
+			name:     "until as a date",
+			rule:     "FREQ=WEEKLY;UNTIL=20260317",
+			start:    start,
+			from:     start,
+			to:       day(4, 1),
+			expected: []time.Time{day(3, 3), day(3, 10)},
+		},
+		{
+			name:     "yearly on the 29th of february",
+			rule:     "FREQ=MONTHLY;INTERVAL=12",
+			start:    time.Date(2028, 2, 29, 2, 0, 0, 0, time.UTC),
+			from:     time.Date(2028, 2, 29, 2, 0, 0, 0, time.UTC),
+			to:       time.Date(2033, 1, 1, 0, 0, 0, 0, time.UTC),
+			expected: []time.Time{time.Date(2028, 2, 29, 2, 0, 0, 0, time.UTC), time.Date(2032, 2, 29, 2, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	for _, test := range tests {
+		rule, err := parseRecurrenceRule(test.rule)
+		if err != nil {
+			t.Errorf("%s: unable to parse %s: %v", test.name, test.rule, err)
+			continue
+		}
+
+		occurrences := rule.occurrences(test.start, test.from, test.to)
+
+		if len(occurrences) != len(test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, occurrences)
+			continue
+		}
+
+		for i := range test.expected {
+			if !occurrences[i].Equal(test.expected[i]) {
+				t.Errorf("%s: expected %v, got %v", test.name, test.expected, occurrences)
+				break
+			}
+		}
+	}
+}
+
+func TestRecurrenceRuleIsOccurrence(t *testing.T) {
+	start := time.Date(2026, 1, 31, 2, 0, 0, 0, time.UTC)
+
+	rule, err := parseRecurrenceRule("FREQ=MONTHLY;COUNT=3")
+	if err != nil {
+		t.Fatalf("unable to parse the rule: %v", err)
+	}
+
+	tests := []struct {
+		t        time.Time
+		expected bool
+	}{
+		{t: start, expected: true},
+		{t: time.Date(2026, 3, 31, 2, 0, 0, 0, time.UTC), expected: true},
+		{t: time.Date(2026, 2, 28, 2, 0, 0, 0, time.UTC)},
+		{t: time.Date(2026, 3, 31, 3, 0, 0, 0, time.UTC)},
+		// The fourth occurrence is past the count
+		{t: time.Date(2026, 7, 31, 2, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		if rule.isOccurrence(start, test.t) != test.expected {
+			t.Errorf("expected %v to be an occurrence: %v", test.t, test.expected)
+		}
+	}
+}
+
+func TestParseRecurrenceRuleRejectsInvalidRules(t *testing.T) {
+	rules := []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=YEARLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;INTERVAL=two",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;COUNT=3;UNTIL=20260317",
+		"FREQ=MONTHLY;BYDAY=MO",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYMONTH=3",
+		"FREQ",
+	}
+
+	for _, rule := range rules {
+		if _, err := parseRecurrenceRule(rule); err == nil {
+			t.Errorf("expected %q to be rejected", rule)
+		}
+	}
+}
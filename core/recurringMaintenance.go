@@ -0,0 +1,376 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// ErrInvalidRecurringMaintenance is returned when a recurring maintenance or one of its exceptions isn't valid
+var ErrInvalidRecurringMaintenance = errors.New("invalid recurring maintenance")
+
+// recurringMaintenanceLock keeps the scheduler from creating occurrences while the recurring maintenance changes
+var recurringMaintenanceLock sync.Mutex
+
+// GetRecurringMaintenances gets all of the recurring maintenance
+func GetRecurringMaintenances() ([]*models.RecurringMaintenance, error) {
+	return _dataStore.GetRecurringMaintenances()
+}
+
+// GetRecurringMaintenanceByID gets the recurring maintenance by id, both will be nil if none found
+func GetRecurringMaintenanceByID(id int) (*models.RecurringMaintenance, error) {
+	return _dataStore.GetRecurringMaintenanceByID(id)
+}
+
+// CreateRecurringMaintenance creates the recurring maintenance, its occurrences get scheduled by the scheduler
+func CreateRecurringMaintenance(recurringMaintenance *models.RecurringMaintenance) (*models.RecurringMaintenance, error) {
+	if err := validateRecurringMaintenance(recurringMaintenance); err != nil {
+		return nil, err
+	}
+
+	recurringMaintenance.Exceptions = nil
+	recurringMaintenance.CreatedUntil = time.Time{}
+
+	recurringMaintenanceLock.Lock()
+	defer recurringMaintenanceLock.Unlock()
+
+	if err := _dataStore.CreateRecurringMaintenance(recurringMaintenance); err != nil {
+		return nil, err
+	}
+
+	wakeUpMaintenanceScheduler()
+
+	return recurringMaintenance, nil
+}
+
+// UpdateRecurringMaintenance replaces the definition of the recurring maintenance, which only changes the
+// occurrences which weren't scheduled yet. The recurring maintenance is nil when it doesn't exist.
+func UpdateRecurringMaintenance(recurringMaintenance *models.RecurringMaintenance) (*models.RecurringMaintenance, error) {
+	if err := validateRecurringMaintenance(recurringMaintenance); err != nil {
+		return nil, err
+	}
+
+	recurringMaintenanceLock.Lock()
+	defer recurringMaintenanceLock.Unlock()
+
+	existing, err := _dataStore.GetRecurringMaintenanceByID(recurringMaintenance.ID)
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	recurringMaintenance.Exceptions = existing.Exceptions
+	recurringMaintenance.CreatedUntil = existing.CreatedUntil
+	recurringMaintenance.CreatedAt = existing.CreatedAt
+
+	if err := _dataStore.UpdateRecurringMaintenance(recurringMaintenance); err != nil {
+		return nil, err
+	}
+
+	wakeUpMaintenanceScheduler()
+
+	return recurringMaintenance, nil
+}
+
+// DeleteRecurringMaintenance removes the recurring maintenance, the occurrences already scheduled stay
+func DeleteRecurringMaintenance(id int) error {
+	recurringMaintenanceLock.Lock()
+	defer recurringMaintenanceLock.Unlock()
+
+	return _dataStore.DeleteRecurringMaintenance(id)
+}
+
+// GetRecurringMaintenanceOccurrences gives the next occurrences of the recurring maintenance with their exceptions
+// applied, including the ones in progress. Both will be nil if the recurring maintenance doesn't exist.
+func GetRecurringMaintenanceOccurrences(id int, count int) ([]models.RecurringMaintenanceOccurrence, error) {
+	recurringMaintenance, err := _dataStore.GetRecurringMaintenanceByID(id)
+	if err != nil || recurringMaintenance == nil {
+		return nil, err
+	}
+
+	rule, err := parseRecurrenceRule(recurringMaintenance.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduled, err := recurringMaintenanceScheduled(id)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := time.Duration(recurringMaintenance.DurationMinutes) * time.Minute
+	from := time.Now().Add(-duration)
+
+	occurrences := make([]models.RecurringMaintenanceOccurrence, 0, count)
+
+	// The rule could have no more occurrences, so look ahead a year at a time for a while
+	limit := from.AddDate(10, 0, 0)
+	for to := from.AddDate(1, 0, 0); len(occurrences) < count && !to.After(limit); from, to = to, to.AddDate(1, 0, 0) {
+		for _, occurrence := range rule.occurrences(recurringMaintenance.Start, from, to) {
+			if len(occurrences) == count {
+				break
+			}
+
+			o := recurringMaintenanceOccurrence(recurringMaintenance, occurrence)
+			if m := scheduled[occurrence.Unix()]; m != nil {
+				o.ScheduledMaintenanceID = m.ID
+			}
+
+			occurrences = append(occurrences, o)
+		}
+	}
+
+	return occurrences, nil
+}
+
+// SetRecurringMaintenanceException skips or changes a single occurrence of the recurring maintenance. When the
+// occurrence was scheduled already its scheduled maintenance gets cancelled or changed too, as long as it didn't
+// start. The recurring maintenance is nil when it doesn't exist.
+func SetRecurringMaintenanceException(id int, exception models.RecurringMaintenanceException, actor string) (*models.RecurringMaintenance, error) {
+	recurringMaintenanceLock.Lock()
+	defer recurringMaintenanceLock.Unlock()
+
+	recurringMaintenance, err := _dataStore.GetRecurringMaintenanceByID(id)
+	if err != nil || recurringMaintenance == nil {
+		return nil, err
+	}
+
+	rule, err := parseRecurrenceRule(recurringMaintenance.Rule)
+	if err != nil {
+		return nil, err
+	}
+
+	exception.Occurrence = exception.Occurrence.UTC()
+	if !rule.isOccurrence(recurringMaintenance.Start, exception.Occurrence) {
+		return nil, fmt.Errorf("%w: the recurring maintenance has no occurrence at %s", ErrInvalidRecurringMaintenance, exception.Occurrence.Format(time.RFC3339))
+	}
+
+	if !exception.Skip {
+		if exception.PlannedStart.IsZero() {
+			exception.PlannedStart = exception.Occurrence
+		}
+
+		if exception.PlannedEnd.IsZero() {
+			exception.PlannedEnd = exception.PlannedStart.Add(time.Duration(recurringMaintenance.DurationMinutes) * time.Minute)
+		}
+
+		if !exception.PlannedEnd.After(exception.PlannedStart) || !exception.PlannedStart.After(time.Now()) {
+			return nil, fmt.Errorf("%w: the occurrence must start in the future and end after it starts", ErrInvalidRecurringMaintenance)
+		}
+	}
+
+	scheduled, err := recurringMaintenanceScheduled(id)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduledMaintenance := scheduled[exception.Occurrence.Unix()]
+	if scheduledMaintenance != nil && scheduledMaintenance.Started {
+		return nil, fmt.Errorf("%w: the occurrence already started, change its scheduled maintenance %d instead", ErrInvalidRecurringMaintenance, scheduledMaintenance.ID)
+	}
+
+	if existing := recurringMaintenance.Exception(exception.Occurrence); existing != nil {
+		*existing = exception
+	} else {
+		recurringMaintenance.Exceptions = append(recurringMaintenance.Exceptions, exception)
+	}
+
+	if err := _dataStore.UpdateRecurringMaintenance(recurringMaintenance); err != nil {
+		return nil, err
+	}
+
+	if scheduledMaintenance == nil || scheduledMaintenance.Cancelled {
+		return recurringMaintenance, nil
+	}
+
+	if exception.Skip {
+		if _, err := CancelScheduledMaintenance(scheduledMaintenance.ID, "This occurrence of the recurring maintenance is skipped.", actor); err != nil {
+			return nil, err
+		}
+
+		return recurringMaintenance, nil
+	}
+
+	// Whoever moves the occurrence decides on it, so it's moved even onto other maintenance
+	o := recurringMaintenanceOccurrence(recurringMaintenance, exception.Occurrence)
+	conflicts, err := PatchScheduledMaintenance(&models.ScheduledMaintenance{
+		ID:           scheduledMaintenance.ID,
+		Title:        o.Title,
+		Description:  recurringMaintenanceDescription(recurringMaintenance, exception.Occurrence),
+		PlannedStart: o.PlannedStart,
+		PlannedEnd:   o.PlannedEnd,
+	}, true, actor)
+	if err != nil {
+		return nil, err
+	}
+
+	logScheduledMaintenanceConflicts(scheduledMaintenance.ID, conflicts)
+
+	return recurringMaintenance, nil
+}
+
+// scheduleRecurringMaintenance creates the scheduled maintenance of the occurrences which are due within the lead time.
+// Each occurrence is only considered once, so deleting its scheduled maintenance doesn't bring it back.
+func scheduleRecurringMaintenance(now time.Time) {
+	recurringMaintenanceLock.Lock()
+	defer recurringMaintenanceLock.Unlock()
+
+	recurringMaintenances, err := _dataStore.GetRecurringMaintenances()
+	if err != nil {
+		log.Println("Error while getting the recurring maintenance:", err)
+		return
+	}
+
+	until := now.Add(config.Config.ScheduledMaintenance.RecurringLeadTime)
+
+	for _, recurringMaintenance := range recurringMaintenances {
+		if !recurringMaintenance.Enabled || !recurringMaintenance.CreatedUntil.Before(until) {
+			continue
+		}
+
+		if err := scheduleRecurringMaintenanceOccurrences(recurringMaintenance, now, until); err != nil {
+			log.Printf("Error while scheduling the recurring maintenance %d: %v\n", recurringMaintenance.ID, err)
+		}
+	}
+}
+
+func scheduleRecurringMaintenanceOccurrences(recurringMaintenance *models.RecurringMaintenance, now time.Time, until time.Time) error {
+	rule, err := parseRecurrenceRule(recurringMaintenance.Rule)
+	if err != nil {
+		return err
+	}
+
+	from := recurringMaintenance.CreatedUntil
+	if from.Before(now) {
+		from = now
+	}
+
+	// An earlier run which failed to record how far it got may have created some of the occurrences already
+	scheduled, err := recurringMaintenanceScheduled(recurringMaintenance.ID)
+	if err != nil {
+		return err
+	}
+
+	for _, occurrence := range rule.occurrences(recurringMaintenance.Start, from, until) {
+		o := recurringMaintenanceOccurrence(recurringMaintenance, occurrence)
+		if o.Skipped || !o.PlannedStart.After(now) || scheduled[occurrence.Unix()] != nil {
+			continue
+		}
+
+		services := make([]models.ServiceUpdate, len(recurringMaintenance.Services))
+		copy(services, recurringMaintenance.Services)
+
+		// Nobody is there to confirm, so occurrences are scheduled even when they conflict
+		created, conflicts, err := CreateScheduledMaintenance(&models.ScheduledMaintenance{
+			Title:                  o.Title,
+			Description:            recurringMaintenanceDescription(recurringMaintenance, occurrence),
+			Services:               services,
+			PlannedStart:           o.PlannedStart,
+			PlannedEnd:             o.PlannedEnd,
+			RecurringMaintenanceID: recurringMaintenance.ID,
+			Occurrence:             occurrence,
+		}, true)
+		if err != nil {
+			return err
+		}
+
+		logScheduledMaintenanceConflicts(created.ID, conflicts)
+
+		// Recorded right away, so a failure with a later occurrence doesn't create this one again
+		recurringMaintenance.CreatedUntil = occurrence.Add(time.Nanosecond)
+		if err := _dataStore.UpdateRecurringMaintenance(recurringMaintenance); err != nil {
+			return err
+		}
+	}
+
+	recurringMaintenance.CreatedUntil = until
+
+	return _dataStore.UpdateRecurringMaintenance(recurringMaintenance)
+}
+
+// recurringMaintenanceOccurrence gives the occurrence as planned by the recurring maintenance and its exception
+func recurringMaintenanceOccurrence(recurringMaintenance *models.RecurringMaintenance, occurrence time.Time) models.RecurringMaintenanceOccurrence {
+	o := models.RecurringMaintenanceOccurrence{
+		Occurrence:   occurrence,
+		Title:        recurringMaintenance.Title,
+		PlannedStart: occurrence,
+		PlannedEnd:   occurrence.Add(time.Duration(recurringMaintenance.DurationMinutes) * time.Minute),
+	}
+
+	if exception := recurringMaintenance.Exception(occurrence); exception != nil {
+		o.Skipped = exception.Skip
+
+		if !exception.Skip {
+			o.PlannedStart = exception.PlannedStart
+			o.PlannedEnd = exception.PlannedEnd
+
+			if exception.Title != "" {
+				o.Title = exception.Title
+			}
+		}
+	}
+
+	return o
+}
+
+func recurringMaintenanceDescription(recurringMaintenance *models.RecurringMaintenance, occurrence time.Time) string {
+	if exception := recurringMaintenance.Exception(occurrence); exception != nil && exception.Description != "" {
+		return exception.Description
+	}
+
+	return recurringMaintenance.Description
+}
+
+// recurringMaintenanceScheduled gives the scheduled maintenance created for the recurring maintenance, keyed by the
+// unix time of their occurrence
+func recurringMaintenanceScheduled(id int) (map[int64]*models.ScheduledMaintenance, error) {
+	scheduledMaintenances, err := _dataStore.GetScheduledMaintenance(false)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduled := make(map[int64]*models.ScheduledMaintenance)
+	for _, m := range scheduledMaintenances {
+		if m.RecurringMaintenanceID == id {
+			scheduled[m.Occurrence.Unix()] = m
+		}
+	}
+
+	return scheduled, nil
+}
+
+func validateRecurringMaintenance(recurringMaintenance *models.RecurringMaintenance) error {
+	if recurringMaintenance.Title == "" {
+		recurringMaintenance.Title = "Scheduled Maintenance"
+	}
+
+	if _, err := parseRecurrenceRule(recurringMaintenance.Rule); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRecurringMaintenance, err)
+	}
+
+	if recurringMaintenance.Start.IsZero() {
+		return fmt.Errorf("%w: start is missing", ErrInvalidRecurringMaintenance)
+	}
+
+	recurringMaintenance.Start = recurringMaintenance.Start.UTC()
+
+	if recurringMaintenance.DurationMinutes <= 0 {
+		return fmt.Errorf("%w: durationMinutes must be positive", ErrInvalidRecurringMaintenance)
+	}
+
+	for i := range recurringMaintenance.Services {
+		if recurringMaintenance.Services[i].Status == "" {
+			recurringMaintenance.Services[i].Status = models.ServiceStatusScheduledMaintenance
+		}
+	}
+
+	if err := validateServiceUpdates(recurringMaintenance.Services, true); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidRecurringMaintenance, err)
+	}
+
+	return nil
+}
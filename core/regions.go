@@ -19,7 +19,36 @@ func GetRegionByCodeAndServiceName(regionCode, serviceName string) (*models.Regi
 
 // CreateRegion creates the service in the storage layer
 func CreateRegion(region *models.Region) error {
-	return _dataStore.CreateRegion(region)
+	if err := _dataStore.CreateRegion(region); err != nil {
+		return err
+	}
+
+	if err := recordInitialRegionStatus(region); err != nil {
+		return err
+	}
+
+	invalidateDailyStatusHistory()
+
+	return nil
+}
+
+// recordInitialRegionStatus starts the status history of the region with its current status, so the days from
+// then on have data even when its status never changes
+func recordInitialRegionStatus(region *models.Region) error {
+	status := region.Status
+	if status == "" {
+		status = models.ServiceStatusUnknown
+	}
+
+	return _dataStore.CreateStatusChange(&models.StatusChange{
+		ServiceID:      region.ServiceID,
+		ServiceName:    region.ServiceName,
+		RegionID:       region.ID,
+		RegionCode:     region.RegionCode,
+		RegionName:     region.Name,
+		PreviousStatus: models.ServiceStatusUnknown,
+		Status:         status,
+	})
 }
 
 // ValidateAndCreateRegion checks if a region has all necessary info and creates it
@@ -57,7 +86,13 @@ func ValidateAndCreateRegion(region models.Region) (models.Region, error) {
 
 // DeleteRegion deletes a region in the storage layer
 func DeleteRegion(id int) error {
-	return _dataStore.DeleteRegion(id)
+	if err := _dataStore.DeleteRegion(id); err != nil {
+		return err
+	}
+
+	invalidateDailyStatusHistory()
+
+	return nil
 }
 
 func createRegionsFromConfig() error {
@@ -95,7 +130,7 @@ func createRegionsFromConfig() error {
 	return nil
 }
 
-func updateRegionToStatus(regionCode, serviceName string, status models.ServiceAndRegionStatus) error {
+func updateRegionToStatus(regionCode, serviceName string, status models.ServiceAndRegionStatus, cause statusChangeCause) error {
 	region, err := GetRegionByCodeAndServiceName(regionCode, serviceName)
 
 	if err != nil {
@@ -112,7 +147,42 @@ func updateRegionToStatus(regionCode, serviceName string, status models.ServiceA
 		return errors.New("invalid region status")
 	}
 
+	previousStatus := region.Status
 	region.Status = val
 
-	return _dataStore.UpdateRegion(region)
+	if err := _dataStore.UpdateRegion(region); err != nil {
+		return err
+	}
+
+	if previousStatus != val {
+		if err := _dataStore.CreateStatusChange(&models.StatusChange{
+			ServiceID:              region.ServiceID,
+			ServiceName:            region.ServiceName,
+			RegionID:               region.ID,
+			RegionCode:             region.RegionCode,
+			RegionName:             region.Name,
+			PreviousStatus:         previousStatus,
+			Status:                 val,
+			IncidentID:             cause.IncidentID,
+			ScheduledMaintenanceID: cause.ScheduledMaintenanceID,
+			Actor:                  cause.Actor,
+		}); err != nil {
+			return err
+		}
+
+		invalidateDailyStatusHistory()
+
+		notify(&models.Event{
+			Type: models.EventServiceStatusChanged,
+			StatusChange: &models.ServiceStatusChange{
+				ServiceName:    region.ServiceName,
+				RegionCode:     region.RegionCode,
+				RegionName:     region.Name,
+				PreviousStatus: previousStatus,
+				Status:         val,
+			},
+		})
+	}
+
+	return nil
 }
@@ -0,0 +1,300 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+var rocketChatHTTPClient = &http.Client{Timeout: 5 * time.Second}
+
+// rocketChatStatusColors holds the attachment color used for each of the service statuses
+var rocketChatStatusColors = map[models.ServiceAndRegionStatus]string{
+	models.ServiceStatusNominal:              "#2de0a5",
+	models.ServiceStatusDegraded:             "#ffd21f",
+	models.ServiceStatusPartialOutage:        "#f38c39",
+	models.ServiceStatusOutage:               "#f5455c",
+	models.ServiceStatusScheduledMaintenance: "#1d74f5",
+	models.ServiceStatusUnknown:              "#9ea2a8",
+}
+
+type rocketChatMessage struct {
+	Text        string                 `json:"text"`
+	Attachments []rocketChatAttachment `json:"attachments"`
+}
+
+type rocketChatAttachment struct {
+	Title     string            `json:"title"`
+	TitleLink string            `json:"title_link"`
+	Text      string            `json:"text"`
+	Color     string            `json:"color"`
+	Fields    []rocketChatField `json:"fields,omitempty"`
+}
+
+type rocketChatField struct {
+	Short bool   `json:"short"`
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+func init() {
+	RegisterNotifierType("rocketchat", newRocketChatNotifier)
+
+	// Slack incoming webhooks accept the very same message format
+	RegisterNotifierType("slack", newRocketChatNotifier)
+}
+
+// rocketChatNotifier posts the incidents and scheduled maintenance to Rocket.Chat incoming webhooks
+type rocketChatNotifier struct {
+	name        string
+	webhookURLs []string
+}
+
+func newRocketChatNotifier(name string, settings NotifierSettings) (Notifier, error) {
+	if err := requireSettings(settings, "webhookUrls"); err != nil {
+		return nil, err
+	}
+
+	return &rocketChatNotifier{
+		name:        name,
+		webhookURLs: settings.Strings("webhookUrls"),
+	}, nil
+}
+
+func (r *rocketChatNotifier) Name() string {
+	return r.name
+}
+
+func (r *rocketChatNotifier) Notify(event *models.Event) (string, error) {
+	var message rocketChatMessage
+	var err error
+
+	switch event.Type {
+	case models.EventIncidentCreated:
+		message, err = rocketChatIncidentMessage(event.Incident)
+	case models.EventIncidentUpdated, models.EventIncidentResolved:
+		message, err = rocketChatIncidentUpdateMessage(event.Incident, event.Update)
+	case models.EventMaintenanceCreated:
+		message, err = rocketChatScheduledMaintenanceMessage(event.ScheduledMaintenance)
+	case models.EventMaintenanceStarted, models.EventMaintenanceUpdated, models.EventMaintenanceCompleted, models.EventMaintenanceExtended, models.EventMaintenanceCancelled:
+		message, err = rocketChatScheduledMaintenanceUpdateMessage(event.ScheduledMaintenance, event.Update)
+	case models.EventIncidentPostmortemPublished:
+		message, err = rocketChatPostmortemMessage(event.Incident)
+	default:
+		return "", nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	// Incoming webhooks don't give back the id of the message, so there is nothing to keep track of
+	return "", r.send(message)
+}
+
+func rocketChatIncidentMessage(incident *models.Incident) (rocketChatMessage, error) {
+	text, err := renderRocketChatTemplate("create.tmpl", incident)
+	if err != nil {
+		return rocketChatMessage{}, err
+	}
+
+	return rocketChatMessage{
+		Text: fmt.Sprintf("New incident: %s", incident.Title),
+		Attachments: []rocketChatAttachment{{
+			Title:     incident.Title,
+			TitleLink: fmt.Sprintf("%s/i/%d", websiteURL(), incident.ID),
+			Text:      text,
+			Color:     rocketChatColor(incident.Services),
+			Fields:    rocketChatServiceFields(incident.Services),
+		}},
+	}, nil
+}
+
+func rocketChatIncidentUpdateMessage(incident *models.Incident, update *models.StatusUpdate) (rocketChatMessage, error) {
+	text, err := renderRocketChatTemplate("update.tmpl", map[string]interface{}{
+		"update":   update,
+		"incident": incident,
+	})
+	if err != nil {
+		return rocketChatMessage{}, err
+	}
+
+	color := rocketChatColor(incident.Services)
+	if update.Status == models.IncidentStatusResolved {
+		color = rocketChatStatusColors[models.ServiceStatusNominal]
+	}
+
+	return rocketChatMessage{
+		Text: fmt.Sprintf("Incident update: %s", incident.Title),
+		Attachments: []rocketChatAttachment{{
+			Title:     incident.Title,
+			TitleLink: fmt.Sprintf("%s/i/%d", websiteURL(), incident.ID),
+			Text:      text,
+			Color:     color,
+			Fields:    rocketChatServiceFields(incident.Services),
+		}},
+	}, nil
+}
+
+func rocketChatPostmortemMessage(incident *models.Incident) (rocketChatMessage, error) {
+	text, err := renderRocketChatTemplate("postmortem.tmpl", incident)
+	if err != nil {
+		return rocketChatMessage{}, err
+	}
+
+	return rocketChatMessage{
+		Text: fmt.Sprintf("Postmortem: %s", incident.Title),
+		Attachments: []rocketChatAttachment{{
+			Title:     incident.Title,
+			TitleLink: fmt.Sprintf("%s/i/%d", websiteURL(), incident.ID),
+			Text:      text,
+			Color:     rocketChatStatusColors[models.ServiceStatusNominal],
+		}},
+	}, nil
+}
+
+func rocketChatScheduledMaintenanceMessage(scheduledMaintenance *models.ScheduledMaintenance) (rocketChatMessage, error) {
+	text, err := renderRocketChatTemplate("maintenance.tmpl", scheduledMaintenance)
+	if err != nil {
+		return rocketChatMessage{}, err
+	}
+
+	return rocketChatMessage{
+		Text: fmt.Sprintf("Scheduled maintenance: %s", scheduledMaintenance.Title),
+		Attachments: []rocketChatAttachment{{
+			Title:     scheduledMaintenance.Title,
+			TitleLink: fmt.Sprintf("%s/m/%d", websiteURL(), scheduledMaintenance.ID),
+			Text:      text,
+			Color:     rocketChatStatusColors[models.ServiceStatusScheduledMaintenance],
+			Fields:    rocketChatServiceFields(scheduledMaintenance.Services),
+		}},
+	}, nil
+}
+
+func rocketChatScheduledMaintenanceUpdateMessage(scheduledMaintenance *models.ScheduledMaintenance, update *models.StatusUpdate) (rocketChatMessage, error) {
+	text, err := renderRocketChatTemplate("maintenanceUpdate.tmpl", map[string]interface{}{
+		"update":      update,
+		"maintenance": scheduledMaintenance,
+	})
+	if err != nil {
+		return rocketChatMessage{}, err
+	}
+
+	color := rocketChatStatusColors[models.ServiceStatusScheduledMaintenance]
+	if update.Status == models.IncidentStatusResolved {
+		color = rocketChatStatusColors[models.ServiceStatusNominal]
+	}
+
+	return rocketChatMessage{
+		Text: fmt.Sprintf("Scheduled maintenance update: %s", scheduledMaintenance.Title),
+		Attachments: []rocketChatAttachment{{
+			Title:     scheduledMaintenance.Title,
+			TitleLink: fmt.Sprintf("%s/m/%d", websiteURL(), scheduledMaintenance.ID),
+			Text:      text,
+			Color:     color,
+			Fields:    rocketChatServiceFields(scheduledMaintenance.Services),
+		}},
+	}, nil
+}
+
+func renderRocketChatTemplate(name string, data interface{}) (string, error) {
+	tmpl, err := template.ParseFiles("templates/incident/rocketchat/" + name)
+	if err != nil {
+		return "", err
+	}
+
+	b := &bytes.Buffer{}
+	if err := tmpl.ExecuteTemplate(b, tmpl.Name(), data); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(b.String()), nil
+}
+
+// rocketChatColor picks the attachment color based on the worst status of the services. Unknown ranks below
+// nominal, but an incident about services in an unknown state shouldn't look like all is fine.
+func rocketChatColor(services []models.ServiceUpdate) string {
+	statuses := make([]models.ServiceAndRegionStatus, 0, len(services))
+	unknown := false
+	for _, s := range services {
+		statuses = append(statuses, s.Status)
+		unknown = unknown || s.Status == models.ServiceStatusUnknown
+	}
+
+	worst := models.WorstServiceStatus(statuses...)
+	if worst == models.ServiceStatusNominal && unknown {
+		worst = models.ServiceStatusUnknown
+	}
+
+	return rocketChatStatusColors[worst]
+}
+
+func rocketChatServiceFields(services []models.ServiceUpdate) []rocketChatField {
+	fields := make([]rocketChatField, 0, len(services))
+
+	for _, s := range services {
+		title := s.Name
+		if len(s.Regions) > 0 {
+			title = fmt.Sprintf("%s (%s)", s.Name, strings.Join(s.Regions, ", "))
+		}
+
+		fields = append(fields, rocketChatField{
+			Short: true,
+			Title: title,
+			Value: s.Status.String(),
+		})
+	}
+
+	return fields
+}
+
+// send posts the message to every configured webhook, trying all of them even if one fails
+func (r *rocketChatNotifier) send(message rocketChatMessage) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	failed := make([]string, 0)
+
+	for i, webhookURL := range r.webhookURLs {
+		if err := postRocketChatMessage(webhookURL, body); err != nil {
+			// Never log the url itself, the token is part of it
+			log.Printf("Error while posting to Rocket.Chat webhook #%d: %v\n", i, err)
+			failed = append(failed, fmt.Sprintf("#%d", i))
+		}
+	}
+
+	if len(failed) > 0 {
+		return errors.New("failed to post to Rocket.Chat webhooks " + strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+func postRocketChatMessage(webhookURL string, body []byte) error {
+	resp, err := rocketChatHTTPClient.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		// The error contains the url, which holds the token
+		return errors.New("request failed")
+	}
+
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64*1024)) //nolint:errcheck // Only draining so the connection can be reused
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,277 @@
+package core
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// rocketChatReceiver is an incoming webhook keeping the messages posted to it
+type rocketChatReceiver struct {
+	server   *httptest.Server
+	mu       sync.Mutex
+	status   int
+	messages []rocketChatMessage
+}
+
+func newRocketChatReceiver(t *testing.T, status int) *rocketChatReceiver {
+	r := &rocketChatReceiver{status: status}
+
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodPost {
+			t.Errorf("expected a POST, got %s", req.Method)
+		}
+
+		if contentType := req.Header.Get("Content-Type"); contentType != "application/json" {
+			t.Errorf("expected a json body, got %q", contentType)
+		}
+
+		body, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			t.Errorf("unable to read the body: %v", err)
+		}
+
+		var message rocketChatMessage
+		if err := json.Unmarshal(body, &message); err != nil {
+			t.Errorf("unable to parse the body %s: %v", body, err)
+		}
+
+		r.mu.Lock()
+		r.messages = append(r.messages, message)
+		r.mu.Unlock()
+
+		w.WriteHeader(r.status)
+	}))
+
+	t.Cleanup(r.server.Close)
+
+	return r
+}
+
+func (r *rocketChatReceiver) received() []rocketChatMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]rocketChatMessage(nil), r.messages...)
+}
+
+func newTestRocketChatNotifier(t *testing.T, receivers ...*rocketChatReceiver) Notifier {
+	urls := make([]interface{}, 0, len(receivers))
+	for _, r := range receivers {
+		urls = append(urls, r.server.URL+"/hooks/secret-token")
+	}
+
+	notifier, err := newRocketChatNotifier("rocketchat", NotifierSettings{"webhookUrls": urls})
+	if err != nil {
+		t.Fatalf("unable to create the notifier: %v", err)
+	}
+
+	return notifier
+}
+
+func TestRocketChatNotifierPostsToEveryWebhook(t *testing.T) {
+	first := newRocketChatReceiver(t, http.StatusOK)
+	second := newRocketChatReceiver(t, http.StatusOK)
+	notifier := newTestRocketChatNotifier(t, first, second)
+
+	incident := &models.Incident{
+		ID:     7,
+		Title:  "Push notifications delayed",
+		Status: models.IncidentStatusInvestigating,
+		Services: []models.ServiceUpdate{
+			{Name: "Push Gateway", Status: models.ServiceStatusDegraded, Regions: []string{"eu", "us"}},
+			{Name: "Cloud", Status: models.ServiceStatusNominal},
+		},
+	}
+
+	if _, err := notifier.Notify(&models.Event{Type: models.EventIncidentCreated, Incident: incident}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := rocketChatMessage{
+		Text: "New incident: Push notifications delayed",
+		Attachments: []rocketChatAttachment{{
+			Title:     "Push notifications delayed",
+			TitleLink: "https://status.example.com/i/7",
+			Text:      "We detected an incident affecting our services:\n\n*Investigating* - Push notifications delayed",
+			Color:     rocketChatStatusColors[models.ServiceStatusDegraded],
+			Fields: []rocketChatField{
+				{Short: true, Title: "Push Gateway (eu, us)", Value: "Degraded"},
+				{Short: true, Title: "Cloud", Value: "Nominal"},
+			},
+		}},
+	}
+
+	for name, receiver := range map[string]*rocketChatReceiver{"first": first, "second": second} {
+		messages := receiver.received()
+		if len(messages) != 1 {
+			t.Fatalf("the %s webhook received %d messages, expected 1", name, len(messages))
+		}
+
+		got, _ := json.Marshal(messages[0])
+		want, _ := json.Marshal(expected)
+		if string(got) != string(want) {
+			t.Errorf("the %s webhook received\n%s\nexpected\n%s", name, got, want)
+		}
+	}
+}
+
+func TestRocketChatNotifierColors(t *testing.T) {
+	tests := []struct {
+		name     string
+		event    *models.Event
+		expected string
+	}{
+		{
+			name: "nominal incident",
+			event: &models.Event{Type: models.EventIncidentCreated, Incident: &models.Incident{
+				Services: []models.ServiceUpdate{{Name: "Cloud", Status: models.ServiceStatusNominal}},
+			}},
+			expected: "#2de0a5",
+		},
+		{
+			name: "degraded incident",
+			event: &models.Event{Type: models.EventIncidentCreated, Incident: &models.Incident{
+				Services: []models.ServiceUpdate{{Name: "Cloud", Status: models.ServiceStatusDegraded}},
+			}},
+			expected: "#ffd21f",
+		},
+		{
+			name: "partial outage incident",
+			event: &models.Event{Type: models.EventIncidentCreated, Incident: &models.Incident{
+				Services: []models.ServiceUpdate{{Name: "Cloud", Status: models.ServiceStatusPartialOutage}},
+			}},
+			expected: "#f38c39",
+		},
+		{
+			name: "outage incident",
+			event: &models.Event{Type: models.EventIncidentCreated, Incident: &models.Incident{
+				Services: []models.ServiceUpdate{{Name: "Cloud", Status: models.ServiceStatusOutage}},
+			}},
+			expected: "#f5455c",
+		},
+		{
+			name: "unknown incident",
+			event: &models.Event{Type: models.EventIncidentCreated, Incident: &models.Incident{
+				Services: []models.ServiceUpdate{{Name: "Cloud", Status: models.ServiceStatusUnknown}},
+			}},
+			expected: "#9ea2a8",
+		},
+		{
+			name: "unknown and nominal incident",
+			event: &models.Event{Type: models.EventIncidentCreated, Incident: &models.Incident{
+				Services: []models.ServiceUpdate{
+					{Name: "Cloud", Status: models.ServiceStatusUnknown},
+					{Name: "Marketplace", Status: models.ServiceStatusNominal},
+				},
+			}},
+			expected: "#9ea2a8",
+		},
+		{
+			name: "worst status of the services",
+			event: &models.Event{Type: models.EventIncidentCreated, Incident: &models.Incident{
+				Services: []models.ServiceUpdate{
+					{Name: "Cloud", Status: models.ServiceStatusDegraded},
+					{Name: "Push Gateway", Status: models.ServiceStatusOutage},
+					{Name: "Marketplace", Status: models.ServiceStatusNominal},
+				},
+			}},
+			expected: "#f5455c",
+		},
+		{
+			name: "incident update",
+			event: &models.Event{
+				Type:     models.EventIncidentUpdated,
+				Incident: &models.Incident{Services: []models.ServiceUpdate{{Name: "Cloud", Status: models.ServiceStatusPartialOutage}}},
+				Update:   &models.StatusUpdate{Status: models.IncidentStatusIdentified, Message: "Found it"},
+			},
+			expected: "#f38c39",
+		},
+		{
+			name: "resolved incident",
+			event: &models.Event{
+				Type:     models.EventIncidentResolved,
+				Incident: &models.Incident{Services: []models.ServiceUpdate{{Name: "Cloud", Status: models.ServiceStatusOutage}}},
+				Update:   &models.StatusUpdate{Status: models.IncidentStatusResolved, Message: "Fixed"},
+			},
+			expected: "#2de0a5",
+		},
+		{
+			name: "scheduled maintenance",
+			event: &models.Event{Type: models.EventMaintenanceCreated, ScheduledMaintenance: &models.ScheduledMaintenance{
+				Services: []models.ServiceUpdate{{Name: "Cloud", Status: models.ServiceStatusScheduledMaintenance}},
+			}},
+			expected: "#1d74f5",
+		},
+		{
+			name: "completed scheduled maintenance",
+			event: &models.Event{
+				Type:                 models.EventMaintenanceCompleted,
+				ScheduledMaintenance: &models.ScheduledMaintenance{},
+				Update:               &models.StatusUpdate{Status: models.IncidentStatusResolved, Message: "Done"},
+			},
+			expected: "#2de0a5",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			receiver := newRocketChatReceiver(t, http.StatusOK)
+
+			if _, err := newTestRocketChatNotifier(t, receiver).Notify(test.event); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			messages := receiver.received()
+			if len(messages) != 1 || len(messages[0].Attachments) != 1 {
+				t.Fatalf("expected a message with one attachment, got %+v", messages)
+			}
+
+			if color := messages[0].Attachments[0].Color; color != test.expected {
+				t.Errorf("expected the color %s, got %s", test.expected, color)
+			}
+		})
+	}
+}
+
+func TestRocketChatNotifierKeepsPostingWhenAWebhookFails(t *testing.T) {
+	failing := newRocketChatReceiver(t, http.StatusInternalServerError)
+	working := newRocketChatReceiver(t, http.StatusOK)
+	notifier := newTestRocketChatNotifier(t, failing, working)
+
+	_, err := notifier.Notify(&models.Event{Type: models.EventIncidentCreated, Incident: &models.Incident{Title: "Down"}})
+	if err == nil {
+		t.Fatal("expected an error for the failing webhook")
+	}
+
+	if !strings.Contains(err.Error(), "#0") || strings.Contains(err.Error(), "#1") {
+		t.Errorf("expected only the first webhook to be reported, got %q", err)
+	}
+
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("the error leaks the webhook url: %q", err)
+	}
+
+	if len(failing.received()) != 1 || len(working.received()) != 1 {
+		t.Errorf("expected both webhooks to receive the message, got %d and %d", len(failing.received()), len(working.received()))
+	}
+}
+
+func TestRocketChatNotifierIgnoresOtherEvents(t *testing.T) {
+	receiver := newRocketChatReceiver(t, http.StatusOK)
+
+	event := &models.Event{Type: models.EventServiceStatusChanged, StatusChange: &models.ServiceStatusChange{ServiceName: "Cloud"}}
+	if _, err := newTestRocketChatNotifier(t, receiver).Notify(event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if messages := receiver.received(); len(messages) != 0 {
+		t.Errorf("expected nothing to be posted, got %+v", messages)
+	}
+}
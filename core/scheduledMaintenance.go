@@ -1,19 +1,26 @@
 package core
 
 import (
-	"bytes"
 	"errors"
+	"fmt"
 	"sort"
 	"strings"
-	"text/template"
+	"sync"
 	"time"
 
-	"github.com/RocketChat/statuscentral/config"
 	"github.com/RocketChat/statuscentral/models"
-	"github.com/dghubble/go-twitter/twitter"
-	"github.com/dghubble/oauth1"
 )
 
+// scheduledMaintenanceCalendarHistory is how long past scheduled maintenance stays in the calendar feed
+const scheduledMaintenanceCalendarHistory = 90 * 24 * time.Hour
+
+// ErrInvalidScheduledMaintenance is returned when the window of a scheduled maintenance isn't valid
+var ErrInvalidScheduledMaintenance = errors.New("invalid scheduled maintenance")
+
+// maintenanceLock keeps the scheduler and the changes made through the api from changing the same maintenance at
+// once. Whoever holds it loads the maintenance again, so a maintenance cancelled meanwhile isn't started after all.
+var maintenanceLock sync.Mutex
+
 // GetScheduledMaintenance retrieves the scheduled maintenance from the storage layer
 func GetScheduledMaintenance(latest bool) ([]*models.ScheduledMaintenance, error) {
 	return _dataStore.GetScheduledMaintenance(latest)
@@ -24,60 +31,81 @@ func GetScheduledMaintenanceByID(id int) (*models.ScheduledMaintenance, error) {
 	return _dataStore.GetScheduledMaintenanceByID(id)
 }
 
-// SendScheduledMaintenanceTwitter sends the info about the scheduled maintenance to the offical Rocket.Chat Cloud twitter account.
-func SendScheduledMaintenanceTwitter(incident *models.ScheduledMaintenance) (int64, error) {
-	conf := oauth1.NewConfig(config.Config.Twitter.ConsumerKey, config.Config.Twitter.ConsumerSecret)
-	token := oauth1.NewToken(config.Config.Twitter.AccessToken, config.Config.Twitter.AccessSecret)
-	http := conf.Client(oauth1.NoContext, token)
-	http.Timeout = 5 * time.Second
-
-	client := twitter.NewClient(http)
-	tmpl, err := template.ParseFiles("templates/incident/tweet/maintenance.tmpl")
+// GetPendingScheduledMaintenance gets the scheduled maintenance which isn't completed or cancelled, by planned start
+func GetPendingScheduledMaintenance() ([]*models.ScheduledMaintenance, error) {
+	scheduledMaintenances, err := _dataStore.GetScheduledMaintenance(false)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	b := &bytes.Buffer{}
-	if err = tmpl.ExecuteTemplate(b, tmpl.Name(), incident); err != nil {
-		return 0, err
+	pending := make([]*models.ScheduledMaintenance, 0)
+	for _, m := range scheduledMaintenances {
+		if !m.Completed && !m.Cancelled {
+			pending = append(pending, m)
+		}
 	}
 
-	tweet, _, err := client.Statuses.Update(b.String(), nil)
-	if err != nil {
-		return 0, err
-	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].PlannedStart.Before(pending[j].PlannedStart)
+	})
 
-	return tweet.ID, nil
+	return pending, nil
 }
 
-// SendScheduledMaintenanceUpdateTwitter sends the info about the update to scheduled maintenance to the twitter
-func SendScheduledMaintenanceUpdateTwitter(scheduledMaintenance *models.ScheduledMaintenance, update *models.StatusUpdate) (int64, error) {
-	conf := oauth1.NewConfig(config.Config.Twitter.ConsumerKey, config.Config.Twitter.ConsumerSecret)
-	token := oauth1.NewToken(config.Config.Twitter.AccessToken, config.Config.Twitter.AccessSecret)
-	http := conf.Client(oauth1.NoContext, token)
-	http.Timeout = 5 * time.Second
+// GetScheduledMaintenanceCalendar gets the scheduled maintenance to put in the calendar feed, which includes
+// the deleted ones so calendars are told about them being cancelled
+func GetScheduledMaintenanceCalendar() ([]*models.ScheduledMaintenance, error) {
+	scheduledMaintenances, err := _dataStore.GetScheduledMaintenance(false)
+	if err != nil {
+		return nil, err
+	}
 
-	client := twitter.NewClient(http)
-	tmpl, err := template.ParseFiles("templates/incident/tweet/maintenance.tmpl")
+	deleted, err := _dataStore.GetDeletedScheduledMaintenance()
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	since := time.Now().Add(-scheduledMaintenanceCalendarHistory)
+
+	calendar := make([]*models.ScheduledMaintenance, 0, len(scheduledMaintenances)+len(deleted))
+	for _, m := range append(scheduledMaintenances, deleted...) {
+		if m.PlannedEnd.After(since) {
+			calendar = append(calendar, m)
+		}
 	}
 
-	b := &bytes.Buffer{}
-	if err = tmpl.ExecuteTemplate(b, tmpl.Name(), scheduledMaintenance); err != nil {
-		return 0, err
+	sort.Slice(calendar, func(i, j int) bool {
+		return calendar[i].PlannedStart.Before(calendar[j].PlannedStart)
+	})
+
+	return calendar, nil
+}
+
+// GetScheduledMaintenanceForCalendar retrieves the scheduled maintenance by id, falling back to the deleted ones.
+// Both scheduled maintenance and error will be nil if none found
+func GetScheduledMaintenanceForCalendar(id int) (*models.ScheduledMaintenance, error) {
+	scheduledMaintenance, err := _dataStore.GetScheduledMaintenanceByID(id)
+	if err != nil || scheduledMaintenance != nil {
+		return scheduledMaintenance, err
 	}
 
-	tweet, _, err := client.Statuses.Update(b.String(), nil)
+	deleted, err := _dataStore.GetDeletedScheduledMaintenance()
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	for _, m := range deleted {
+		if m.ID == id {
+			return m, nil
+		}
 	}
 
-	return tweet.ID, nil
+	return nil, nil
 }
 
-// CreateScheduledMaintenance creates scheduled maintenance in the storage layer
-func CreateScheduledMaintenance(scheduledMaintenance *models.ScheduledMaintenance) (*models.ScheduledMaintenance, error) {
+// CreateScheduledMaintenance creates scheduled maintenance in the storage layer. When it conflicts with other
+// maintenance or open incidents it's only created if forced, the conflicts are given either way.
+func CreateScheduledMaintenance(scheduledMaintenance *models.ScheduledMaintenance, force bool) (*models.ScheduledMaintenance, []models.MaintenanceConflict, error) {
 	ensureScheduledMaintenanceDefaults(scheduledMaintenance)
 
 	if len(scheduledMaintenance.Updates) > 0 {
@@ -87,37 +115,46 @@ func CreateScheduledMaintenance(scheduledMaintenance *models.ScheduledMaintenanc
 	scheduledMaintenance.CreatedAt = time.Now()
 
 	if scheduledMaintenance.PlannedStart.Before(scheduledMaintenance.CreatedAt) || scheduledMaintenance.PlannedEnd.Before(scheduledMaintenance.CreatedAt) {
-		return nil, errors.New("start and end date must be in the future")
+		return nil, nil, fmt.Errorf("%w: start and end date must be in the future", ErrInvalidScheduledMaintenance)
+	}
+
+	if !scheduledMaintenance.PlannedEnd.After(scheduledMaintenance.PlannedStart) {
+		return nil, nil, fmt.Errorf("%w: the end must be after the start", ErrInvalidScheduledMaintenance)
+	}
+
+	conflicts, err := checkScheduledMaintenanceConflicts(scheduledMaintenance, force)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	if err := _dataStore.CreateScheduledMaintenance(scheduledMaintenance); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	// Todo: we need to figure out how we want this to look
-	/*if config.Config.Twitter.Enabled {
-		tweetID, err := SendScheduledMaintenanceTwitter(scheduledMaintenance)
-		if err == nil {
-			scheduledMaintenance.OriginalTweetID = tweetID
-			if err := _dataStore.UpdateScheduledMaintenance(scheduledMaintenance); err != nil {
-				return nil, err
-			}
-		}
-	}*/
+	wakeUpMaintenanceScheduler()
 
-	return scheduledMaintenance, nil
+	notify(&models.Event{
+		Type:                 models.EventMaintenanceCreated,
+		ScheduledMaintenance: scheduledMaintenance,
+	})
+
+	return scheduledMaintenance, conflicts, nil
 }
 
-// PatchScheduledMaintenance updates the scheduled maintenance in the storage layer
-func PatchScheduledMaintenance(scheduledMaintenance *models.ScheduledMaintenance) error {
+// PatchScheduledMaintenance updates the scheduled maintenance in the storage layer. Moving the window of pending
+// maintenance onto other maintenance or open incidents is only done if forced, the conflicts are given either way.
+// A moved window is announced with an update, changing only the title or description isn't.
+func PatchScheduledMaintenance(scheduledMaintenance *models.ScheduledMaintenance, force bool, actor string) ([]models.MaintenanceConflict, error) {
+	maintenanceLock.Lock()
+	defer maintenanceLock.Unlock()
 
 	existingMaintenance, err := _dataStore.GetScheduledMaintenanceByID(scheduledMaintenance.ID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if existingMaintenance == nil {
-		return errors.New("invalid scheduledMaintenance")
+		return nil, errors.New("invalid scheduledMaintenance")
 	}
 
 	scheduledMaintenance.UpdatedAt = time.Now()
@@ -130,8 +167,16 @@ func PatchScheduledMaintenance(scheduledMaintenance *models.ScheduledMaintenance
 		scheduledMaintenance.Description = existingMaintenance.Description
 	}
 
-	if scheduledMaintenance.PlannedStart.Before(scheduledMaintenance.CreatedAt) || scheduledMaintenance.PlannedEnd.Before(scheduledMaintenance.CreatedAt) {
-		return errors.New("start and end date must be in the future")
+	now := time.Now()
+	startChanged := !scheduledMaintenance.PlannedStart.IsZero() && !scheduledMaintenance.PlannedStart.Equal(existingMaintenance.PlannedStart)
+	endChanged := !scheduledMaintenance.PlannedEnd.IsZero() && !scheduledMaintenance.PlannedEnd.Equal(existingMaintenance.PlannedEnd)
+
+	if startChanged && existingMaintenance.Started {
+		return nil, fmt.Errorf("%w: the start of maintenance which already started can't be changed", ErrInvalidScheduledMaintenance)
+	}
+
+	if (startChanged && scheduledMaintenance.PlannedStart.Before(now)) || (endChanged && scheduledMaintenance.PlannedEnd.Before(now)) {
+		return nil, fmt.Errorf("%w: start and end date must be in the future", ErrInvalidScheduledMaintenance)
 	}
 
 	if scheduledMaintenance.PlannedStart.IsZero() {
@@ -142,28 +187,233 @@ func PatchScheduledMaintenance(scheduledMaintenance *models.ScheduledMaintenance
 		scheduledMaintenance.PlannedEnd = existingMaintenance.PlannedEnd
 	}
 
+	if !scheduledMaintenance.PlannedEnd.After(scheduledMaintenance.PlannedStart) {
+		return nil, fmt.Errorf("%w: the end must be after the start", ErrInvalidScheduledMaintenance)
+	}
+
+	scheduledMaintenance.Started = existingMaintenance.Started
+	scheduledMaintenance.StartedAt = existingMaintenance.StartedAt
 	scheduledMaintenance.Completed = existingMaintenance.Completed
+	scheduledMaintenance.CompletedAt = existingMaintenance.CompletedAt
+	scheduledMaintenance.Cancelled = existingMaintenance.Cancelled
+	scheduledMaintenance.CancelledAt = existingMaintenance.CancelledAt
+	scheduledMaintenance.OriginalPlannedEnd = existingMaintenance.OriginalPlannedEnd
+	scheduledMaintenance.RecurringMaintenanceID = existingMaintenance.RecurringMaintenanceID
+	scheduledMaintenance.Occurrence = existingMaintenance.Occurrence
+
+	// Calendars only pick up changes to the event when the sequence goes up
+	scheduledMaintenance.Sequence = existingMaintenance.Sequence
+	if !scheduledMaintenance.PlannedStart.Equal(existingMaintenance.PlannedStart) || !scheduledMaintenance.PlannedEnd.Equal(existingMaintenance.PlannedEnd) {
+		scheduledMaintenance.Sequence++
+	}
 
 	scheduledMaintenance.Updates = existingMaintenance.Updates
 	scheduledMaintenance.CreatedAt = existingMaintenance.CreatedAt
 	scheduledMaintenance.Services = existingMaintenance.Services
+	scheduledMaintenance.Notifications = existingMaintenance.Notifications
 	scheduledMaintenance.LatestTweetID = existingMaintenance.LatestTweetID
 	scheduledMaintenance.OriginalTweetID = existingMaintenance.OriginalTweetID
 
+	// Only a new window can bring new conflicts, the ones it had were accepted when it was planned
+	var conflicts []models.MaintenanceConflict
+	if scheduledMaintenance.Sequence != existingMaintenance.Sequence && !scheduledMaintenance.Completed && !scheduledMaintenance.Cancelled {
+		conflicts, err = checkScheduledMaintenanceConflicts(scheduledMaintenance, force)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var update *models.StatusUpdate
+	if scheduledMaintenance.Sequence != existingMaintenance.Sequence && !scheduledMaintenance.Completed && !scheduledMaintenance.Cancelled {
+		update = appendScheduledMaintenanceUpdate(scheduledMaintenance, models.IncidentStatusUpdate, fmt.Sprintf("The maintenance has been rescheduled from %s until %s.",
+			scheduledMaintenance.PlannedStart.UTC().Format("2006/01/02 15:04 MST"), scheduledMaintenance.PlannedEnd.UTC().Format("2006/01/02 15:04 MST")))
+	}
+
 	if err := _dataStore.UpdateScheduledMaintenance(scheduledMaintenance); err != nil {
+		return nil, err
+	}
+
+	wakeUpMaintenanceScheduler()
+
+	if update != nil {
+		notify(&models.Event{
+			Type:                 models.EventMaintenanceUpdated,
+			ScheduledMaintenance: scheduledMaintenance,
+			Update:               update,
+		})
+	}
+
+	return conflicts, nil
+}
+
+// DeleteScheduledMaintenance removes the scheduled maintenance from the storage layer, the services it affected no longer are
+func DeleteScheduledMaintenance(id int, actor string) error {
+	maintenanceLock.Lock()
+	defer maintenanceLock.Unlock()
+
+	if err := _dataStore.DeleteScheduledMaintenance(id); err != nil {
 		return err
 	}
 
-	return nil
+	return deriveStatuses(statusChangeCause{ScheduledMaintenanceID: id, Actor: actor})
 }
 
-// DeleteScheduledMaintenance removes the scheduled maintenance from the storage layer
-func DeleteScheduledMaintenance(id int) error {
-	return _dataStore.DeleteScheduledMaintenance(id)
+// ErrInvalidScheduledMaintenanceAction is returned when the maintenance can't be extended, started or cancelled
+var ErrInvalidScheduledMaintenanceAction = errors.New("invalid maintenance action")
+
+// ExtendScheduledMaintenance pushes back the planned end of a maintenance in progress, posting an update with
+// the previous and new end. The maintenance is nil when it doesn't exist.
+func ExtendScheduledMaintenance(id int, plannedEnd time.Time, message string, actor string) (*models.ScheduledMaintenance, error) {
+	maintenanceLock.Lock()
+	defer maintenanceLock.Unlock()
+
+	scheduledMaintenance, err := _dataStore.GetScheduledMaintenanceByID(id)
+	if err != nil || scheduledMaintenance == nil {
+		return nil, err
+	}
+
+	if !isScheduledMaintenanceInProgress(scheduledMaintenance) {
+		return nil, fmt.Errorf("%w: only maintenance in progress can be extended, change the planned end of the others instead", ErrInvalidScheduledMaintenanceAction)
+	}
+
+	if !plannedEnd.After(scheduledMaintenance.PlannedEnd) || !plannedEnd.After(time.Now()) {
+		return nil, fmt.Errorf("%w: the new end must be after the planned end and in the future", ErrInvalidScheduledMaintenanceAction)
+	}
+
+	previousEnd := scheduledMaintenance.PlannedEnd
+	if scheduledMaintenance.OriginalPlannedEnd.IsZero() {
+		scheduledMaintenance.OriginalPlannedEnd = previousEnd
+	}
+
+	scheduledMaintenance.PlannedEnd = plannedEnd
+	scheduledMaintenance.Sequence++
+
+	if message == "" {
+		message = "The maintenance needs more time than planned."
+	}
+
+	update := appendScheduledMaintenanceUpdate(scheduledMaintenance, models.IncidentStatusUpdate, fmt.Sprintf("%s It was planned to end at %s and now ends at %s.",
+		message, previousEnd.UTC().Format("2006/01/02 15:04 MST"), plannedEnd.UTC().Format("2006/01/02 15:04 MST")))
+
+	if err := _dataStore.UpdateScheduledMaintenance(scheduledMaintenance); err != nil {
+		return nil, err
+	}
+
+	wakeUpMaintenanceScheduler()
+
+	notify(&models.Event{
+		Type:                 models.EventMaintenanceExtended,
+		ScheduledMaintenance: scheduledMaintenance,
+		Update:               update,
+	})
+
+	return scheduledMaintenance, nil
+}
+
+// StartScheduledMaintenanceEarly starts a maintenance before its planned start, which moves to now. The
+// maintenance is nil when it doesn't exist.
+func StartScheduledMaintenanceEarly(id int, message string, actor string) (*models.ScheduledMaintenance, error) {
+	maintenanceLock.Lock()
+	defer maintenanceLock.Unlock()
+
+	scheduledMaintenance, err := _dataStore.GetScheduledMaintenanceByID(id)
+	if err != nil || scheduledMaintenance == nil {
+		return nil, err
+	}
+
+	if scheduledMaintenance.Completed || scheduledMaintenance.Cancelled || scheduledMaintenance.Started {
+		return nil, fmt.Errorf("%w: the maintenance already started", ErrInvalidScheduledMaintenanceAction)
+	}
+
+	if now := time.Now(); scheduledMaintenance.PlannedStart.After(now) {
+		scheduledMaintenance.PlannedStart = now
+		scheduledMaintenance.Sequence++
+
+		if err := _dataStore.UpdateScheduledMaintenance(scheduledMaintenance); err != nil {
+			return nil, err
+		}
+	}
+
+	if message == "" {
+		message = "The scheduled maintenance has started earlier than planned."
+	}
+
+	if err := startScheduledMaintenance(scheduledMaintenance, message, actor); err != nil {
+		return nil, err
+	}
+
+	return _dataStore.GetScheduledMaintenanceByID(id)
+}
+
+// CancelScheduledMaintenance calls off a maintenance which isn't over, unlike deleting it the maintenance stays
+// on the status page marked as cancelled. The maintenance is nil when it doesn't exist.
+func CancelScheduledMaintenance(id int, message string, actor string) (*models.ScheduledMaintenance, error) {
+	maintenanceLock.Lock()
+	defer maintenanceLock.Unlock()
+
+	scheduledMaintenance, err := _dataStore.GetScheduledMaintenanceByID(id)
+	if err != nil || scheduledMaintenance == nil {
+		return nil, err
+	}
+
+	if scheduledMaintenance.Completed || scheduledMaintenance.Cancelled {
+		return nil, fmt.Errorf("%w: the maintenance is already over", ErrInvalidScheduledMaintenanceAction)
+	}
+
+	if message == "" {
+		message = "The scheduled maintenance has been cancelled."
+	}
+
+	update := appendScheduledMaintenanceUpdate(scheduledMaintenance, models.IncidentStatusResolved, message)
+
+	scheduledMaintenance.Cancelled = true
+	scheduledMaintenance.CancelledAt = update.Time
+	scheduledMaintenance.Sequence++
+
+	if err := _dataStore.UpdateScheduledMaintenance(scheduledMaintenance); err != nil {
+		return nil, err
+	}
+
+	// Maintenance which already started gives its services back
+	if err := deriveStatuses(statusChangeCause{ScheduledMaintenanceID: id, Actor: actor}); err != nil {
+		return nil, err
+	}
+
+	notify(&models.Event{
+		Type:                 models.EventMaintenanceCancelled,
+		ScheduledMaintenance: scheduledMaintenance,
+		Update:               update,
+	})
+
+	return scheduledMaintenance, nil
+}
+
+// appendScheduledMaintenanceUpdate adds an update to the maintenance the way the store would, it still has to be saved
+func appendScheduledMaintenanceUpdate(scheduledMaintenance *models.ScheduledMaintenance, status models.IncidentStatus, message string) *models.StatusUpdate {
+	update := &models.StatusUpdate{
+		ID:      len(scheduledMaintenance.Updates),
+		Time:    time.Now(),
+		Status:  status,
+		Message: message,
+	}
+
+	scheduledMaintenance.Updates = append(scheduledMaintenance.Updates, update)
+
+	return update
 }
 
-// CreateScheduledMaintenanceUpdate creates an update for a scheduled maintenance
-func CreateScheduledMaintenanceUpdate(incidentID int, update *models.StatusUpdate) (*models.ScheduledMaintenance, error) {
+// CreateScheduledMaintenanceUpdate creates an update for a scheduled maintenance, the actor is who posted it.
+// Only the scheduler starts the maintenance, or starting it early.
+func CreateScheduledMaintenanceUpdate(incidentID int, update *models.StatusUpdate, actor string) (*models.ScheduledMaintenance, error) {
+	maintenanceLock.Lock()
+	defer maintenanceLock.Unlock()
+
+	return createScheduledMaintenanceUpdate(incidentID, update, actor, false)
+}
+
+// createScheduledMaintenanceUpdate creates an update for a scheduled maintenance, starting it when asked to. The
+// maintenance lock has to be held, so the maintenance can't be cancelled or completed while the update is posted.
+func createScheduledMaintenanceUpdate(incidentID int, update *models.StatusUpdate, actor string, start bool) (*models.ScheduledMaintenance, error) {
 	if incidentID <= 0 {
 		return nil, errors.New("invalid incident id")
 	}
@@ -183,6 +433,27 @@ func CreateScheduledMaintenanceUpdate(incidentID int, update *models.StatusUpdat
 
 	update.Status = status
 
+	if err := validateServiceUpdates(update.Services, true); err != nil {
+		return nil, err
+	}
+
+	existingMaintenance, err := _dataStore.GetScheduledMaintenanceByID(incidentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingMaintenance == nil {
+		return nil, errors.New("invalid scheduled maintenance")
+	}
+
+	if existingMaintenance.Completed || existingMaintenance.Cancelled {
+		return nil, fmt.Errorf("%w: the maintenance is already over", ErrInvalidScheduledMaintenanceAction)
+	}
+
+	if status == models.IncidentStatusResolved && !existingMaintenance.Started {
+		return nil, fmt.Errorf("%w: maintenance which didn't start can't be completed, cancel it instead", ErrInvalidScheduledMaintenanceAction)
+	}
+
 	if err := _dataStore.CreateScheduledMaintenanceUpdate(incidentID, update); err != nil {
 		return nil, err
 	}
@@ -192,56 +463,48 @@ func CreateScheduledMaintenanceUpdate(incidentID int, update *models.StatusUpdat
 		return nil, err
 	}
 
+	if start && !scheduledMaintenance.Started {
+		scheduledMaintenance.Started = true
+		scheduledMaintenance.StartedAt = update.Time
+	}
+
 	if status != models.IncidentStatusResolved {
-		for _, s := range update.Services {
-			if err := updateServiceToStatus(s.Name, s.Status); err != nil {
-				return nil, err
-			}
-
-			// Update the status on the incident.  Makes it easier for those utilizing api
-			for i, si := range scheduledMaintenance.Services {
-				if s.Name == si.Name {
-					scheduledMaintenance.Services[i].Status = s.Status
-				}
-			}
-
-			for _, regionCode := range s.Regions {
-				if err := updateRegionToStatus(regionCode, s.Name, s.Status); err != nil {
-					return nil, err
-				}
-			}
-		}
+		scheduledMaintenance.Services = mergeUpdateServices(scheduledMaintenance.Services, update.Services)
 	} else {
-		for i, s := range scheduledMaintenance.Services {
+		for i := range scheduledMaintenance.Services {
 			scheduledMaintenance.Services[i].Status = models.ServiceStatusNominal
-			if err := updateServiceToStatus(s.Name, models.ServiceStatusNominal); err != nil {
-				return nil, err
-			}
-
-			for _, regionCode := range s.Regions {
-				if err := updateRegionToStatus(regionCode, s.Name, models.ServiceStatusNominal); err != nil {
-					return nil, err
-				}
-			}
 		}
 
 		scheduledMaintenance.Completed = true
+		scheduledMaintenance.CompletedAt = update.Time
+
+		// Completing it early cancels the rest of the window in the calendars
+		if scheduledMaintenance.CompletedAt.Before(scheduledMaintenance.PlannedEnd) {
+			scheduledMaintenance.Sequence++
+		}
 	}
 
 	if err := _dataStore.UpdateScheduledMaintenance(scheduledMaintenance); err != nil {
 		return nil, err
 	}
 
-	if config.Config.Twitter.Enabled {
-		tweetID, err := SendScheduledMaintenanceUpdateTwitter(scheduledMaintenance, update)
-		if err == nil && tweetID != 0 {
-			scheduledMaintenance.LatestTweetID = tweetID
-			if err := _dataStore.UpdateScheduledMaintenance(scheduledMaintenance); err != nil {
-				return nil, err
-			}
-		}
+	if err := deriveStatuses(statusChangeCause{ScheduledMaintenanceID: incidentID, Actor: actor}); err != nil {
+		return nil, err
 	}
 
+	eventType := models.EventMaintenanceUpdated
+	if status == models.IncidentStatusResolved {
+		eventType = models.EventMaintenanceCompleted
+	} else if start {
+		eventType = models.EventMaintenanceStarted
+	}
+
+	notify(&models.Event{
+		Type:                 eventType,
+		ScheduledMaintenance: scheduledMaintenance,
+		Update:               update,
+	})
+
 	return scheduledMaintenance, nil
 }
 
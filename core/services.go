@@ -25,7 +25,33 @@ func GetServiceByName(name string) (*models.Service, error) {
 
 // CreateService creates the service in the storage layer
 func CreateService(service *models.Service) error {
-	return _dataStore.CreateService(service)
+	if err := _dataStore.CreateService(service); err != nil {
+		return err
+	}
+
+	if err := recordInitialServiceStatus(service); err != nil {
+		return err
+	}
+
+	invalidateDailyStatusHistory()
+
+	return nil
+}
+
+// recordInitialServiceStatus starts the status history of the service with its current status, so the days from
+// then on have data even when its status never changes
+func recordInitialServiceStatus(service *models.Service) error {
+	status := service.Status
+	if status == "" {
+		status = models.ServiceStatusUnknown
+	}
+
+	return _dataStore.CreateStatusChange(&models.StatusChange{
+		ServiceID:      service.ID,
+		ServiceName:    service.Name,
+		PreviousStatus: models.ServiceStatusUnknown,
+		Status:         status,
+	})
 }
 
 // GetService Get service by id
@@ -33,8 +59,9 @@ func GetServiceByID(id int) (*models.Service, error) {
 	return _dataStore.GetServiceByID(id)
 }
 
-// UpdateService updates the service
-func UpdateService(service *models.Service) error {
+// UpdateService updates the service. A status other than the current one is kept as the manual override of
+// the service, which counts like an open incident until it's set back to nominal.
+func UpdateService(service *models.Service, actor string) error {
 	existingService, err := _dataStore.GetServiceByID(service.ID)
 	if err != nil {
 		return err
@@ -44,6 +71,21 @@ func UpdateService(service *models.Service) error {
 		return errors.New("invalid service")
 	}
 
+	service.StatusOverride = existingService.StatusOverride
+	if service.Status != "" && service.Status != existingService.Status {
+		status, ok := models.ServiceStatuses[service.Status.ToLower()]
+		if !ok {
+			return errors.New("invalid service status")
+		}
+
+		service.StatusOverride = status
+		if status == models.ServiceStatusNominal || status == models.ServiceStatusUnknown {
+			service.StatusOverride = ""
+		}
+	}
+
+	// The status is derived from the override, the open incidents and the maintenance in progress
+	service.Status = existingService.Status
 	service.Regions = nil // these are added on fetch so no update should be touching
 	service.UpdatedAt = time.Now()
 
@@ -51,9 +93,47 @@ func UpdateService(service *models.Service) error {
 		return err
 	}
 
+	invalidateDailyStatusHistory()
+
+	if err := deriveStatuses(statusChangeCause{Actor: actor}); err != nil {
+		return err
+	}
+
+	updated, err := _dataStore.GetServiceByID(service.ID)
+	if err != nil {
+		return err
+	}
+
+	service.Status = updated.Status
+
 	return nil
 }
 
+// GetServiceStatusHistory gets a page of the status changes of the service and its regions, newest first.
+// Both the changes and error will be nil if the service doesn't exist
+func GetServiceStatusHistory(serviceID int, pagination models.Pagination) ([]*models.StatusChange, error) {
+	service, err := _dataStore.GetServiceByID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if service == nil {
+		return nil, nil
+	}
+
+	changes, err := _dataStore.GetStatusChangesByServiceID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*models.StatusChange, 0, pagination.Limit)
+	for i := len(changes) - 1 - pagination.Offset; i >= 0 && len(history) < pagination.Limit; i-- {
+		history = append(history, changes[i])
+	}
+
+	return history, nil
+}
+
 // MostCriticalServiceStatus returns the most critical service number of the services provided
 func MostCriticalServiceStatus(services []*models.Service, regions []*models.Region) int {
 	mostCritical := 0
@@ -102,7 +182,47 @@ func createServicesFromConfig() error {
 	return nil
 }
 
-func updateServiceToStatus(serviceName string, status models.ServiceAndRegionStatus) error {
+// validateServiceUpdates makes sure the services and their regions exist, and optionally that the statuses are valid
+func validateServiceUpdates(services []models.ServiceUpdate, checkStatus bool) error {
+	for _, s := range services {
+		service, err := GetServiceByName(s.Name)
+		if err != nil {
+			return err
+		}
+
+		if service == nil {
+			return errors.New("unknown service")
+		}
+
+		if checkStatus {
+			if _, ok := models.ServiceStatuses[s.Status.ToLower()]; !ok {
+				return errors.New("invalid service status")
+			}
+		}
+
+		for _, regionCode := range s.Regions {
+			region, err := GetRegionByCodeAndServiceName(regionCode, s.Name)
+			if err != nil {
+				return err
+			}
+
+			if region == nil {
+				return errors.New("unknown region")
+			}
+		}
+	}
+
+	return nil
+}
+
+// statusChangeCause is what made the status of a service or region change, it gets recorded in the status history
+type statusChangeCause struct {
+	IncidentID             int
+	ScheduledMaintenanceID int
+	Actor                  string
+}
+
+func updateServiceToStatus(serviceName string, status models.ServiceAndRegionStatus, cause statusChangeCause) error {
 	service, err := GetServiceByName(serviceName)
 
 	if err != nil {
@@ -119,7 +239,37 @@ func updateServiceToStatus(serviceName string, status models.ServiceAndRegionSta
 		return errors.New("invalid service status")
 	}
 
+	previousStatus := service.Status
 	service.Status = val
 
-	return _dataStore.UpdateService(service)
+	if err := _dataStore.UpdateService(service); err != nil {
+		return err
+	}
+
+	if previousStatus != val {
+		if err := _dataStore.CreateStatusChange(&models.StatusChange{
+			ServiceID:              service.ID,
+			ServiceName:            service.Name,
+			PreviousStatus:         previousStatus,
+			Status:                 val,
+			IncidentID:             cause.IncidentID,
+			ScheduledMaintenanceID: cause.ScheduledMaintenanceID,
+			Actor:                  cause.Actor,
+		}); err != nil {
+			return err
+		}
+
+		invalidateDailyStatusHistory()
+
+		notify(&models.Event{
+			Type: models.EventServiceStatusChanged,
+			StatusChange: &models.ServiceStatusChange{
+				ServiceName:    service.Name,
+				PreviousStatus: previousStatus,
+				Status:         val,
+			},
+		})
+	}
+
+	return nil
 }
@@ -0,0 +1,227 @@
+package core
+
+import (
+	"sync"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// statusLock keeps two changes from deriving the statuses at once, whichever runs last sees both changes
+var statusLock sync.Mutex
+
+// deriveStatuses sets every service and region to the worst status the open incidents, the scheduled
+// maintenance in progress and its manual override give it, or nominal when nothing affects it. Only
+// services have a manual override, and whatever is still unknown stays so until something affects it.
+// It has to run after every change to any of them, only the statuses which differ are stored.
+func deriveStatuses(cause statusChangeCause) error {
+	statusLock.Lock()
+	defer statusLock.Unlock()
+
+	affecting, err := affectingServiceUpdates()
+	if err != nil {
+		return err
+	}
+
+	services, err := _dataStore.GetServices()
+	if err != nil {
+		return err
+	}
+
+	for _, service := range services {
+		statuses := make([]models.ServiceAndRegionStatus, 0)
+		for _, s := range affecting {
+			if s.Name == service.Name {
+				statuses = append(statuses, s.Status)
+			}
+		}
+
+		if service.StatusOverride != "" {
+			statuses = append(statuses, service.StatusOverride)
+		}
+
+		if len(statuses) == 0 && service.Status == models.ServiceStatusUnknown {
+			continue
+		}
+
+		if status := models.WorstServiceStatus(statuses...); status != service.Status {
+			if err := updateServiceToStatus(service.Name, status, cause); err != nil {
+				return err
+			}
+		}
+	}
+
+	regions, err := _dataStore.GetRegions()
+	if err != nil {
+		return err
+	}
+
+	for _, region := range regions {
+		statuses := make([]models.ServiceAndRegionStatus, 0)
+		for _, s := range affecting {
+			if s.Name == region.ServiceName && stringInSlice(region.RegionCode, s.Regions) {
+				statuses = append(statuses, s.Status)
+			}
+		}
+
+		if len(statuses) == 0 && region.Status == models.ServiceStatusUnknown {
+			continue
+		}
+
+		if status := models.WorstServiceStatus(statuses...); status != region.Status {
+			if err := updateRegionToStatus(region.RegionCode, region.ServiceName, status, cause); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// affectingServiceUpdates gives the services of the open incidents and of the scheduled maintenance in progress
+func affectingServiceUpdates() ([]models.ServiceUpdate, error) {
+	affecting := make([]models.ServiceUpdate, 0)
+
+	incidents, err := GetOpenIncidents()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, incident := range incidents {
+		for _, s := range incident.Services {
+			// Maintenance used to be an incident, its services are under maintenance whatever status they were given
+			if incident.IsMaintenance {
+				s.Status = models.ServiceStatusScheduledMaintenance
+			}
+
+			affecting = append(affecting, s)
+		}
+	}
+
+	scheduledMaintenances, err := _dataStore.GetScheduledMaintenance(false)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range scheduledMaintenances {
+		if isScheduledMaintenanceInProgress(m) {
+			affecting = append(affecting, m.Services...)
+		}
+	}
+
+	return affecting, nil
+}
+
+// isScheduledMaintenanceInProgress tells whether the maintenance was started, by the scheduler or early, and isn't over
+func isScheduledMaintenanceInProgress(m *models.ScheduledMaintenance) bool {
+	return m.Started && !m.Completed && !m.Cancelled
+}
+
+// mergeUpdateServices records the services of an update on the incident or maintenance, the ones already
+// there take the status of the update and any regions they didn't list yet
+func mergeUpdateServices(affected []models.ServiceUpdate, services []models.ServiceUpdate) []models.ServiceUpdate {
+	for _, s := range services {
+		found := false
+
+		for i := range affected {
+			if affected[i].Name != s.Name {
+				continue
+			}
+
+			found = true
+			affected[i].Status = s.Status
+
+			for _, regionCode := range s.Regions {
+				if !stringInSlice(regionCode, affected[i].Regions) {
+					affected[i].Regions = append(affected[i].Regions, regionCode)
+				}
+			}
+		}
+
+		if !found {
+			affected = append(affected, s)
+		}
+	}
+
+	return affected
+}
+
+// removedUpdateServices gives what an edit took out of the services of an update: the services it no longer
+// lists, and the regions it no longer lists of the services it still lists by region
+func removedUpdateServices(before []models.ServiceUpdate, after []models.ServiceUpdate) []models.ServiceUpdate {
+	removed := make([]models.ServiceUpdate, 0)
+
+	for _, b := range before {
+		var a *models.ServiceUpdate
+		for i := range after {
+			if after[i].Name == b.Name {
+				a = &after[i]
+				break
+			}
+		}
+
+		if a == nil {
+			removed = append(removed, models.ServiceUpdate{Name: b.Name})
+			continue
+		}
+
+		// Listed without regions it now affects all of them
+		if len(a.Regions) == 0 {
+			continue
+		}
+
+		regions := make([]string, 0)
+		for _, regionCode := range b.Regions {
+			if !stringInSlice(regionCode, a.Regions) {
+				regions = append(regions, regionCode)
+			}
+		}
+
+		if len(regions) > 0 {
+			removed = append(removed, models.ServiceUpdate{Name: b.Name, Regions: regions})
+		}
+	}
+
+	return removed
+}
+
+// removeServices takes the services, or only some of their regions, off the incident or maintenance. A service
+// left without any of the regions it listed is taken off, as having no regions would mean all of them.
+func removeServices(affected []models.ServiceUpdate, removed []models.ServiceUpdate) []models.ServiceUpdate {
+	remaining := make([]models.ServiceUpdate, 0, len(affected))
+
+	for _, s := range affected {
+		keep := true
+
+		for _, r := range removed {
+			// Taking regions off a service affected as a whole leaves it affected as a whole
+			if r.Name != s.Name || (len(r.Regions) > 0 && len(s.Regions) == 0) {
+				continue
+			}
+
+			if len(r.Regions) == 0 {
+				keep = false
+				break
+			}
+
+			regions := make([]string, 0, len(s.Regions))
+			for _, regionCode := range s.Regions {
+				if !stringInSlice(regionCode, r.Regions) {
+					regions = append(regions, regionCode)
+				}
+			}
+
+			if len(regions) == 0 {
+				keep = false
+				break
+			}
+
+			s.Regions = regions
+		}
+
+		if keep {
+			remaining = append(remaining, s)
+		}
+	}
+
+	return remaining
+}
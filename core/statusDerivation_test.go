@@ -0,0 +1,167 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+func TestDeriveStatusesTakesTheWorstOfWhatAffectsAService(t *testing.T) {
+	service := newTestService(t, "derive-worst", "eu", "us")
+
+	expect := func(step string, expected map[string]models.ServiceAndRegionStatus) {
+		t.Helper()
+
+		for regionCode, status := range expected {
+			if actual := serviceStatus(t, service.Name, regionCode); actual != status {
+				t.Errorf("%s: expected %q to be %s, got %s", step, regionCode, status, actual)
+			}
+		}
+	}
+
+	// Maintenance posted as an incident, the way it was before scheduled maintenance existed
+	newTestIncident(t, &models.Incident{
+		Title:       "Database upgrade",
+		Status:      models.IncidentStatusScheduledMaintenance,
+		Maintenance: models.IncidentMaintenance{Start: time.Now().Add(-time.Minute), End: time.Now().Add(time.Hour)},
+		Services:    []models.ServiceUpdate{{Name: service.Name, Regions: []string{"eu"}}},
+	})
+
+	expect("maintenance of eu", map[string]models.ServiceAndRegionStatus{
+		"":   models.ServiceStatusScheduledMaintenance,
+		"eu": models.ServiceStatusScheduledMaintenance,
+		"us": models.ServiceStatusNominal,
+	})
+
+	degraded := newTestIncident(t, &models.Incident{
+		Title:    "Slow responses",
+		Status:   models.IncidentStatusInvestigating,
+		Services: []models.ServiceUpdate{{Name: service.Name, Status: models.ServiceStatusDegraded, Regions: []string{"us"}}},
+	})
+
+	newTestIncident(t, &models.Incident{
+		Title:    "Errors in eu",
+		Status:   models.IncidentStatusInvestigating,
+		Services: []models.ServiceUpdate{{Name: service.Name, Status: models.ServiceStatusPartialOutage, Regions: []string{"eu"}}},
+	})
+
+	expect("incidents on top of the maintenance", map[string]models.ServiceAndRegionStatus{
+		"":   models.ServiceStatusPartialOutage,
+		"eu": models.ServiceStatusPartialOutage,
+		"us": models.ServiceStatusDegraded,
+	})
+
+	service.Status = models.ServiceStatusOutage
+	if err := UpdateService(service, "test"); err != nil {
+		t.Fatalf("unable to override the status: %v", err)
+	}
+
+	// The override is of the service, its regions keep what affects them
+	expect("override", map[string]models.ServiceAndRegionStatus{
+		"":   models.ServiceStatusOutage,
+		"eu": models.ServiceStatusPartialOutage,
+		"us": models.ServiceStatusDegraded,
+	})
+
+	service.Status = models.ServiceStatusNominal
+	if err := UpdateService(service, "test"); err != nil {
+		t.Fatalf("unable to clear the override: %v", err)
+	}
+
+	if _, err := CreateIncidentUpdate(degraded.ID, &models.StatusUpdate{
+		Status:  models.IncidentStatusResolved,
+		Message: "Responses are fast again",
+	}, "test"); err != nil {
+		t.Fatalf("unable to resolve the incident: %v", err)
+	}
+
+	expect("resolved one of the incidents", map[string]models.ServiceAndRegionStatus{
+		"":   models.ServiceStatusPartialOutage,
+		"eu": models.ServiceStatusPartialOutage,
+		"us": models.ServiceStatusNominal,
+	})
+}
+
+func TestDeriveStatusesKeepsUnknownUntilSomethingAffectsIt(t *testing.T) {
+	service := &models.Service{
+		Name:    "derive-unknown",
+		Status:  models.ServiceStatusUnknown,
+		Enabled: true,
+		Tags:    make([]string, 0),
+	}
+
+	if err := CreateService(service); err != nil {
+		t.Fatalf("unable to create the service: %v", err)
+	}
+
+	for _, regionCode := range []string{"eu", "us"} {
+		if err := CreateRegion(&models.Region{
+			Name:        regionCode,
+			RegionCode:  regionCode,
+			ServiceID:   service.ID,
+			ServiceName: service.Name,
+			Status:      models.ServiceStatusUnknown,
+			Enabled:     true,
+			Tags:        make([]string, 0),
+		}); err != nil {
+			t.Fatalf("unable to create the region %s: %v", regionCode, err)
+		}
+	}
+
+	if err := deriveStatuses(statusChangeCause{Actor: "test"}); err != nil {
+		t.Fatalf("unable to derive the statuses: %v", err)
+	}
+
+	for _, regionCode := range []string{"", "eu", "us"} {
+		if status := serviceStatus(t, service.Name, regionCode); status != models.ServiceStatusUnknown {
+			t.Errorf("expected %q to stay unknown while nothing affects it, got %s", regionCode, status)
+		}
+	}
+
+	newTestIncident(t, &models.Incident{
+		Title:    "Errors in eu",
+		Status:   models.IncidentStatusInvestigating,
+		Services: []models.ServiceUpdate{{Name: service.Name, Status: models.ServiceStatusOutage, Regions: []string{"eu"}}},
+	})
+
+	expected := map[string]models.ServiceAndRegionStatus{
+		"":   models.ServiceStatusOutage,
+		"eu": models.ServiceStatusOutage,
+		"us": models.ServiceStatusUnknown,
+	}
+
+	for regionCode, status := range expected {
+		if actual := serviceStatus(t, service.Name, regionCode); actual != status {
+			t.Errorf("expected %q to be %s, got %s", regionCode, status, actual)
+		}
+	}
+}
+
+func TestGetOpenIncidentsLeavesOutResolvedAndDeletedIncidents(t *testing.T) {
+	open := newTestIncident(t, &models.Incident{Title: "Open", Status: models.IncidentStatusInvestigating})
+	resolved := newTestIncident(t, &models.Incident{Title: "Resolved", Status: models.IncidentStatusInvestigating})
+	deleted := newTestIncident(t, &models.Incident{Title: "Deleted", Status: models.IncidentStatusInvestigating})
+
+	if _, err := CreateIncidentUpdate(resolved.ID, &models.StatusUpdate{Status: models.IncidentStatusResolved, Message: "Fixed"}, "test"); err != nil {
+		t.Fatalf("unable to resolve the incident: %v", err)
+	}
+
+	if err := DeleteIncident(deleted.ID, "test"); err != nil {
+		t.Fatalf("unable to delete the incident: %v", err)
+	}
+
+	incidents, err := GetOpenIncidents()
+	if err != nil {
+		t.Fatalf("unable to get the open incidents: %v", err)
+	}
+
+	found := make(map[int]bool)
+	for _, incident := range incidents {
+		found[incident.ID] = true
+	}
+
+	if !found[open.ID] || found[resolved.ID] || found[deleted.ID] {
+		t.Errorf("expected only the open incident %d, got %v", open.ID, found)
+	}
+}
@@ -0,0 +1,188 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// ErrInvalidStatusUpdateEdit is returned when the edit of a status update isn't allowed or isn't valid
+var ErrInvalidStatusUpdateEdit = errors.New("invalid edit")
+
+// EditIncidentUpdate changes the message, status or services of a published incident update, keeping
+// what it said before as a revision. The update is nil when the incident or update doesn't exist.
+func EditIncidentUpdate(incidentID int, updateID int, edit *models.StatusUpdate, actor string) (*models.StatusUpdate, error) {
+	incident, err := _dataStore.GetIncidentByID(incidentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if incident == nil {
+		return nil, nil
+	}
+
+	update, latest := findStatusUpdate(incident.Updates, updateID)
+	if update == nil {
+		return nil, nil
+	}
+
+	changed, err := reviseStatusUpdate(update, edit, actor)
+	if err != nil || !changed {
+		return update, err
+	}
+
+	if err := _dataStore.UpdateIncidentUpdate(incidentID, update); err != nil {
+		return nil, err
+	}
+
+	// Only the latest update decides the current status, editing an older one just fixes the record
+	if !latest {
+		return update, nil
+	}
+
+	incident, err = _dataStore.GetIncidentByID(incidentID)
+	if err != nil {
+		return nil, err
+	}
+
+	incident.Status = update.Status
+
+	if update.Status != models.IncidentStatusResolved {
+		incident.Services = editedUpdateServices(incident.Services, update)
+	}
+
+	if err := _dataStore.UpdateIncident(incident); err != nil {
+		return nil, err
+	}
+
+	if err := deriveStatuses(statusChangeCause{IncidentID: incidentID, Actor: actor}); err != nil {
+		return nil, err
+	}
+
+	return update, nil
+}
+
+// EditScheduledMaintenanceUpdate changes the message, status or services of a published scheduled maintenance
+// update, keeping what it said before as a revision. The update is nil when the maintenance or update doesn't exist.
+func EditScheduledMaintenanceUpdate(maintenanceID int, updateID int, edit *models.StatusUpdate, actor string) (*models.StatusUpdate, error) {
+	maintenanceLock.Lock()
+	defer maintenanceLock.Unlock()
+
+	scheduledMaintenance, err := _dataStore.GetScheduledMaintenanceByID(maintenanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if scheduledMaintenance == nil {
+		return nil, nil
+	}
+
+	update, latest := findStatusUpdate(scheduledMaintenance.Updates, updateID)
+	if update == nil {
+		return nil, nil
+	}
+
+	changed, err := reviseStatusUpdate(update, edit, actor)
+	if err != nil || !changed {
+		return update, err
+	}
+
+	if err := _dataStore.UpdateScheduledMaintenanceUpdate(maintenanceID, update); err != nil {
+		return nil, err
+	}
+
+	if !latest || update.Status == models.IncidentStatusResolved {
+		return update, nil
+	}
+
+	scheduledMaintenance, err = _dataStore.GetScheduledMaintenanceByID(maintenanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduledMaintenance.Services = editedUpdateServices(scheduledMaintenance.Services, update)
+
+	if err := _dataStore.UpdateScheduledMaintenance(scheduledMaintenance); err != nil {
+		return nil, err
+	}
+
+	if err := deriveStatuses(statusChangeCause{ScheduledMaintenanceID: maintenanceID, Actor: actor}); err != nil {
+		return nil, err
+	}
+
+	return update, nil
+}
+
+// editedUpdateServices gives the services of the incident or maintenance once the latest update was edited. What
+// the update listed before the edit and no longer does is taken off, so services listed by mistake stop being affected.
+func editedUpdateServices(affected []models.ServiceUpdate, update *models.StatusUpdate) []models.ServiceUpdate {
+	previous := update.Revisions[len(update.Revisions)-1].Services
+
+	return removeServices(mergeUpdateServices(affected, update.Services), removedUpdateServices(previous, update.Services))
+}
+
+// findStatusUpdate finds the update by its id, and tells whether it's the latest one
+func findStatusUpdate(updates []*models.StatusUpdate, updateID int) (*models.StatusUpdate, bool) {
+	for i, update := range updates {
+		if update.ID == updateID {
+			return update, i == len(updates)-1
+		}
+	}
+
+	return nil, false
+}
+
+// reviseStatusUpdate applies the edit to the update and records what it said before, the fields the edit
+// leaves empty stay as they were. It tells whether anything changed.
+func reviseStatusUpdate(update *models.StatusUpdate, edit *models.StatusUpdate, actor string) (bool, error) {
+	status := update.Status
+	if edit.Status != "" {
+		s, ok := models.IncidentStatuses[strings.ToLower(edit.Status.String())]
+		if !ok {
+			return false, fmt.Errorf("%w: invalid status value", ErrInvalidStatusUpdateEdit)
+		}
+
+		status = s
+	}
+
+	// Resolving has side effects an edit can't undo, like the services going back to nominal
+	if (status == models.IncidentStatusResolved) != (update.Status == models.IncidentStatusResolved) {
+		return false, fmt.Errorf("%w: an update can't be edited to or from resolved, post a new update instead", ErrInvalidStatusUpdateEdit)
+	}
+
+	message := update.Message
+	if edit.Message != "" {
+		message = edit.Message
+	}
+
+	services := update.Services
+	if edit.Services != nil {
+		if err := validateServiceUpdates(edit.Services, true); err != nil {
+			return false, fmt.Errorf("%w: %v", ErrInvalidStatusUpdateEdit, err)
+		}
+
+		services = edit.Services
+	}
+
+	if status == update.Status && message == update.Message && reflect.DeepEqual(services, update.Services) {
+		return false, nil
+	}
+
+	update.Revisions = append(update.Revisions, models.StatusUpdateRevision{
+		EditedAt: time.Now(),
+		EditedBy: actor,
+		Status:   update.Status,
+		Message:  update.Message,
+		Services: update.Services,
+	})
+
+	update.Status = status
+	update.Message = message
+	update.Services = services
+
+	return true, nil
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+func TestEditIncidentUpdateTakesOffWhatWasListedByMistake(t *testing.T) {
+	newTestService(t, "edit-api", "eu", "us")
+	newTestService(t, "edit-web")
+
+	incident := newTestIncident(t, &models.Incident{
+		Title:    "API errors",
+		Status:   models.IncidentStatusInvestigating,
+		Services: []models.ServiceUpdate{{Name: "edit-api", Status: models.ServiceStatusDegraded, Regions: []string{"eu"}}},
+	})
+
+	incident, err := CreateIncidentUpdate(incident.ID, &models.StatusUpdate{
+		Status:  models.IncidentStatusIdentified,
+		Message: "The database is overloaded",
+		Services: []models.ServiceUpdate{
+			{Name: "edit-api", Status: models.ServiceStatusDegraded, Regions: []string{"eu", "us"}},
+			{Name: "edit-web", Status: models.ServiceStatusOutage},
+		},
+	}, "test")
+	if err != nil {
+		t.Fatalf("unable to post the update: %v", err)
+	}
+
+	if status := serviceStatus(t, "edit-api", "us"); status != models.ServiceStatusDegraded {
+		t.Fatalf("expected the us region to be degraded by the update, got %s", status)
+	}
+
+	if status := serviceStatus(t, "edit-web", ""); status != models.ServiceStatusOutage {
+		t.Fatalf("expected edit-web to be out by the update, got %s", status)
+	}
+
+	update := incident.Updates[len(incident.Updates)-1]
+
+	if _, err := EditIncidentUpdate(incident.ID, update.ID, &models.StatusUpdate{
+		Services: []models.ServiceUpdate{{Name: "edit-api", Status: models.ServiceStatusDegraded, Regions: []string{"eu"}}},
+	}, "test"); err != nil {
+		t.Fatalf("unable to edit the update: %v", err)
+	}
+
+	expected := []struct {
+		service string
+		region  string
+		status  models.ServiceAndRegionStatus
+	}{
+		{service: "edit-api", status: models.ServiceStatusDegraded},
+		{service: "edit-api", region: "eu", status: models.ServiceStatusDegraded},
+		{service: "edit-api", region: "us", status: models.ServiceStatusNominal},
+		{service: "edit-web", status: models.ServiceStatusNominal},
+	}
+
+	for _, e := range expected {
+		if status := serviceStatus(t, e.service, e.region); status != e.status {
+			t.Errorf("expected %s %s to be %s after the edit, got %s", e.service, e.region, e.status, status)
+		}
+	}
+
+	edited, err := GetIncidentByID(incident.ID)
+	if err != nil {
+		t.Fatalf("unable to get the incident: %v", err)
+	}
+
+	if len(edited.Services) != 1 || edited.Services[0].Name != "edit-api" || len(edited.Services[0].Regions) != 1 || edited.Services[0].Regions[0] != "eu" {
+		t.Errorf("expected only the eu region of edit-api to stay affected, got %+v", edited.Services)
+	}
+}
+
+func TestRemoveServices(t *testing.T) {
+	affected := []models.ServiceUpdate{
+		{Name: "api", Regions: []string{"eu", "us"}},
+		{Name: "web"},
+		{Name: "db", Regions: []string{"eu"}},
+	}
+
+	removed := removedUpdateServices(
+		[]models.ServiceUpdate{{Name: "api", Regions: []string{"eu", "us"}}, {Name: "web", Regions: []string{"eu"}}, {Name: "db", Regions: []string{"eu"}}},
+		[]models.ServiceUpdate{{Name: "api", Regions: []string{"eu"}}},
+	)
+
+	remaining := removeServices(affected, removed)
+
+	if len(remaining) != 1 || remaining[0].Name != "api" || len(remaining[0].Regions) != 1 || remaining[0].Regions[0] != "eu" {
+		t.Errorf("expected only the eu region of api to remain, got %+v", remaining)
+	}
+
+	// Taking a region off a service affected as a whole leaves it as it was
+	remaining = removeServices([]models.ServiceUpdate{{Name: "web"}}, []models.ServiceUpdate{{Name: "web", Regions: []string{"eu"}}})
+	if len(remaining) != 1 || len(remaining[0].Regions) != 0 {
+		t.Errorf("expected web to stay affected as a whole, got %+v", remaining)
+	}
+}
@@ -0,0 +1,373 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+const (
+	subscriberTokenActionConfirm     = "confirm"
+	subscriberTokenActionUnsubscribe = "unsubscribe"
+)
+
+// subscriberTokenTTL is how long the tokens of each action stay valid. The unsubscribe links are in every
+// notification email, so they last long enough for old emails to still work.
+var subscriberTokenTTL = map[string]time.Duration{
+	subscriberTokenActionConfirm:     48 * time.Hour,
+	subscriberTokenActionUnsubscribe: 90 * 24 * time.Hour,
+}
+
+var (
+	// ErrInvalidSubscriberToken is returned when a confirm or unsubscribe token can not be verified
+	ErrInvalidSubscriberToken = errors.New("invalid or expired token")
+
+	// ErrTooManySubscriptionRequests is returned when an address or a client asked for too many confirmation emails
+	ErrTooManySubscriptionRequests = errors.New("too many subscription requests, try again later")
+)
+
+// Anyone can subscribe, so the confirmation emails sent are limited per client and per address
+var (
+	subscribeClientLimiter  = newRateLimiter(10, time.Hour)
+	subscribeAddressLimiter = newRateLimiter(3, time.Hour)
+)
+
+// GetSubscribers gets all of the subscribers from the storage layer
+func GetSubscribers() ([]*models.Subscriber, error) {
+	return _dataStore.GetSubscribers()
+}
+
+// DeleteSubscriber removes the subscriber from the storage layer
+func DeleteSubscriber(id int) error {
+	return _dataStore.DeleteSubscriber(id)
+}
+
+// Subscribe registers the email address for notifications and queues the double opt-in email, the client is
+// the address the request came from. The subscription only becomes active, or changes its selection, once the
+// email is confirmed.
+func Subscribe(email string, services []models.SubscribedService, client string) (*models.Subscriber, error) {
+	if !config.Config.Subscriptions.Enabled {
+		return nil, errors.New("subscriptions are not enabled")
+	}
+
+	if !subscribeClientLimiter.allow(client) {
+		return nil, ErrTooManySubscriptionRequests
+	}
+
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil {
+		return nil, errors.New("invalid email address")
+	}
+
+	email = strings.ToLower(address.Address)
+
+	if !subscribeAddressLimiter.allow(email) {
+		return nil, ErrTooManySubscriptionRequests
+	}
+
+	if err := validateSubscribedServices(services); err != nil {
+		return nil, err
+	}
+
+	// An empty selection means everything, make sure it survives being stored
+	if services == nil {
+		services = make([]models.SubscribedService, 0)
+	}
+
+	subscriber, err := _dataStore.GetSubscriberByEmail(email)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscriber == nil {
+		subscriber = &models.Subscriber{
+			Email:           email,
+			Services:        make([]models.SubscribedService, 0),
+			PendingServices: services,
+		}
+
+		if err := _dataStore.CreateSubscriber(subscriber); err != nil {
+			return nil, err
+		}
+	} else {
+		subscriber.PendingServices = services
+
+		if err := _dataStore.UpdateSubscriber(subscriber); err != nil {
+			return nil, err
+		}
+	}
+
+	body, err := renderMailTemplate("confirm.tmpl", map[string]interface{}{
+		"owner":      config.Config.Website.Title,
+		"services":   services,
+		"confirmURL": subscriberActionURL(subscriber, subscriberTokenActionConfirm),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := queueMail(subscriber.Email, fmt.Sprintf("Confirm your %s status subscription", config.Config.Website.Title), body); err != nil {
+		return nil, err
+	}
+
+	return subscriber, nil
+}
+
+// ConfirmSubscription verifies the token and activates the pending subscription
+func ConfirmSubscription(token string) (*models.Subscriber, error) {
+	subscriber, err := getSubscriberFromToken(token, subscriberTokenActionConfirm)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscriber.PendingServices != nil {
+		subscriber.Services = subscriber.PendingServices
+		subscriber.PendingServices = nil
+	}
+
+	if !subscriber.Confirmed {
+		subscriber.Confirmed = true
+		subscriber.ConfirmedAt = time.Now()
+	}
+
+	if err := _dataStore.UpdateSubscriber(subscriber); err != nil {
+		return nil, err
+	}
+
+	return subscriber, nil
+}
+
+// Unsubscribe verifies the token and removes the subscriber
+func Unsubscribe(token string) error {
+	subscriber, err := getSubscriberFromToken(token, subscriberTokenActionUnsubscribe)
+	if err != nil {
+		return err
+	}
+
+	return _dataStore.DeleteSubscriber(subscriber.ID)
+}
+
+func validateSubscribedServices(services []models.SubscribedService) error {
+	for _, s := range services {
+		service, err := GetServiceByName(s.Name)
+		if err != nil {
+			return err
+		}
+
+		if service == nil {
+			return fmt.Errorf("unknown service: %s", s.Name)
+		}
+
+		for _, regionCode := range s.Regions {
+			region, err := GetRegionByCodeAndServiceName(regionCode, s.Name)
+			if err != nil {
+				return err
+			}
+
+			if region == nil {
+				return fmt.Errorf("unknown region %s for service %s", regionCode, s.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// notifySubscribers queues an email for every confirmed subscriber interested in the provided services, the
+// mail worker sends them in order
+func notifySubscribers(subject string, templateName string, data map[string]interface{}, services []models.ServiceUpdate) {
+	subscribers, err := _dataStore.GetSubscribers()
+	if err != nil {
+		log.Println("Error while getting the subscribers:", err)
+		return
+	}
+
+	for _, subscriber := range subscribers {
+		if !subscriber.Confirmed || !subscriber.IsInterestedIn(services) {
+			continue
+		}
+
+		data["unsubscribeURL"] = subscriberActionURL(subscriber, subscriberTokenActionUnsubscribe)
+
+		body, err := renderMailTemplate(templateName, data)
+		if err != nil {
+			log.Printf("Error while rendering the email for subscriber %d: %v\n", subscriber.ID, err)
+			continue
+		}
+
+		if err := queueMail(subscriber.Email, subject, body); err != nil {
+			log.Printf("Error while queueing the email for subscriber %d: %v\n", subscriber.ID, err)
+		}
+	}
+}
+
+// NotifySubscribersOfIncident emails the subscribers about a new incident
+func NotifySubscribersOfIncident(incident *models.Incident) {
+	notifySubscribers(
+		fmt.Sprintf("[%s] %s", incident.Status, incident.Title),
+		"incident.tmpl",
+		map[string]interface{}{
+			"owner":    config.Config.Website.Title,
+			"incident": incident,
+			"url":      fmt.Sprintf("%s/i/%d", websiteURL(), incident.ID),
+		},
+		incident.Services,
+	)
+}
+
+// NotifySubscribersOfIncidentUpdate emails the subscribers about an update to an incident
+func NotifySubscribersOfIncidentUpdate(incident *models.Incident, update *models.StatusUpdate) {
+	notifySubscribers(
+		fmt.Sprintf("[%s] %s", update.Status, incident.Title),
+		"incidentUpdate.tmpl",
+		map[string]interface{}{
+			"owner":    config.Config.Website.Title,
+			"incident": incident,
+			"update":   update,
+			"url":      fmt.Sprintf("%s/i/%d", websiteURL(), incident.ID),
+		},
+		incident.Services,
+	)
+}
+
+// NotifySubscribersOfPostmortem emails the subscribers about the postmortem published for an incident
+func NotifySubscribersOfPostmortem(incident *models.Incident) {
+	notifySubscribers(
+		fmt.Sprintf("[Postmortem] %s", incident.Title),
+		"postmortem.tmpl",
+		map[string]interface{}{
+			"owner":    config.Config.Website.Title,
+			"incident": incident,
+			"url":      fmt.Sprintf("%s/i/%d", websiteURL(), incident.ID),
+		},
+		incident.Services,
+	)
+}
+
+// NotifySubscribersOfScheduledMaintenanceUpdate emails the subscribers about an update to a scheduled maintenance
+func NotifySubscribersOfScheduledMaintenanceUpdate(scheduledMaintenance *models.ScheduledMaintenance, update *models.StatusUpdate) {
+	notifySubscribers(
+		fmt.Sprintf("[%s] %s", update.Status, scheduledMaintenance.Title),
+		"maintenanceUpdate.tmpl",
+		map[string]interface{}{
+			"owner":       config.Config.Website.Title,
+			"maintenance": scheduledMaintenance,
+			"update":      update,
+			"url":         fmt.Sprintf("%s/m/%d", websiteURL(), scheduledMaintenance.ID),
+		},
+		scheduledMaintenance.Services,
+	)
+}
+
+func init() {
+	RegisterNotifierType("email", newEmailNotifier)
+}
+
+// emailNotifier emails the confirmed subscribers which are interested in the affected services
+type emailNotifier struct {
+	name string
+}
+
+func newEmailNotifier(name string, settings NotifierSettings) (Notifier, error) {
+	if !config.Config.Subscriptions.Enabled {
+		return nil, errors.New("subscriptions need to be enabled")
+	}
+
+	return &emailNotifier{name: name}, nil
+}
+
+func (e *emailNotifier) Name() string {
+	return e.name
+}
+
+func (e *emailNotifier) Notify(event *models.Event) (string, error) {
+	switch event.Type {
+	case models.EventIncidentCreated:
+		NotifySubscribersOfIncident(event.Incident)
+	case models.EventIncidentUpdated, models.EventIncidentResolved:
+		NotifySubscribersOfIncidentUpdate(event.Incident, event.Update)
+	case models.EventIncidentPostmortemPublished:
+		NotifySubscribersOfPostmortem(event.Incident)
+	case models.EventMaintenanceStarted, models.EventMaintenanceUpdated, models.EventMaintenanceCompleted, models.EventMaintenanceExtended, models.EventMaintenanceCancelled:
+		NotifySubscribersOfScheduledMaintenanceUpdate(event.ScheduledMaintenance, event.Update)
+	}
+
+	return "", nil
+}
+
+func subscriberActionURL(subscriber *models.Subscriber, action string) string {
+	return fmt.Sprintf("%s/api/v1/subscribers/%s?token=%s", websiteURL(), action, signSubscriberToken(subscriber, action))
+}
+
+// signSubscriberToken creates a token bound to the subscriber, their email address and the action, which
+// expires after the TTL of the action. Changing the email address of a subscriber invalidates all of the tokens
+// handed out before.
+func signSubscriberToken(subscriber *models.Subscriber, action string) string {
+	payload := fmt.Sprintf("%d:%s:%d:%s", subscriber.ID, action, time.Now().Unix(), subscriber.Email)
+
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(subscriberTokenSignature(payload))
+}
+
+func subscriberTokenSignature(payload string) []byte {
+	mac := hmac.New(sha256.New, []byte(config.Config.Subscriptions.TokenSecret))
+	mac.Write([]byte(payload))
+
+	return mac.Sum(nil)
+}
+
+func getSubscriberFromToken(token string, action string) (*models.Subscriber, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalidSubscriberToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidSubscriberToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, ErrInvalidSubscriberToken
+	}
+
+	if !hmac.Equal(signature, subscriberTokenSignature(string(payload))) {
+		return nil, ErrInvalidSubscriberToken
+	}
+
+	fields := strings.SplitN(string(payload), ":", 4)
+	if len(fields) != 4 || fields[1] != action {
+		return nil, ErrInvalidSubscriberToken
+	}
+
+	id, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil, ErrInvalidSubscriberToken
+	}
+
+	issuedAt, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil || time.Since(time.Unix(issuedAt, 0)) > subscriberTokenTTL[action] {
+		return nil, ErrInvalidSubscriberToken
+	}
+
+	subscriber, err := _dataStore.GetSubscriberByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscriber == nil || subscriber.Email != fields[3] {
+		return nil, ErrInvalidSubscriberToken
+	}
+
+	return subscriber, nil
+}
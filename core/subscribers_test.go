@@ -0,0 +1,87 @@
+package core
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+func newTestSubscriber(t *testing.T, email string) *models.Subscriber {
+	config.Config.Subscriptions.TokenSecret = "subscriber-token-secret"
+
+	subscriber := &models.Subscriber{Email: email, Services: make([]models.SubscribedService, 0)}
+	if err := _dataStore.CreateSubscriber(subscriber); err != nil {
+		t.Fatalf("unable to create the subscriber: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_dataStore.DeleteSubscriber(subscriber.ID) //nolint:errcheck
+	})
+
+	return subscriber
+}
+
+// signedSubscriberToken signs the payload like signSubscriberToken does, so tokens issued in the past can be made
+func signedSubscriberToken(payload string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(subscriberTokenSignature(payload))
+}
+
+func TestGetSubscriberFromToken(t *testing.T) {
+	subscriber := newTestSubscriber(t, "tokens@example.com")
+	other := newTestSubscriber(t, "other@example.com")
+
+	token := signSubscriberToken(subscriber, subscriberTokenActionConfirm)
+	parts := strings.Split(token, ".")
+	issued := func(action string, age time.Duration) string {
+		return signedSubscriberToken(fmt.Sprintf("%d:%s:%d:%s", subscriber.ID, action, time.Now().Add(-age).Unix(), subscriber.Email))
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		action string
+		valid  bool
+	}{
+		{name: "confirm token", token: token, action: subscriberTokenActionConfirm, valid: true},
+		{name: "unsubscribe token", token: signSubscriberToken(subscriber, subscriberTokenActionUnsubscribe), action: subscriberTokenActionUnsubscribe, valid: true},
+		{name: "other action", token: token, action: subscriberTokenActionUnsubscribe},
+		{name: "confirm token within its ttl", token: issued(subscriberTokenActionConfirm, 47*time.Hour), action: subscriberTokenActionConfirm, valid: true},
+		{name: "expired confirm token", token: issued(subscriberTokenActionConfirm, 49*time.Hour), action: subscriberTokenActionConfirm},
+		{name: "unsubscribe token within its ttl", token: issued(subscriberTokenActionUnsubscribe, 89*24*time.Hour), action: subscriberTokenActionUnsubscribe, valid: true},
+		{name: "expired unsubscribe token", token: issued(subscriberTokenActionUnsubscribe, 91*24*time.Hour), action: subscriberTokenActionUnsubscribe},
+		{
+			name:   "tampered subscriber",
+			token:  base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s:%d:%s", other.ID, subscriberTokenActionConfirm, time.Now().Unix(), other.Email))) + "." + parts[1],
+			action: subscriberTokenActionConfirm,
+		},
+		{name: "tampered signature", token: parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte("not the signature")), action: subscriberTokenActionConfirm},
+		{name: "signed with another secret", token: parts[0] + "." + base64.RawURLEncoding.EncodeToString(make([]byte, 32)), action: subscriberTokenActionConfirm},
+		{name: "changed email", token: signedSubscriberToken(fmt.Sprintf("%d:%s:%d:%s", subscriber.ID, subscriberTokenActionConfirm, time.Now().Unix(), "old@example.com")), action: subscriberTokenActionConfirm},
+		{name: "unknown subscriber", token: signedSubscriberToken(fmt.Sprintf("%d:%s:%d:%s", 999999, subscriberTokenActionConfirm, time.Now().Unix(), subscriber.Email)), action: subscriberTokenActionConfirm},
+		{name: "missing fields", token: signedSubscriberToken(fmt.Sprintf("%d:%s", subscriber.ID, subscriberTokenActionConfirm)), action: subscriberTokenActionConfirm},
+		{name: "no signature", token: parts[0], action: subscriberTokenActionConfirm},
+		{name: "not base64", token: "!!!.???", action: subscriberTokenActionConfirm},
+		{name: "empty", token: "", action: subscriberTokenActionConfirm},
+	}
+
+	for _, test := range tests {
+		found, err := getSubscriberFromToken(test.token, test.action)
+
+		if test.valid {
+			if err != nil || found == nil || found.ID != subscriber.ID {
+				t.Errorf("%s: expected subscriber %d, got %+v and %v", test.name, subscriber.ID, found, err)
+			}
+
+			continue
+		}
+
+		if err != ErrInvalidSubscriberToken {
+			t.Errorf("%s: expected the token to be rejected, got %+v and %v", test.name, found, err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"bytes"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/dghubble/go-twitter/twitter"
+	"github.com/dghubble/oauth1"
+)
+
+func init() {
+	RegisterNotifierType("twitter", newTwitterNotifier)
+}
+
+// twitterNotifier tweets about the incidents and scheduled maintenance, with the updates as replies to the original tweet
+type twitterNotifier struct {
+	name   string
+	client *twitter.Client
+}
+
+func newTwitterNotifier(name string, settings NotifierSettings) (Notifier, error) {
+	if err := requireSettings(settings, "consumerKey", "consumerSecret", "accessToken", "accessSecret"); err != nil {
+		return nil, err
+	}
+
+	conf := oauth1.NewConfig(settings.String("consumerKey"), settings.String("consumerSecret"))
+	token := oauth1.NewToken(settings.String("accessToken"), settings.String("accessSecret"))
+	http := conf.Client(oauth1.NoContext, token)
+	http.Timeout = 5 * time.Second
+
+	return &twitterNotifier{
+		name:   name,
+		client: twitter.NewClient(http),
+	}, nil
+}
+
+func (t *twitterNotifier) Name() string {
+	return t.name
+}
+
+func (t *twitterNotifier) Notify(event *models.Event) (string, error) {
+	text, err := RenderTweet(event)
+	if err != nil || text == "" {
+		return "", err
+	}
+
+	params := &twitter.StatusUpdateParams{}
+	if replyTo := t.originalTweetID(event); replyTo != 0 {
+		params.InReplyToStatusID = replyTo
+	}
+
+	tweet, _, err := t.client.Statuses.Update(text, params)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(tweet.ID, 10), nil
+}
+
+// originalTweetID gets the tweet to reply to, falling back to the id stored before there were notifiers
+func (t *twitterNotifier) originalTweetID(event *models.Event) int64 {
+	if id, err := strconv.ParseInt(originalNotificationID(event, t.name), 10, 64); err == nil {
+		return id
+	}
+
+	switch {
+	case event.Incident != nil:
+		return event.Incident.OriginalTweetID
+	case event.ScheduledMaintenance != nil:
+		return event.ScheduledMaintenance.OriginalTweetID
+	}
+
+	return 0
+}
+
+// RenderTweet gives the text posted about the event by the twitter and mastodon notifiers, empty when
+// nothing is posted about it
+func RenderTweet(event *models.Event) (string, error) {
+	var name string
+	var data interface{}
+
+	switch event.Type {
+	case models.EventIncidentCreated:
+		name = "create.tmpl"
+		data = event.Incident
+	case models.EventIncidentUpdated, models.EventIncidentResolved:
+		name = "update.tmpl"
+		data = map[string]interface{}{
+			"update":   event.Update,
+			"incident": event.Incident,
+		}
+	case models.EventMaintenanceCreated:
+		name = "maintenance.tmpl"
+		data = event.ScheduledMaintenance
+	case models.EventMaintenanceStarted, models.EventMaintenanceUpdated, models.EventMaintenanceCompleted, models.EventMaintenanceExtended, models.EventMaintenanceCancelled:
+		name = "maintenanceUpdate.tmpl"
+		data = map[string]interface{}{
+			"update":      event.Update,
+			"maintenance": event.ScheduledMaintenance,
+		}
+	case models.EventIncidentPostmortemPublished:
+		name = "postmortem.tmpl"
+		data = event.Incident
+	default:
+		return "", nil
+	}
+
+	tmpl, err := template.ParseFiles("templates/incident/tweet/" + name)
+	if err != nil {
+		return "", err
+	}
+
+	b := &bytes.Buffer{}
+	if err := tmpl.ExecuteTemplate(b, tmpl.Name(), data); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
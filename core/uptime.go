@@ -0,0 +1,436 @@
+package core
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// defaultUptimeWeights is how much of the time spent in a status counts as downtime
+var defaultUptimeWeights = map[models.ServiceAndRegionStatus]float64{
+	models.ServiceStatusNominal:              0,
+	models.ServiceStatusDegraded:             0.25,
+	models.ServiceStatusPartialOutage:        0.5,
+	models.ServiceStatusOutage:               1,
+	models.ServiceStatusScheduledMaintenance: 1,
+}
+
+// GetServiceUptime calculates the uptime of the service and each of its regions between from and to.
+// Both uptime and error will be nil if the service is not found.
+func GetServiceUptime(serviceID int, from, to time.Time, excludeMaintenance bool) (*models.Uptime, error) {
+	service, err := _dataStore.GetServiceByID(serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if service == nil {
+		return nil, nil
+	}
+
+	regions, err := _dataStore.GetRegions()
+	if err != nil {
+		return nil, err
+	}
+
+	return serviceUptime(service, regions, from, to, excludeMaintenance)
+}
+
+// GetServicesUptime calculates the uptime of all the enabled services and their regions between from and to
+func GetServicesUptime(from, to time.Time, excludeMaintenance bool) ([]*models.Uptime, error) {
+	services, err := _dataStore.GetServicesEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	regions, err := _dataStore.GetRegions()
+	if err != nil {
+		return nil, err
+	}
+
+	uptimes := make([]*models.Uptime, 0, len(services))
+	for _, service := range services {
+		uptime, err := serviceUptime(service, regions, from, to, excludeMaintenance)
+		if err != nil {
+			return nil, err
+		}
+
+		uptimes = append(uptimes, uptime)
+	}
+
+	return uptimes, nil
+}
+
+func serviceUptime(service *models.Service, regions []*models.Region, from, to time.Time, excludeMaintenance bool) (*models.Uptime, error) {
+	if now := time.Now(); to.After(now) {
+		to = now
+	}
+
+	if !from.Before(to) {
+		return nil, errors.New("the start of the window must be before its end")
+	}
+
+	changes, err := _dataStore.GetStatusChangesByServiceID(service.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	serviceChanges := make([]*models.StatusChange, 0, len(changes))
+	for _, c := range changes {
+		if !c.IsRegion() {
+			serviceChanges = append(serviceChanges, c)
+		}
+	}
+
+	uptime := calculateUptime(serviceChanges, service.Status, from, to, excludeMaintenance)
+	uptime.ServiceID = service.ID
+	uptime.ServiceName = service.Name
+	uptime.Regions = make([]*models.Uptime, 0)
+
+	for _, region := range regions {
+		if region.ServiceID != service.ID {
+			continue
+		}
+
+		regionChanges := make([]*models.StatusChange, 0)
+		for _, c := range changes {
+			if c.RegionCode == region.RegionCode {
+				regionChanges = append(regionChanges, c)
+			}
+		}
+
+		regionUptime := calculateUptime(regionChanges, region.Status, from, to, excludeMaintenance)
+		regionUptime.ServiceID = service.ID
+		regionUptime.ServiceName = service.Name
+		regionUptime.RegionCode = region.RegionCode
+		regionUptime.RegionName = region.Name
+
+		uptime.Regions = append(uptime.Regions, regionUptime)
+	}
+
+	return uptime, nil
+}
+
+// calculateUptime works out how long was spent in each status between from and to, based on the changes
+// which have to be sorted oldest first, and turns that into a percentage using the weights. The time before
+// the status history was recorded is left out, making the uptime partial.
+func calculateUptime(changes []*models.StatusChange, currentStatus models.ServiceAndRegionStatus, from, to time.Time, excludeMaintenance bool) *models.Uptime {
+	uptime := &models.Uptime{
+		From:               from,
+		To:                 to,
+		RecordedFrom:       from,
+		ExcludeMaintenance: excludeMaintenance,
+		Durations:          make(map[models.ServiceAndRegionStatus]float64),
+		Percentage:         100,
+	}
+
+	if since := recordedSince(changes, to); since.After(from) {
+		from = since
+		uptime.RecordedFrom = since
+		uptime.Partial = true
+	}
+
+	if !from.Before(to) {
+		uptime.RecordedFrom = to
+		return uptime
+	}
+
+	status := statusAt(changes, currentStatus, from)
+
+	cursor := from
+	for _, c := range changes {
+		if !c.Time.After(from) {
+			continue
+		}
+
+		if c.Time.After(to) {
+			break
+		}
+
+		uptime.Durations[status] += c.Time.Sub(cursor).Seconds()
+		cursor = c.Time
+		status = c.Status
+	}
+
+	uptime.Durations[status] += to.Sub(cursor).Seconds()
+
+	weights := uptimeWeights()
+
+	var counted, downtime float64
+	for s, seconds := range uptime.Durations {
+		// Time with an unknown status can't count either way
+		if s == models.ServiceStatusUnknown {
+			continue
+		}
+
+		if s == models.ServiceStatusScheduledMaintenance && excludeMaintenance {
+			continue
+		}
+
+		counted += seconds
+		downtime += seconds * weights[s]
+	}
+
+	if counted > 0 {
+		uptime.Percentage = 100 * (1 - downtime/counted)
+	}
+
+	return uptime
+}
+
+// statusAt works out the status at the given time from the changes, which have to be sorted oldest first.
+// It is the status of the last change before it, otherwise whatever the first change after it changed it from.
+func statusAt(changes []*models.StatusChange, currentStatus models.ServiceAndRegionStatus, t time.Time) models.ServiceAndRegionStatus {
+	status := currentStatus
+	for i, c := range changes {
+		if c.Time.After(t) {
+			if i == 0 {
+				status = c.PreviousStatus
+			}
+
+			break
+		}
+
+		status = c.Status
+	}
+
+	return status
+}
+
+// uptimeWeights gets the weights of the statuses, with the ones from the config taking precedence
+func uptimeWeights() map[models.ServiceAndRegionStatus]float64 {
+	weights := make(map[models.ServiceAndRegionStatus]float64, len(defaultUptimeWeights))
+	for s, w := range defaultUptimeWeights {
+		weights[s] = w
+	}
+
+	for s, w := range config.Config.Uptime.Weights {
+		if status, ok := models.ServiceStatuses[strings.ToLower(s)]; ok {
+			weights[status] = w
+		}
+	}
+
+	return weights
+}
+
+// dailyStatusHistoryMaxAge is how long the daily status history is kept before it's calculated again. Status
+// changes and changed services or regions drop it right away, new and changed incidents show up within that time.
+const dailyStatusHistoryMaxAge = time.Minute
+
+// dailyStatusHistoryCache keeps the daily status history the index page shows, as calculating it reads all of
+// the status changes and incidents of the days
+var dailyStatusHistoryCache struct {
+	sync.Mutex
+	days         int
+	histories    []*models.DailyStatusHistory
+	calculatedAt time.Time
+}
+
+// invalidateDailyStatusHistory makes the next request calculate the daily status history again
+func invalidateDailyStatusHistory() {
+	dailyStatusHistoryCache.Lock()
+	defer dailyStatusHistoryCache.Unlock()
+
+	dailyStatusHistoryCache.histories = nil
+}
+
+// GetDailyStatusHistory gets the worst status of each of the last days for all the enabled services and their regions,
+// along with the incidents which affected them on each day. The histories are shared, so they must not be changed.
+func GetDailyStatusHistory(days int) ([]*models.DailyStatusHistory, error) {
+	dailyStatusHistoryCache.Lock()
+	defer dailyStatusHistoryCache.Unlock()
+
+	now := time.Now()
+	cache := &dailyStatusHistoryCache
+
+	if cache.histories != nil && cache.days == days && now.Sub(cache.calculatedAt) < dailyStatusHistoryMaxAge &&
+		truncateToDay(cache.calculatedAt).Equal(truncateToDay(now)) {
+		return cache.histories, nil
+	}
+
+	histories, err := calculateDailyStatusHistory(days, now)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.days = days
+	cache.histories = histories
+	cache.calculatedAt = now
+
+	return histories, nil
+}
+
+func calculateDailyStatusHistory(days int, now time.Time) ([]*models.DailyStatusHistory, error) {
+	services, err := _dataStore.GetServicesEnabled()
+	if err != nil {
+		return nil, err
+	}
+
+	regions, err := _dataStore.GetRegions()
+	if err != nil {
+		return nil, err
+	}
+
+	from := truncateToDay(now).AddDate(0, 0, -(days - 1))
+
+	incidents, err := _dataStore.GetIncidentsSince(from)
+	if err != nil {
+		return nil, err
+	}
+
+	histories := make([]*models.DailyStatusHistory, 0, len(services))
+	for _, service := range services {
+		changes, err := _dataStore.GetStatusChangesByServiceID(service.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		serviceChanges := make([]*models.StatusChange, 0, len(changes))
+		for _, c := range changes {
+			if !c.IsRegion() {
+				serviceChanges = append(serviceChanges, c)
+			}
+		}
+
+		history := &models.DailyStatusHistory{
+			ServiceName: service.Name,
+			Uptime:      recordedUptime(serviceChanges, service.Status, from, now),
+			Days:        dailyStatuses(serviceChanges, service.Status, from, now, days, incidentsAffecting(incidents, service.Name, "")),
+			Regions:     make([]*models.DailyStatusHistory, 0),
+		}
+
+		for _, region := range regions {
+			if region.ServiceID != service.ID {
+				continue
+			}
+
+			regionChanges := make([]*models.StatusChange, 0)
+			for _, c := range changes {
+				if c.RegionCode == region.RegionCode {
+					regionChanges = append(regionChanges, c)
+				}
+			}
+
+			history.Regions = append(history.Regions, &models.DailyStatusHistory{
+				ServiceName: service.Name,
+				RegionCode:  region.RegionCode,
+				RegionName:  region.Name,
+				Uptime:      recordedUptime(regionChanges, region.Status, from, now),
+				Days:        dailyStatuses(regionChanges, region.Status, from, now, days, incidentsAffecting(incidents, service.Name, region.RegionCode)),
+			})
+		}
+
+		histories = append(histories, history)
+	}
+
+	return histories, nil
+}
+
+// recordedSince is when the status history of the changes starts, which have to be sorted oldest first. Nothing is
+// known about the status before the first change, without any changes there is only the current status.
+func recordedSince(changes []*models.StatusChange, now time.Time) time.Time {
+	if len(changes) == 0 {
+		return now
+	}
+
+	return changes[0].Time
+}
+
+// recordedUptime calculates the uptime between from and now, leaving out the time before the history was recorded
+func recordedUptime(changes []*models.StatusChange, currentStatus models.ServiceAndRegionStatus, from, now time.Time) float64 {
+	return calculateUptime(changes, currentStatus, from, now, true).Percentage
+}
+
+// dailyStatuses works out the worst status of each day, starting at from, based on the changes which have to be sorted oldest first.
+// The days before the history was recorded have no data.
+func dailyStatuses(changes []*models.StatusChange, currentStatus models.ServiceAndRegionStatus, from, now time.Time, days int, incidents []*models.Incident) []models.DailyStatus {
+	dailyStatuses := make([]models.DailyStatus, 0, days)
+
+	since := recordedSince(changes, now)
+
+	for d := 0; d < days; d++ {
+		dayStart := from.AddDate(0, 0, d)
+		dayEnd := dayStart.AddDate(0, 0, 1)
+
+		dayIncidents := make([]*models.Incident, 0)
+		for _, incident := range incidents {
+			if incidentOpenBetween(incident, dayStart, dayEnd) {
+				dayIncidents = append(dayIncidents, incident)
+			}
+		}
+
+		if !since.Before(dayEnd) {
+			dailyStatuses = append(dailyStatuses, models.DailyStatus{Day: dayStart, NoData: true, Incidents: dayIncidents})
+			continue
+		}
+
+		start := dayStart
+		if since.After(start) {
+			start = since
+		}
+
+		statuses := []models.ServiceAndRegionStatus{statusAt(changes, currentStatus, start)}
+		for _, c := range changes {
+			if c.Time.After(start) && c.Time.Before(dayEnd) {
+				statuses = append(statuses, c.Status)
+			}
+		}
+
+		dailyStatuses = append(dailyStatuses, models.DailyStatus{
+			Day:       dayStart,
+			Status:    models.WorstServiceStatus(statuses...),
+			Incidents: dayIncidents,
+		})
+	}
+
+	return dailyStatuses
+}
+
+// incidentsAffecting filters the incidents down to the ones affecting the service, or the region of it when a region code is given
+func incidentsAffecting(incidents []*models.Incident, serviceName, regionCode string) []*models.Incident {
+	affecting := make([]*models.Incident, 0)
+
+	for _, incident := range incidents {
+		for _, s := range incident.Services {
+			if s.Name != serviceName {
+				continue
+			}
+
+			if regionCode == "" || stringInSlice(regionCode, s.Regions) {
+				affecting = append(affecting, incident)
+			}
+
+			break
+		}
+	}
+
+	return affecting
+}
+
+// incidentOpenBetween tells whether the incident was going on at some point between start and end
+func incidentOpenBetween(incident *models.Incident, start, end time.Time) bool {
+	if !incident.Time.Before(end) {
+		return false
+	}
+
+	if incident.Status != models.IncidentStatusResolved || len(incident.Updates) == 0 {
+		return true
+	}
+
+	resolvedAt := incident.Updates[len(incident.Updates)-1].Time
+
+	return !resolvedAt.Before(start)
+}
+
+func stringInSlice(value string, values []string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
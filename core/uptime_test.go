@@ -0,0 +1,101 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+func TestDailyStatusesHaveNoDataBeforeTheHistory(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	now := from.AddDate(0, 0, 4).Add(12 * time.Hour)
+
+	changes := []*models.StatusChange{
+		{Time: from.AddDate(0, 0, 1).Add(18 * time.Hour), PreviousStatus: models.ServiceStatusOutage, Status: models.ServiceStatusNominal},
+		{Time: from.AddDate(0, 0, 3).Add(6 * time.Hour), PreviousStatus: models.ServiceStatusNominal, Status: models.ServiceStatusDegraded},
+		{Time: from.AddDate(0, 0, 3).Add(8 * time.Hour), PreviousStatus: models.ServiceStatusDegraded, Status: models.ServiceStatusNominal},
+	}
+
+	days := dailyStatuses(changes, models.ServiceStatusNominal, from, now, 5, nil)
+
+	expected := []struct {
+		noData bool
+		status models.ServiceAndRegionStatus
+	}{
+		{noData: true},
+		// The outage before the first change isn't recorded, only the status it changed to
+		{status: models.ServiceStatusNominal},
+		{status: models.ServiceStatusNominal},
+		{status: models.ServiceStatusDegraded},
+		{status: models.ServiceStatusNominal},
+	}
+
+	if len(days) != len(expected) {
+		t.Fatalf("expected %d days, got %d", len(expected), len(days))
+	}
+
+	for i, e := range expected {
+		if days[i].NoData != e.noData || days[i].Status != e.status {
+			t.Errorf("day %d: expected no data %v and status %q, got %v and %q", i, e.noData, e.status, days[i].NoData, days[i].Status)
+		}
+	}
+}
+
+func TestDailyStatusesWithoutHistory(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	now := from.AddDate(0, 0, 2).Add(12 * time.Hour)
+
+	days := dailyStatuses(nil, models.ServiceStatusDegraded, from, now, 3, nil)
+
+	if !days[0].NoData || !days[1].NoData {
+		t.Errorf("expected the days before today to have no data, got %+v", days)
+	}
+
+	if days[2].NoData || days[2].Status != models.ServiceStatusDegraded {
+		t.Errorf("expected today to have the current status, got %+v", days[2])
+	}
+
+	if uptime := recordedUptime(nil, models.ServiceStatusDegraded, from, now); uptime != 100 {
+		t.Errorf("expected no downtime without history, got %v", uptime)
+	}
+}
+
+func TestRecordedUptimeLeavesOutTimeBeforeTheHistory(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	now := from.AddDate(0, 0, 2)
+
+	changes := []*models.StatusChange{
+		{Time: from.AddDate(0, 0, 1), PreviousStatus: models.ServiceStatusOutage, Status: models.ServiceStatusOutage},
+		{Time: from.AddDate(0, 0, 1).Add(12 * time.Hour), PreviousStatus: models.ServiceStatusOutage, Status: models.ServiceStatusNominal},
+	}
+
+	if uptime := recordedUptime(changes, models.ServiceStatusNominal, from, now); uptime != 50 {
+		t.Errorf("expected an uptime of 50%% over the recorded day, got %v", uptime)
+	}
+}
+
+func TestCalculateUptimeIsPartialBeforeTheHistory(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 30)
+	since := to.AddDate(0, 0, -1)
+
+	changes := []*models.StatusChange{
+		{Time: since, PreviousStatus: models.ServiceStatusUnknown, Status: models.ServiceStatusNominal},
+		{Time: since.Add(18 * time.Hour), PreviousStatus: models.ServiceStatusNominal, Status: models.ServiceStatusOutage},
+	}
+
+	uptime := calculateUptime(changes, models.ServiceStatusOutage, from, to, false)
+
+	if !uptime.Partial || !uptime.RecordedFrom.Equal(since) {
+		t.Errorf("expected the uptime to be recorded from %v only, got partial %v from %v", since, uptime.Partial, uptime.RecordedFrom)
+	}
+
+	if uptime.Percentage != 75 {
+		t.Errorf("expected an uptime of 75%% over the recorded day, got %v", uptime.Percentage)
+	}
+
+	if uptime := calculateUptime(nil, models.ServiceStatusNominal, from, to, false); !uptime.Partial || len(uptime.Durations) != 0 {
+		t.Errorf("expected no recorded time without history, got %+v", uptime)
+	}
+}
@@ -0,0 +1,428 @@
+package core
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/RocketChat/statuscentral/buildInfo"
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/models"
+)
+
+const (
+	webhookDefaultMaxAttempts = 10
+	webhookBaseBackoff        = 30 * time.Second
+	webhookMaxBackoff         = 6 * time.Hour
+	webhookWorkerInterval     = 10 * time.Second
+	webhookDeliveryRetention  = 30 * 24 * time.Hour
+)
+
+var webhookHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// webhookWakeUp allows new deliveries to be attempted right away instead of waiting for the next tick
+var webhookWakeUp = make(chan struct{}, 1)
+
+// GetWebhooks gets all of the webhooks from the storage layer
+func GetWebhooks() ([]*models.Webhook, error) {
+	return _dataStore.GetWebhooks()
+}
+
+// GetWebhookByID gets the webhook by id, both webhook and error will be nil if none found
+func GetWebhookByID(id int) (*models.Webhook, error) {
+	return _dataStore.GetWebhookByID(id)
+}
+
+// CreateWebhook validates and creates the webhook, generating a secret when none is provided
+func CreateWebhook(webhook *models.Webhook) (*models.Webhook, error) {
+	if err := validateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	existing, err := _dataStore.GetWebhookByName(webhook.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil {
+		return nil, errors.New("webhook with that name already exists")
+	}
+
+	if webhook.Secret == "" {
+		secret, err := randomHex(32)
+		if err != nil {
+			return nil, err
+		}
+
+		webhook.Secret = secret
+	}
+
+	if webhook.Events == nil {
+		webhook.Events = make([]models.EventType, 0)
+	}
+
+	if err := _dataStore.CreateWebhook(webhook); err != nil {
+		return nil, err
+	}
+
+	return webhook, nil
+}
+
+// PatchWebhook updates the webhook, keeping the existing values for anything not provided
+func PatchWebhook(webhook *models.Webhook) error {
+	existingWebhook, err := _dataStore.GetWebhookByID(webhook.ID)
+	if err != nil {
+		return err
+	}
+
+	if existingWebhook == nil {
+		return errors.New("invalid webhook")
+	}
+
+	if webhook.Name == "" {
+		webhook.Name = existingWebhook.Name
+	}
+
+	if webhook.URL == "" {
+		webhook.URL = existingWebhook.URL
+	}
+
+	if webhook.Secret == "" {
+		webhook.Secret = existingWebhook.Secret
+	}
+
+	if webhook.Events == nil {
+		webhook.Events = existingWebhook.Events
+	}
+
+	webhook.CreatedAt = existingWebhook.CreatedAt
+
+	if err := validateWebhook(webhook); err != nil {
+		return err
+	}
+
+	return _dataStore.UpdateWebhook(webhook)
+}
+
+// DeleteWebhook removes the webhook from the storage layer
+func DeleteWebhook(id int) error {
+	return _dataStore.DeleteWebhook(id)
+}
+
+// GetWebhookDeliveries gets the latest deliveries of a webhook
+func GetWebhookDeliveries(webhookID int, limit int) ([]*models.WebhookDelivery, error) {
+	if webhookID <= 0 {
+		return nil, errors.New("invalid webhook id")
+	}
+
+	return _dataStore.GetWebhookDeliveriesByWebhookID(webhookID, limit)
+}
+
+func validateWebhook(webhook *models.Webhook) error {
+	if webhook.Name == "" {
+		return errors.New("webhook needs to have a name")
+	}
+
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("webhook needs to have a valid http or https url")
+	}
+
+	for _, e := range webhook.Events {
+		if _, ok := models.EventTypes[e.String()]; !ok {
+			return fmt.Errorf("invalid event type: %s", e)
+		}
+	}
+
+	return nil
+}
+
+func createWebhooksFromConfig() error {
+	for _, endpoint := range config.Config.Webhooks.Endpoints {
+		webhook, err := _dataStore.GetWebhookByName(endpoint.Name)
+		if err != nil {
+			return err
+		}
+
+		if webhook != nil {
+			continue
+		}
+
+		events := make([]models.EventType, 0, len(endpoint.Events))
+		for _, e := range endpoint.Events {
+			events = append(events, models.EventType(e))
+		}
+
+		toCreate := &models.Webhook{
+			Name:    endpoint.Name,
+			URL:     endpoint.URL,
+			Secret:  endpoint.Secret,
+			Events:  events,
+			Enabled: true,
+		}
+
+		if _, err := CreateWebhook(toCreate); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	RegisterNotifierType("webhooks", newWebhooksNotifier)
+}
+
+// webhooksNotifier queues a delivery of the events for the webhooks, the delivery worker takes it from there
+type webhooksNotifier struct {
+	name string
+}
+
+func newWebhooksNotifier(name string, settings NotifierSettings) (Notifier, error) {
+	if !config.Config.Webhooks.Enabled {
+		return nil, errors.New("webhooks need to be enabled")
+	}
+
+	return &webhooksNotifier{name: name}, nil
+}
+
+func (w *webhooksNotifier) Name() string {
+	return w.name
+}
+
+func (w *webhooksNotifier) Notify(event *models.Event) (string, error) {
+	return "", queueWebhookDeliveries(event)
+}
+
+// queueWebhookDeliveries queues a delivery of the event for every enabled webhook which wants it
+func queueWebhookDeliveries(event *models.Event) error {
+	webhooks, err := _dataStore.GetWebhooks()
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	queued := false
+
+	for _, webhook := range webhooks {
+		if !webhook.Enabled || !webhook.WantsEvent(event.Type) {
+			continue
+		}
+
+		delivery := &models.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        models.WebhookDeliveryStatusPending,
+			NextAttemptAt: time.Now(),
+		}
+
+		if err := _dataStore.CreateWebhookDelivery(delivery); err != nil {
+			log.Printf("Error while queueing the %s event for webhook %d: %v\n", event.Type, webhook.ID, err)
+			continue
+		}
+
+		queued = true
+	}
+
+	if queued {
+		select {
+		case webhookWakeUp <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// startWebhookDeliveryWorker attempts the pending deliveries in the background.
+// As deliveries are persisted, anything pending before a restart gets picked up again.
+func startWebhookDeliveryWorker() {
+	if !config.Config.Webhooks.Enabled {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(webhookWorkerInterval)
+		defer ticker.Stop()
+
+		lastCleanup := time.Time{}
+
+		for {
+			processWebhookDeliveries()
+
+			if time.Since(lastCleanup) > time.Hour {
+				if err := _dataStore.DeleteWebhookDeliveriesBefore(time.Now().Add(-webhookDeliveryRetention)); err != nil {
+					log.Println("Error while cleaning up the webhook deliveries:", err)
+				}
+
+				lastCleanup = time.Now()
+			}
+
+			select {
+			case <-ticker.C:
+			case <-webhookWakeUp:
+			}
+		}
+	}()
+}
+
+// processWebhookDeliveries attempts the deliveries which are due. The deliveries of each webhook are attempted in
+// order, while the webhooks are delivered to at the same time so a slow receiver doesn't hold up the others.
+func processWebhookDeliveries() {
+	deliveries, err := _dataStore.GetPendingWebhookDeliveries()
+	if err != nil {
+		log.Println("Error while getting the pending webhook deliveries:", err)
+		return
+	}
+
+	due := map[int][]*models.WebhookDelivery{}
+	now := time.Now()
+
+	for _, delivery := range deliveries {
+		if delivery.NextAttemptAt.After(now) {
+			continue
+		}
+
+		due[delivery.WebhookID] = append(due[delivery.WebhookID], delivery)
+	}
+
+	var wg sync.WaitGroup
+
+	for webhookID, webhookDeliveries := range due {
+		webhook, err := _dataStore.GetWebhookByID(webhookID)
+		if err != nil {
+			log.Println("Error while getting the webhook:", err)
+			continue
+		}
+
+		wg.Add(1)
+		go func(webhook *models.Webhook, webhookDeliveries []*models.WebhookDelivery) {
+			defer wg.Done()
+
+			for _, delivery := range webhookDeliveries {
+				attemptWebhookDelivery(webhook, delivery)
+
+				if err := _dataStore.UpdateWebhookDelivery(delivery); err != nil {
+					log.Println("Error while updating the webhook delivery:", err)
+				}
+			}
+		}(webhook, webhookDeliveries)
+	}
+
+	wg.Wait()
+}
+
+func attemptWebhookDelivery(webhook *models.Webhook, delivery *models.WebhookDelivery) {
+	if webhook == nil || !webhook.Enabled {
+		delivery.Status = models.WebhookDeliveryStatusFailed
+		delivery.LastError = "webhook was removed or disabled"
+		return
+	}
+
+	delivery.Attempts++
+
+	statusCode, err := sendWebhookDelivery(webhook, delivery)
+	delivery.LastStatusCode = statusCode
+
+	if err == nil {
+		delivery.Status = models.WebhookDeliveryStatusDelivered
+		delivery.DeliveredAt = time.Now()
+		delivery.LastError = ""
+		return
+	}
+
+	delivery.LastError = err.Error()
+
+	maxAttempts := config.Config.Webhooks.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = webhookDefaultMaxAttempts
+	}
+
+	if delivery.Attempts >= maxAttempts {
+		delivery.Status = models.WebhookDeliveryStatusFailed
+		log.Printf("Giving up on webhook delivery %d to %s after %d attempts: %v\n", delivery.ID, webhook.Name, delivery.Attempts, err)
+		return
+	}
+
+	delivery.NextAttemptAt = time.Now().Add(webhookBackoff(delivery.Attempts))
+}
+
+// webhookBackoff doubles the wait for every failed attempt, up to the max backoff
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= webhookMaxBackoff {
+			return webhookMaxBackoff
+		}
+	}
+
+	return backoff
+}
+
+func sendWebhookDelivery(webhook *models.Webhook, delivery *models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", fmt.Sprintf("StatusCentral/%s", buildInfo.GetVersion()))
+	req.Header.Set("X-StatusCentral-Event", delivery.EventType.String())
+	req.Header.Set("X-StatusCentral-Delivery", fmt.Sprintf("%d", delivery.ID))
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("X-StatusCentral-Timestamp", timestamp)
+	req.Header.Set("X-StatusCentral-Signature", "sha256="+signWebhookPayload(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := webhookHTTPClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64*1024)) //nolint:errcheck // Only draining so the connection can be reused
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
+
+// signWebhookPayload returns the hex encoded HMAC-SHA256 of the timestamp, a dot and the payload using the webhook
+// secret. Signing the timestamp lets receivers reject old requests which are replayed.
+func signWebhookPayload(secret string, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
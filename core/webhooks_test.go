@@ -0,0 +1,77 @@
+package core
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+func TestSendWebhookDeliverySignsTheTimestampAndPayload(t *testing.T) {
+	const secret = "webhook-secret"
+	payload := []byte(`{"type":"incident.created"}`)
+
+	var timestamp, signature string
+	var body []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		timestamp = req.Header.Get("X-StatusCentral-Timestamp")
+		signature = req.Header.Get("X-StatusCentral-Signature")
+		body, _ = ioutil.ReadAll(req.Body)
+	}))
+	defer server.Close()
+
+	webhook := &models.Webhook{URL: server.URL, Secret: secret, Enabled: true}
+	delivery := &models.WebhookDelivery{ID: 1, EventType: models.EventIncidentCreated, Payload: payload}
+
+	if _, err := sendWebhookDelivery(webhook, delivery); err != nil {
+		t.Fatalf("unable to send the delivery: %v", err)
+	}
+
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(sent, 0)) > time.Minute {
+		t.Fatalf("expected the current unix time as the timestamp, got %q", timestamp)
+	}
+
+	// Verified the way a receiver would
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + string(body)))
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		t.Errorf("expected the signature %s, got %s", expected, signature)
+	}
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	payload := []byte(`{"type":"incident.created"}`)
+	signature := signWebhookPayload("secret", "1700000000", payload)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		payload   []byte
+	}{
+		{name: "other secret", secret: "other", timestamp: "1700000000", payload: payload},
+		{name: "replayed later", secret: "secret", timestamp: "1700000600", payload: payload},
+		{name: "tampered payload", secret: "secret", timestamp: "1700000000", payload: []byte(`{"type":"incident.resolved"}`)},
+	}
+
+	for _, test := range tests {
+		if signWebhookPayload(test.secret, test.timestamp, test.payload) == signature {
+			t.Errorf("%s: expected a different signature", test.name)
+		}
+	}
+
+	if signWebhookPayload("secret", "1700000000", payload) != signature {
+		t.Error("expected the same signature for the same timestamp and payload")
+	}
+}
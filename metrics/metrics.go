@@ -0,0 +1,319 @@
+// Package metrics is a small implementation of the Prometheus text exposition format, just enough
+// for the counters, gauges and histograms statuscentral exports.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Metric types as they appear in the exposition format
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// Label is a name and value pair identifying a sample
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample is a single value of a metric family
+type Sample struct {
+	// Suffix is appended to the name of the family, like _bucket, _sum and _count of histograms
+	Suffix string
+	Labels []Label
+	Value  float64
+}
+
+// Family is a metric with all of its samples
+type Family struct {
+	Name    string
+	Help    string
+	Type    string
+	Samples []Sample
+}
+
+// Collector gives the current samples of its metrics whenever they are scraped
+type Collector interface {
+	Collect() []*Family
+}
+
+// CollectorFunc lets a function be used as a collector
+type CollectorFunc func() []*Family
+
+// Collect calls the function
+func (f CollectorFunc) Collect() []*Family {
+	return f()
+}
+
+var (
+	collectorsLock sync.Mutex
+	collectors     []Collector
+)
+
+// Register adds the collector to the ones written out by WriteTo
+func Register(c Collector) {
+	collectorsLock.Lock()
+	defer collectorsLock.Unlock()
+
+	collectors = append(collectors, c)
+}
+
+// WriteTo writes all of the registered metrics in the text exposition format
+func WriteTo(w io.Writer) error {
+	collectorsLock.Lock()
+	registered := make([]Collector, len(collectors))
+	copy(registered, collectors)
+	collectorsLock.Unlock()
+
+	b := bufio.NewWriter(w)
+
+	for _, c := range registered {
+		for _, f := range c.Collect() {
+			fmt.Fprintf(b, "# HELP %s %s\n", f.Name, escapeHelp(f.Help))
+			fmt.Fprintf(b, "# TYPE %s %s\n", f.Name, f.Type)
+
+			for _, s := range f.Samples {
+				b.WriteString(f.Name)
+				b.WriteString(s.Suffix)
+				writeLabels(b, s.Labels)
+				b.WriteByte(' ')
+				b.WriteString(formatValue(s.Value))
+				b.WriteByte('\n')
+			}
+		}
+	}
+
+	return b.Flush()
+}
+
+// Gauge creates the family of a gauge with a single unlabeled sample
+func Gauge(name, help string, value float64) *Family {
+	return &Family{Name: name, Help: help, Type: TypeGauge, Samples: []Sample{{Value: value}}}
+}
+
+// CounterVec is a counter split up by the values of its labels
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string
+
+	lock   sync.Mutex
+	values map[string]*counterValue
+}
+
+type counterValue struct {
+	labelValues []string
+	value       float64
+}
+
+// NewCounterVec creates and registers a counter with the given label names
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	c := &CounterVec{name: name, help: help, labels: labels, values: make(map[string]*counterValue)}
+	Register(c)
+
+	return c
+}
+
+// Inc adds one to the counter of the label values, given in the same order as the label names
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds the value to the counter of the label values
+func (c *CounterVec) Add(value float64, labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	v, ok := c.values[key]
+	if !ok {
+		v = &counterValue{labelValues: labelValues}
+		c.values[key] = v
+	}
+
+	v.value += value
+}
+
+// Collect gives the counters sorted by their label values
+func (c *CounterVec) Collect() []*Family {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	f := &Family{Name: c.name, Help: c.help, Type: TypeCounter}
+
+	keys := make([]string, 0, len(c.values))
+	for key := range c.values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		v := c.values[key]
+		f.Samples = append(f.Samples, Sample{Labels: zipLabels(c.labels, v.labelValues), Value: v.value})
+	}
+
+	return []*Family{f}
+}
+
+// HistogramVec is a histogram split up by the values of its labels
+type HistogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+
+	lock   sync.Mutex
+	values map[string]*histogramValue
+}
+
+type histogramValue struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+// NewHistogramVec creates a histogram with the given upper bounds of its buckets and label names. It
+// isn't registered, so it can also be filled at scrape time by a collector.
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	sorted := make([]float64, len(buckets))
+	copy(sorted, buckets)
+	sort.Float64s(sorted)
+
+	return &HistogramVec{name: name, help: help, labels: labels, buckets: sorted, values: make(map[string]*histogramValue)}
+}
+
+// Observe adds the value to the histogram of the label values
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	v, ok := h.values[key]
+	if !ok {
+		v = &histogramValue{labelValues: labelValues, counts: make([]uint64, len(h.buckets))}
+		h.values[key] = v
+	}
+
+	for i, upperBound := range h.buckets {
+		if value <= upperBound {
+			v.counts[i]++
+		}
+	}
+
+	v.count++
+	v.sum += value
+}
+
+// Collect gives the cumulative buckets, sum and count of every set of label values
+func (h *HistogramVec) Collect() []*Family {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	f := &Family{Name: h.name, Help: h.help, Type: TypeHistogram}
+
+	keys := make([]string, 0, len(h.values))
+	for key := range h.values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		v := h.values[key]
+		labels := zipLabels(h.labels, v.labelValues)
+
+		for i, upperBound := range h.buckets {
+			f.Samples = append(f.Samples, Sample{
+				Suffix: "_bucket",
+				Labels: withLabel(labels, "le", formatValue(upperBound)),
+				Value:  float64(v.counts[i]),
+			})
+		}
+
+		f.Samples = append(f.Samples,
+			Sample{Suffix: "_bucket", Labels: withLabel(labels, "le", "+Inf"), Value: float64(v.count)},
+			Sample{Suffix: "_sum", Labels: labels, Value: v.sum},
+			Sample{Suffix: "_count", Labels: labels, Value: float64(v.count)},
+		)
+	}
+
+	return []*Family{f}
+}
+
+func zipLabels(names, values []string) []Label {
+	labels := make([]Label, 0, len(names))
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+
+		labels = append(labels, Label{Name: name, Value: value})
+	}
+
+	return labels
+}
+
+// withLabel copies the labels with one more added
+func withLabel(labels []Label, name, value string) []Label {
+	result := make([]Label, len(labels), len(labels)+1)
+	copy(result, labels)
+
+	return append(result, Label{Name: name, Value: value})
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func writeLabels(b *bufio.Writer, labels []Label) {
+	if len(labels) == 0 {
+		return
+	}
+
+	b.WriteByte('{')
+
+	for i, l := range labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		b.WriteString(l.Name)
+		b.WriteString(`="`)
+		b.WriteString(labelValueEscaper.Replace(l.Value))
+		b.WriteByte('"')
+	}
+
+	b.WriteByte('}')
+}
+
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
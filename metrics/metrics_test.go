@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"bytes"
+	"testing"
+)
+
+const expectedExposition = `# HELP test_requests_total Requests by path, with a \\ and a\nnewline in the help.
+# TYPE test_requests_total counter
+test_requests_total{path="/a\\b",method="GET"} 2
+test_requests_total{path="/quote\"d",method="POST"} 1
+test_requests_total{path="/two\nlines",method="GET"} 0.5
+# HELP test_duration_seconds How long it took.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{kind="fast",le="0.1"} 1
+test_duration_seconds_bucket{kind="fast",le="1"} 2
+test_duration_seconds_bucket{kind="fast",le="+Inf"} 3
+test_duration_seconds_sum{kind="fast"} 5.55
+test_duration_seconds_count{kind="fast"} 3
+# HELP test_open Something open.
+# TYPE test_open gauge
+test_open 3
+`
+
+func TestWriteTo(t *testing.T) {
+	collectorsLock.Lock()
+	previous := collectors
+	collectors = nil
+	collectorsLock.Unlock()
+
+	defer func() {
+		collectorsLock.Lock()
+		collectors = previous
+		collectorsLock.Unlock()
+	}()
+
+	requests := NewCounterVec("test_requests_total", "Requests by path, with a \\ and a\nnewline in the help.", "path", "method")
+	requests.Inc(`/a\b`, "GET")
+	requests.Inc(`/a\b`, "GET")
+	requests.Inc(`/quote"d`, "POST")
+	requests.Add(0.5, "/two\nlines", "GET")
+
+	// Given out of order, the buckets are sorted
+	duration := NewHistogramVec("test_duration_seconds", "How long it took.", []float64{1, 0.1}, "kind")
+	duration.Observe(0.05, "fast")
+	duration.Observe(0.5, "fast")
+	duration.Observe(5, "fast")
+
+	Register(duration)
+	Register(CollectorFunc(func() []*Family {
+		return []*Family{Gauge("test_open", "Something open.", 3)}
+	}))
+
+	var b bytes.Buffer
+	if err := WriteTo(&b); err != nil {
+		t.Fatalf("unable to write the metrics: %v", err)
+	}
+
+	if b.String() != expectedExposition {
+		t.Errorf("expected:\n%s\ngot:\n%s", expectedExposition, b.String())
+	}
+}
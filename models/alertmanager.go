@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+//AlertmanagerWebhook is the payload Alertmanager posts to its webhook receivers
+type AlertmanagerWebhook struct {
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+}
+
+//AlertmanagerAlert is a single alert of an Alertmanager webhook
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+const (
+	//AlertmanagerStatusFiring - At least one of the alerts is firing
+	AlertmanagerStatusFiring = "firing"
+	//AlertmanagerStatusResolved - All of the alerts are resolved
+	AlertmanagerStatusResolved = "resolved"
+)
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+)
+
+//APIToken is a token for the api which is limited to its scopes
+type APIToken struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`              // Start of the token, to recognize it without knowing all of it
+	Hash       string     `json:"hash,omitempty"`      // Only the hash of the token is stored
+	Token      string     `json:"token,omitempty"`     // Only returned once, when the token is created
+	Scopes     []Scope    `json:"scopes"`
+	CreatedBy  string     `json:"createdBy,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"` // Never expires when empty
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+//IsActive tells whether the token can still be used
+func (t *APIToken) IsActive(now time.Time) bool {
+	if t.RevokedAt != nil {
+		return false
+	}
+
+	return t.ExpiresAt == nil || now.Before(*t.ExpiresAt)
+}
+
+//Scope is something an api token is allowed to do
+type Scope string
+
+const (
+	//ScopeReadPrivate - Read everything which isn't public, like the services and incidents through the authorized api
+	ScopeReadPrivate Scope = "read-private"
+	//ScopeIncidentsWrite - Create, update and delete incidents
+	ScopeIncidentsWrite Scope = "incidents:write"
+	//ScopeMaintenanceWrite - Create, update and delete scheduled maintenance
+	ScopeMaintenanceWrite Scope = "maintenance:write"
+	//ScopeServicesAdmin - Create and update services and regions
+	ScopeServicesAdmin Scope = "services:admin"
+	//ScopeSnapshot - Download snapshots of the database
+	ScopeSnapshot Scope = "snapshot"
+	//ScopeAdmin - Everything, including the tokens, webhooks, subscribers and config
+	ScopeAdmin Scope = "admin"
+)
+
+//Scopes holds all of the valid scopes
+var Scopes = map[string]Scope{
+	"read-private":      ScopeReadPrivate,
+	"incidents:write":   ScopeIncidentsWrite,
+	"maintenance:write": ScopeMaintenanceWrite,
+	"services:admin":    ScopeServicesAdmin,
+	"snapshot":          ScopeSnapshot,
+	"admin":             ScopeAdmin,
+}
+
+//HasScope checks whether the scopes allow what the scope is needed for, admin allows everything
+func HasScope(scopes []Scope, scope Scope) bool {
+	for _, s := range scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+//AuditEntry records a change made through the api, with the target as it was before and after
+type AuditEntry struct {
+	ID         int             `json:"id"`
+	Time       time.Time       `json:"time"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	Method     string          `json:"method"`
+	Path       string          `json:"path"`
+	Status     int             `json:"status"`
+	TargetType string          `json:"targetType"`
+	TargetID   int             `json:"targetId,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty"`
+	After      json.RawMessage `json:"after,omitempty"`
+}
+
+//AuditFilter narrows down the audit entries, empty fields match everything
+type AuditFilter struct {
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   int
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
+
+//Matches checks whether the entry passes the filter, the limit isn't taken into account
+func (f *AuditFilter) Matches(entry *AuditEntry) bool {
+	switch {
+	case f.Actor != "" && entry.Actor != f.Actor:
+		return false
+	case f.Action != "" && entry.Action != f.Action:
+		return false
+	case f.TargetType != "" && entry.TargetType != f.TargetType:
+		return false
+	case f.TargetID != 0 && entry.TargetID != f.TargetID:
+		return false
+	case !f.From.IsZero() && entry.Time.Before(f.From):
+		return false
+	case !f.To.IsZero() && !entry.Time.Before(f.To):
+		return false
+	}
+
+	return true
+}
+
+//Types of the records changes are audited for
+const (
+	AuditTargetService              = "service"
+	AuditTargetRegion               = "region"
+	AuditTargetIncident             = "incident"
+	AuditTargetIncidentTemplate     = "incident_template"
+	AuditTargetScheduledMaintenance = "scheduled_maintenance"
+	AuditTargetRecurringMaintenance = "recurring_maintenance"
+	AuditTargetSubscriber           = "subscriber"
+	AuditTargetWebhook              = "webhook"
+	AuditTargetAPIToken             = "token"
+)
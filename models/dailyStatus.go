@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+//DailyStatus holds the worst status a service or region had on a day, along with the incidents of that day
+type DailyStatus struct {
+	Day       time.Time              `json:"day"`
+	Status    ServiceAndRegionStatus `json:"status"`
+	NoData    bool                   `json:"noData"` // The day is from before the status history was recorded
+	Incidents []*Incident            `json:"incidents"`
+}
+
+//DailyStatusHistory holds the daily statuses of a service or region, oldest day first
+type DailyStatusHistory struct {
+	ServiceName string                `json:"serviceName"`
+	RegionCode  string                `json:"regionCode,omitempty"`
+	RegionName  string                `json:"regionName,omitempty"`
+	Uptime      float64               `json:"uptime"`
+	Days        []DailyStatus         `json:"days"`
+	Regions     []*DailyStatusHistory `json:"regions,omitempty"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+)
+
+//EventType represents something that happened to an incident, maintenance or service
+type EventType string
+
+func (et EventType) String() string {
+	return string(et)
+}
+
+const (
+	//EventIncidentCreated - An incident was created
+	EventIncidentCreated EventType = "incident.created"
+	//EventIncidentUpdated - An update was posted to an incident
+	EventIncidentUpdated EventType = "incident.updated"
+	//EventIncidentResolved - An incident was resolved
+	EventIncidentResolved EventType = "incident.resolved"
+	//EventIncidentPostmortemPublished - The postmortem of a resolved incident was published
+	EventIncidentPostmortemPublished EventType = "incident.postmortem_published"
+	//EventMaintenanceCreated - A maintenance was scheduled
+	EventMaintenanceCreated EventType = "maintenance.created"
+	//EventMaintenanceStarted - A scheduled maintenance started
+	EventMaintenanceStarted EventType = "maintenance.started"
+	//EventMaintenanceUpdated - An update was posted to a scheduled maintenance which is in progress
+	EventMaintenanceUpdated EventType = "maintenance.updated"
+	//EventMaintenanceCompleted - A scheduled maintenance was completed
+	EventMaintenanceCompleted EventType = "maintenance.completed"
+	//EventMaintenanceExtended - The planned end of a scheduled maintenance was pushed back
+	EventMaintenanceExtended EventType = "maintenance.extended"
+	//EventMaintenanceCancelled - A scheduled maintenance was cancelled
+	EventMaintenanceCancelled EventType = "maintenance.cancelled"
+	//EventServiceStatusChanged - The status of a service or one of its regions changed
+	EventServiceStatusChanged EventType = "service.status_changed"
+)
+
+//EventTypes holds all of the valid event types
+var EventTypes = map[string]EventType{
+	EventIncidentCreated.String():             EventIncidentCreated,
+	EventIncidentUpdated.String():             EventIncidentUpdated,
+	EventIncidentResolved.String():            EventIncidentResolved,
+	EventIncidentPostmortemPublished.String(): EventIncidentPostmortemPublished,
+	EventMaintenanceCreated.String():          EventMaintenanceCreated,
+	EventMaintenanceStarted.String():          EventMaintenanceStarted,
+	EventMaintenanceUpdated.String():          EventMaintenanceUpdated,
+	EventMaintenanceCompleted.String():        EventMaintenanceCompleted,
+	EventMaintenanceExtended.String():         EventMaintenanceExtended,
+	EventMaintenanceCancelled.String():        EventMaintenanceCancelled,
+	EventServiceStatusChanged.String():        EventServiceStatusChanged,
+}
+
+//Event holds the information about something which happened, only the fields relevant to the type are set
+type Event struct {
+	ID                   string                `json:"id"`
+	Type                 EventType             `json:"type"`
+	Time                 time.Time             `json:"time"`
+	Incident             *Incident             `json:"incident,omitempty"`
+	ScheduledMaintenance *ScheduledMaintenance `json:"scheduledMaintenance,omitempty"`
+	Update               *StatusUpdate         `json:"update,omitempty"`
+	StatusChange         *ServiceStatusChange  `json:"statusChange,omitempty"`
+}
+
+//ServiceStatusChange holds the information about a service or region changing status
+type ServiceStatusChange struct {
+	ServiceName    string                 `json:"serviceName"`
+	RegionCode     string                 `json:"regionCode,omitempty"`
+	RegionName     string                 `json:"regionName,omitempty"`
+	PreviousStatus ServiceAndRegionStatus `json:"previousStatus"`
+	Status         ServiceAndRegionStatus `json:"status"`
+}
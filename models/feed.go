@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+//Feed holds the latest incident and scheduled maintenance updates, ready to be rendered as RSS or Atom
+type Feed struct {
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Link        string      `json:"link"`
+	Updated     time.Time   `json:"updated"`
+	Entries     []FeedEntry `json:"entries"`
+}
+
+//FeedEntry is a single status update in the feed
+type FeedEntry struct {
+	GUID      string    `json:"guid"`
+	Title     string    `json:"title"`
+	Link      string    `json:"link"`
+	Content   string    `json:"content"`
+	Published time.Time `json:"published"`
+}
@@ -15,8 +15,13 @@ type Incident struct {
 	UpdatedAt       time.Time           `json:"updatedAt"`
 	Maintenance     IncidentMaintenance `json:"maintenance"`   // Deprecated
 	IsMaintenance   bool                `json:"isMaintenance"` // Deprecated
-	OriginalTweetID int64               `json:"originalTweetId"`
-	LatestTweetID   int64               `json:"latestTweetId"`
+	Notifications   NotificationResults `json:"notifications,omitempty"`
+	OriginalTweetID int64               `json:"originalTweetId,omitempty"` // Deprecated: moved to Notifications
+	LatestTweetID   int64               `json:"latestTweetId,omitempty"`   // Deprecated: moved to Notifications
+	Source          string              `json:"source,omitempty"`          // What opened the incident when it wasn't a person, like a probe
+	Alerts          []string            `json:"alerts,omitempty"`          // Fingerprints of the alerts which are part of the incident
+	PostmortemURL   string              `json:"postmortemUrl,omitempty"`   // Where the write-up of the incident is published
+	Postmortem      *Postmortem         `json:"postmortem,omitempty"`      // The write-up of the incident, once it's resolved
 }
 
 //IncidentMaintenance contains the data about a scheduled maintenance.
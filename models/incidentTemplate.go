@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+//IncidentTemplate is the prepared text of an incident for a common failure scenario. Its title, message and the names
+//and regions of its services are text/templates rendered with the variables given when it's used, like {{.Service}}
+//and {{.Region}}.
+type IncidentTemplate struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+
+	Title   string `json:"title"`
+	Message string `json:"message"`
+
+	// Status is the status the incident or update gets, Investigating by default
+	Status   IncidentStatus  `json:"status"`
+	Services []ServiceUpdate `json:"services,omitempty"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+//IncidentTemplateRender holds the variables a template gets rendered with, "region" sets {{.Region}}
+type IncidentTemplateRender struct {
+	Variables map[string]string `json:"variables"`
+}
@@ -6,10 +6,34 @@ import (
 
 //StatusUpdate holds an update for an incident or scheduled maintenance
 type StatusUpdate struct {
-	ID       int             `json:"id"`
-	Time     time.Time       `json:"time"`
+	ID        int                    `json:"id"`
+	Time      time.Time              `json:"time"`
+	Status    IncidentStatus         `json:"status"`
+	Message   string                 `json:"message"`
+	Services  []ServiceUpdate        `json:"services,omitempty"`
+	Regions   []RegionUpdate         `json:"regions,omitempty"`
+	Revisions []StatusUpdateRevision `json:"revisions,omitempty"` // What the update said before each edit, oldest first
+}
+
+//StatusUpdateRevision is the content a status update had before it was edited
+type StatusUpdateRevision struct {
+	EditedAt time.Time       `json:"editedAt"`
+	EditedBy string          `json:"editedBy"`
 	Status   IncidentStatus  `json:"status"`
 	Message  string          `json:"message"`
 	Services []ServiceUpdate `json:"services,omitempty"`
-	Regions  []RegionUpdate  `json:"regions,omitempty"`
+}
+
+//Edited tells whether the update was changed after it was published
+func (u *StatusUpdate) Edited() bool {
+	return len(u.Revisions) > 0
+}
+
+//LastEditedAt gives when the update was last changed, it's zero when it never was
+func (u *StatusUpdate) LastEditedAt() time.Time {
+	if len(u.Revisions) == 0 {
+		return time.Time{}
+	}
+
+	return u.Revisions[len(u.Revisions)-1].EditedAt
 }
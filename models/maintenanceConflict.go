@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+)
+
+//MaintenanceConflictType is what a scheduled maintenance conflicts with
+type MaintenanceConflictType string
+
+const (
+	//MaintenanceConflictScheduledMaintenance is another maintenance planned at the same time
+	MaintenanceConflictScheduledMaintenance MaintenanceConflictType = "scheduledMaintenance"
+	//MaintenanceConflictIncident is an incident which is still open
+	MaintenanceConflictIncident MaintenanceConflictType = "incident"
+)
+
+//MaintenanceConflict is a maintenance or an open incident affecting the same services or regions as a scheduled maintenance
+type MaintenanceConflict struct {
+	Type  MaintenanceConflictType `json:"type"`
+	ID    int                     `json:"id"`
+	Title string                  `json:"title"`
+
+	// Services both affect, like "Push Gateway" or "Push Gateway (eu)" when only some regions overlap
+	Services []string `json:"services"`
+
+	// Start and End are the window of the maintenance, incidents only have a start as they are open
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
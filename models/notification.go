@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+)
+
+//NotificationResult holds what a notifier reported back after being told about an incident or scheduled maintenance
+type NotificationResult struct {
+	OriginalID string    `json:"originalId,omitempty"`
+	LatestID   string    `json:"latestId,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+//NotificationResults holds the results keyed by the name of the notifier
+type NotificationResults map[string]*NotificationResult
+
+//Record stores the outcome of a notification, the first id returned becomes the original one
+func (r NotificationResults) Record(name string, id string, err error) {
+	result, ok := r[name]
+	if !ok {
+		result = &NotificationResult{}
+		r[name] = result
+	}
+
+	result.UpdatedAt = time.Now()
+
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+
+	result.Error = ""
+
+	if id == "" {
+		return
+	}
+
+	if result.OriginalID == "" {
+		result.OriginalID = id
+	}
+
+	result.LatestID = id
+}
+
+//OriginalID gets the id of the first thing the notifier created, empty when there is none
+func (r NotificationResults) OriginalID(name string) string {
+	if result, ok := r[name]; ok {
+		return result.OriginalID
+	}
+
+	return ""
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+)
+
+//Postmortem is the write-up of a resolved incident, it's shown on the page of the incident once published
+type Postmortem struct {
+	Summary   string `json:"summary"`
+	Body      string `json:"body"` // Markdown
+	RootCause string `json:"rootCause"`
+
+	// ImpactStart and ImpactEnd are when the customers were affected, which can differ from the incident times
+	ImpactStart time.Time `json:"impactStart"`
+	ImpactEnd   time.Time `json:"impactEnd"`
+
+	ActionItems []PostmortemActionItem `json:"actionItems,omitempty"`
+
+	Published   bool      `json:"published"`
+	PublishedAt time.Time `json:"publishedAt"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+//PostmortemActionItem is something which is done so the incident doesn't happen again
+type PostmortemActionItem struct {
+	Description string `json:"description"`
+	Owner       string `json:"owner,omitempty"`
+	Done        bool   `json:"done"`
+}
+
+//ImpactDuration gives how long the customers were affected
+func (p *Postmortem) ImpactDuration() time.Duration {
+	return p.ImpactEnd.Sub(p.ImpactStart)
+}
+
+//ImpactDurationText gives ImpactDuration the way it's shown on the status page, like "1 h 30 min"
+func (p *Postmortem) ImpactDurationText() string {
+	return durationText(p.ImpactDuration())
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+)
+
+//RecurringMaintenance describes maintenance which happens on a schedule, like a weekly patch window.
+//The scheduled maintenance of each occurrence is created ahead of time from it.
+type RecurringMaintenance struct {
+	ID          int             `json:"id"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Services    []ServiceUpdate `json:"services,omitempty"`
+
+	// Rule is an RRULE (RFC 5545) like "FREQ=WEEKLY;BYDAY=TU", supporting FREQ, INTERVAL, BYDAY, COUNT and UNTIL
+	Rule string `json:"rule"`
+	// Start is the first occurrence, the others happen at the same time of day
+	Start time.Time `json:"start"`
+	// DurationMinutes is how long each occurrence is planned to take
+	DurationMinutes int `json:"durationMinutes"`
+
+	Enabled bool `json:"enabled"`
+
+	// Exceptions skip or change single occurrences
+	Exceptions []RecurringMaintenanceException `json:"exceptions,omitempty"`
+	// CreatedUntil is how far ahead the scheduled maintenance of the occurrences was created
+	CreatedUntil time.Time `json:"createdUntil"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+//RecurringMaintenanceException skips or changes the occurrence which was planned to start at Occurrence
+type RecurringMaintenanceException struct {
+	Occurrence   time.Time `json:"occurrence"`
+	Skip         bool      `json:"skip"`
+	Title        string    `json:"title,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	PlannedStart time.Time `json:"plannedStart"`
+	PlannedEnd   time.Time `json:"plannedEnd"`
+}
+
+//RecurringMaintenanceOccurrence is one occurrence of a recurring maintenance, with its exception applied
+type RecurringMaintenanceOccurrence struct {
+	Occurrence   time.Time `json:"occurrence"`
+	Skipped      bool      `json:"skipped"`
+	Title        string    `json:"title"`
+	PlannedStart time.Time `json:"plannedStart"`
+	PlannedEnd   time.Time `json:"plannedEnd"`
+	// ScheduledMaintenanceID is set once the scheduled maintenance of the occurrence was created
+	ScheduledMaintenanceID int `json:"scheduledMaintenanceId,omitempty"`
+}
+
+//Exception gives the exception of the occurrence, nil when it has none
+func (r *RecurringMaintenance) Exception(occurrence time.Time) *RecurringMaintenanceException {
+	for i := range r.Exceptions {
+		if r.Exceptions[i].Occurrence.Equal(occurrence) {
+			return &r.Exceptions[i]
+		}
+	}
+
+	return nil
+}
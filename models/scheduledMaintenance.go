@@ -1,6 +1,7 @@
 package models
 
 import (
+	"fmt"
 	"time"
 )
 
@@ -12,14 +13,64 @@ type ScheduledMaintenance struct {
 	Services    []ServiceUpdate `json:"services,omitempty"`
 	Updates     []*StatusUpdate `json:"updates"`
 
-	OriginalTweetID int64 `json:"originalTweetId"`
-	LatestTweetID   int64 `json:"latestTweetId"`
+	Notifications NotificationResults `json:"notifications,omitempty"`
 
-	Completed bool `json:"completed"`
+	OriginalTweetID int64 `json:"originalTweetId,omitempty"` // Deprecated: moved to Notifications
+	LatestTweetID   int64 `json:"latestTweetId,omitempty"`   // Deprecated: moved to Notifications
+
+	// Started is set by the scheduler at the planned start or when started early, the updates posted
+	// before then don't put the services under maintenance
+	Started     bool      `json:"started"`
+	StartedAt   time.Time `json:"startedAt"`
+	Completed   bool      `json:"completed"`
+	CompletedAt time.Time `json:"completedAt"`
+	Cancelled   bool      `json:"cancelled"`
+	CancelledAt time.Time `json:"cancelledAt"`
+
+	// Sequence is bumped whenever the maintenance window changes, so calendars pick up the change
+	Sequence int `json:"sequence"`
 
 	PlannedStart time.Time `json:"plannedStart"`
 	PlannedEnd   time.Time `json:"plannedEnd"`
 
+	// OriginalPlannedEnd is the end it was planned with, set the first time the maintenance gets extended
+	OriginalPlannedEnd time.Time `json:"originalPlannedEnd"`
+
+	// RecurringMaintenanceID and Occurrence are set when it was created for an occurrence of a recurring maintenance
+	RecurringMaintenanceID int       `json:"recurringMaintenanceId,omitempty"`
+	Occurrence             time.Time `json:"occurrence"`
+
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
 }
+
+//ExtendedBy gives how much later than first planned the maintenance ends, zero when it wasn't extended
+func (m *ScheduledMaintenance) ExtendedBy() time.Duration {
+	if m.OriginalPlannedEnd.IsZero() {
+		return 0
+	}
+
+	return m.PlannedEnd.Sub(m.OriginalPlannedEnd)
+}
+
+//ExtendedByText gives ExtendedBy the way it's shown on the status page, like "1 h 30 min"
+func (m *ScheduledMaintenance) ExtendedByText() string {
+	return durationText(m.ExtendedBy())
+}
+
+//durationText gives the duration rounded to minutes the way it's shown on the status page, like "1 h 30 min"
+func durationText(d time.Duration) string {
+	d = d.Round(time.Minute)
+
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
+
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%d min", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%d h", hours)
+	default:
+		return fmt.Sprintf("%d h %d min", hours, minutes)
+	}
+}
@@ -7,16 +7,17 @@ import (
 
 //Service holds information about the service
 type Service struct {
-	ID          int                    `json:"id"`
-	Name        string                 `json:"name"`
-	Status      ServiceAndRegionStatus `json:"status"`
-	Description string                 `json:"description"`
-	Group       string                 `json:"group"`
-	Link        string                 `json:"link"`
-	Tags        []string               `json:"tags"`
-	Enabled     bool                   `json:"enabled"`
-	UpdatedAt   time.Time              `json:"updatedAt"`
-	Regions     []Region               `json:"regions"` // Not stored like this on DB, filled on-read when needed
+	ID             int                    `json:"id"`
+	Name           string                 `json:"name"`
+	Status         ServiceAndRegionStatus `json:"status"`
+	StatusOverride ServiceAndRegionStatus `json:"statusOverride,omitempty"` // Set by hand, counts like an open incident
+	Description    string                 `json:"description"`
+	Group          string                 `json:"group"`
+	Link           string                 `json:"link"`
+	Tags           []string               `json:"tags"`
+	Enabled        bool                   `json:"enabled"`
+	UpdatedAt      time.Time              `json:"updatedAt"`
+	Regions        []Region               `json:"regions"` // Not stored like this on DB, filled on-read when needed
 }
 
 //ServiceAndRegionStatus represents the status of a service
@@ -66,6 +67,30 @@ var ServiceStatuses = map[string]ServiceAndRegionStatus{
 	ServiceStatusUnknown.ToLower():              ServiceStatusUnknown,
 }
 
+//ServiceStatusSeverity ranks the statuses by how bad they are for the users of a service,
+//unlike ServiceStatusValues where scheduled maintenance and unknown rank above an outage
+var ServiceStatusSeverity = map[ServiceAndRegionStatus]int{
+	ServiceStatusUnknown:              0,
+	ServiceStatusNominal:              1,
+	ServiceStatusScheduledMaintenance: 2,
+	ServiceStatusDegraded:             3,
+	ServiceStatusPartialOutage:        4,
+	ServiceStatusOutage:               5,
+}
+
+//WorstServiceStatus returns the most severe of the provided statuses, Nominal when none are provided
+func WorstServiceStatus(statuses ...ServiceAndRegionStatus) ServiceAndRegionStatus {
+	worst := ServiceStatusNominal
+
+	for _, status := range statuses {
+		if ServiceStatusSeverity[status] > ServiceStatusSeverity[worst] {
+			worst = status
+		}
+	}
+
+	return worst
+}
+
 var ServiceStatusArray = []ServiceAndRegionStatus{
 	ServiceStatusNominal,
 	ServiceStatusDegraded,
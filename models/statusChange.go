@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+)
+
+//StatusChange is a single status transition of a service or one of its regions
+type StatusChange struct {
+	ID             int                    `json:"id"`
+	Time           time.Time              `json:"time"`
+	ServiceID      int                    `json:"serviceId"`
+	ServiceName    string                 `json:"serviceName"`
+	RegionID       int                    `json:"regionId,omitempty"`
+	RegionCode     string                 `json:"regionCode,omitempty"`
+	RegionName     string                 `json:"regionName,omitempty"`
+	PreviousStatus ServiceAndRegionStatus `json:"previousStatus"`
+	Status         ServiceAndRegionStatus `json:"status"`
+
+	// What caused the change and who made it
+	IncidentID             int    `json:"incidentId,omitempty"`
+	ScheduledMaintenanceID int    `json:"scheduledMaintenanceId,omitempty"`
+	Actor                  string `json:"actor,omitempty"`
+}
+
+//IsRegion tells whether the change is about a region rather than the service itself
+func (sc *StatusChange) IsRegion() bool {
+	return sc.RegionCode != ""
+}
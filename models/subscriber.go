@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+)
+
+//Subscriber holds the information about someone subscribed to email notifications
+type Subscriber struct {
+	ID              int                 `json:"id"`
+	Email           string              `json:"email"`
+	Services        []SubscribedService `json:"services"`
+	PendingServices []SubscribedService `json:"pendingServices"`
+	Confirmed       bool                `json:"confirmed"`
+	ConfirmedAt     time.Time           `json:"confirmedAt"`
+	CreatedAt       time.Time           `json:"createdAt"`
+	UpdatedAt       time.Time           `json:"updatedAt"`
+}
+
+//SubscribedService is a service, and optionally some of its regions, a subscriber wants notifications about.
+//When a subscriber has no services they receive notifications about everything.
+type SubscribedService struct {
+	Name    string   `json:"name"`
+	Regions []string `json:"regions,omitempty"`
+}
+
+//IsInterestedIn checks whether the subscriber wants to hear about changes to the provided services
+func (s *Subscriber) IsInterestedIn(services []ServiceUpdate) bool {
+	if len(s.Services) == 0 {
+		return true
+	}
+
+	for _, subscribed := range s.Services {
+		for _, affected := range services {
+			if subscribed.Name != affected.Name {
+				continue
+			}
+
+			// Either side not caring about regions means the whole service is involved
+			if len(subscribed.Regions) == 0 || len(affected.Regions) == 0 {
+				return true
+			}
+
+			for _, subscribedRegion := range subscribed.Regions {
+				for _, affectedRegion := range affected.Regions {
+					if subscribedRegion == affectedRegion {
+						return true
+					}
+				}
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+)
+
+//Uptime holds the availability of a service or region over a window of time
+type Uptime struct {
+	ServiceID          int                                `json:"serviceId"`
+	ServiceName        string                             `json:"serviceName"`
+	RegionCode         string                             `json:"regionCode,omitempty"`
+	RegionName         string                             `json:"regionName,omitempty"`
+	From               time.Time                          `json:"from"`
+	To                 time.Time                          `json:"to"`
+	RecordedFrom       time.Time                          `json:"recordedFrom"` // start of the status history within the window
+	Partial            bool                               `json:"partial"`      // whether the status history starts after the window does
+	ExcludeMaintenance bool                               `json:"excludeMaintenance"`
+	Percentage         float64                            `json:"percentage"`
+	Durations          map[ServiceAndRegionStatus]float64 `json:"durations"` // seconds spent in each status
+	Regions            []*Uptime                          `json:"regions,omitempty"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+//Webhook holds the information about an endpoint which receives events
+type Webhook struct {
+	ID        int         `json:"id"`
+	Name      string      `json:"name"`
+	URL       string      `json:"url"`
+	Secret    string      `json:"secret,omitempty"`
+	Events    []EventType `json:"events"` // Empty means all of the events
+	Enabled   bool        `json:"enabled"`
+	CreatedAt time.Time   `json:"createdAt"`
+	UpdatedAt time.Time   `json:"updatedAt"`
+}
+
+//WantsEvent checks whether the webhook should receive the event type
+func (w *Webhook) WantsEvent(eventType EventType) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+//WebhookDeliveryStatus represents the status of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	//WebhookDeliveryStatusPending - Waiting for the first or a retry attempt
+	WebhookDeliveryStatusPending WebhookDeliveryStatus = "pending"
+	//WebhookDeliveryStatusDelivered - The receiver accepted the event
+	WebhookDeliveryStatusDelivered WebhookDeliveryStatus = "delivered"
+	//WebhookDeliveryStatusFailed - All of the attempts failed, no more retries will happen
+	WebhookDeliveryStatusFailed WebhookDeliveryStatus = "failed"
+)
+
+//WebhookDelivery holds an event which has to be delivered to a webhook along with the attempts made
+type WebhookDelivery struct {
+	ID             int                   `json:"id"`
+	WebhookID      int                   `json:"webhookId"`
+	EventID        string                `json:"eventId"`
+	EventType      EventType             `json:"eventType"`
+	Payload        json.RawMessage       `json:"payload"`
+	Status         WebhookDeliveryStatus `json:"status"`
+	Attempts       int                   `json:"attempts"`
+	LastStatusCode int                   `json:"lastStatusCode,omitempty"`
+	LastError      string                `json:"lastError,omitempty"`
+	NextAttemptAt  time.Time             `json:"nextAttemptAt"`
+	DeliveredAt    time.Time             `json:"deliveredAt"`
+	CreatedAt      time.Time             `json:"createdAt"`
+	UpdatedAt      time.Time             `json:"updatedAt"`
+}
@@ -0,0 +1,149 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/gin-gonic/gin"
+)
+
+//AdminSessionCookie is the cookie holding the id of the admin ui session
+const AdminSessionCookie = "statuscentral_admin"
+
+//AdminLoginCookie is the cookie holding the random value the csrf token of the sign in form is derived from
+const AdminLoginCookie = "statuscentral_admin_login"
+
+//AdminCSRFKey is the context key under which the csrf token of the admin forms is stored
+const AdminCSRFKey = "adminCsrf"
+
+//AdminLoginCSRF protects the sign in form against login csrf, there is no session yet to derive its csrf token from.
+//The browser gets a random value in a cookie instead, and the form has to carry the csrf token derived from it.
+func AdminLoginCSRF(c *gin.Context) {
+	value, err := c.Cookie(AdminLoginCookie)
+	if err != nil {
+		value = ""
+	}
+
+	if c.Request.Method == http.MethodPost && (value == "" || subtle.ConstantTimeCompare([]byte(c.PostForm("csrf")), []byte(AdminCSRFToken(value))) != 1) {
+		AdminError(c, http.StatusForbidden, "The form has expired, please go back, reload the page and try again.")
+		c.Abort()
+		return
+	}
+
+	if value == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			AdminError(c, http.StatusInternalServerError, "The sign in form couldn't be created, please try again.")
+			c.Abort()
+			return
+		}
+
+		value = hex.EncodeToString(b)
+
+		c.SetSameSite(http.SameSiteStrictMode)
+		c.SetCookie(AdminLoginCookie, value, 0, "/admin/login", "", config.Config.HTTP.SecureCookies, true)
+	}
+
+	c.Set(AdminCSRFKey, AdminCSRFToken(value))
+
+	c.Next()
+}
+
+//IsAdminAuthorized checks the token of the admin session like IsAuthorized checks the header. Anyone
+//without a valid session is sent to the sign in page, and forms have to carry the csrf token of the session.
+func IsAdminAuthorized(c *gin.Context) {
+	session, err := c.Cookie(AdminSessionCookie)
+	if err != nil || session == "" {
+		c.Redirect(http.StatusSeeOther, "/admin/login")
+		c.Abort()
+		return
+	}
+
+	token, ok := core.GetAdminSessionToken(session)
+	if !ok {
+		ClearAdminSessionCookie(c)
+		c.Redirect(http.StatusSeeOther, "/admin/login")
+		c.Abort()
+		return
+	}
+
+	switch Authenticate(c, token) {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		core.DeleteAdminSession(session)
+		ClearAdminSessionCookie(c)
+		c.Redirect(http.StatusSeeOther, "/admin/login")
+		c.Abort()
+		return
+	default:
+		AdminError(c, http.StatusInternalServerError, "The session couldn't be checked, please try again.")
+		c.Abort()
+		return
+	}
+
+	csrf := AdminCSRFToken(session)
+	c.Set(AdminCSRFKey, csrf)
+
+	if c.Request.Method == http.MethodPost && subtle.ConstantTimeCompare([]byte(c.PostForm("csrf")), []byte(csrf)) != 1 {
+		AdminError(c, http.StatusForbidden, "The form has expired, please go back, reload the page and try again.")
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
+
+//SetAdminSessionCookie hands the id of the session to the browser
+func SetAdminSessionCookie(c *gin.Context, session string) {
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(AdminSessionCookie, session, int(core.AdminSessionDuration.Seconds()), "/admin", "", config.Config.HTTP.SecureCookies, true)
+}
+
+//ClearAdminSessionCookie removes the session cookie from the browser
+func ClearAdminSessionCookie(c *gin.Context) {
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(AdminSessionCookie, "", -1, "/admin", "", config.Config.HTTP.SecureCookies, true)
+}
+
+//AdminCSRFToken gives the csrf token of the session, it's derived from the session so nothing has to be stored
+func AdminCSRFToken(session string) string {
+	mac := hmac.New(sha256.New, []byte(session))
+	mac.Write([]byte("statuscentral admin csrf"))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+//AdminRequireScope is RequireScope for the admin ui, it shows an error page instead of responding with json
+func AdminRequireScope(scope models.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, _ := c.Value(ScopesKey).([]models.Scope)
+
+		if !models.HasScope(scopes, scope) {
+			AdminError(c, http.StatusForbidden, fmt.Sprintf("Your token doesn't have the %s scope this page requires.", scope))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+//AdminError renders the error page of the admin ui
+func AdminError(c *gin.Context, status int, message string) {
+	c.HTML(status, "adminError.tmpl", gin.H{
+		"owner":           config.Config.Website.Title,
+		"backgroundColor": config.Config.Website.HeaderBgColor,
+		"cacheBreaker":    config.Config.Website.CacheBreaker,
+		"logo":            "static/img/logo.svg",
+		"actor":           c.GetString(ActorKey),
+		"message":         message,
+	})
+}
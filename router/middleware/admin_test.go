@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/gin-gonic/gin"
+)
+
+func newTestAdminRouter(handlers ...gin.HandlerFunc) *gin.Engine {
+	router := gin.New()
+	router.SetHTMLTemplate(template.Must(template.New("adminError.tmpl").Parse("{{.message}}")))
+
+	handlers = append(handlers, func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString(AdminCSRFKey))
+	})
+
+	router.GET("/admin/page", handlers...)
+	router.POST("/admin/page", handlers...)
+
+	return router
+}
+
+func adminRequest(method string, csrf string, cookies ...*http.Cookie) *http.Request {
+	var req *http.Request
+	if method == http.MethodPost {
+		req = httptest.NewRequest(method, "/admin/page", strings.NewReader(url.Values{"csrf": {csrf}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	} else {
+		req = httptest.NewRequest(method, "/admin/page", nil)
+	}
+
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	return req
+}
+
+func TestIsAdminAuthorizedSessionLifecycle(t *testing.T) {
+	router := newTestAdminRouter(IsAdminAuthorized)
+
+	session, err := core.CreateAdminSession(testAuthToken)
+	if err != nil {
+		t.Fatalf("unable to create the session: %v", err)
+	}
+
+	cookie := &http.Cookie{Name: AdminSessionCookie, Value: session}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest(http.MethodGet, "", cookie))
+
+	if w.Code != http.StatusOK || w.Body.String() != AdminCSRFToken(session) {
+		t.Fatalf("expected the page with the csrf token of the session, got %d %q", w.Code, w.Body.String())
+	}
+
+	// Signing out ends the session, the cookie alone doesn't get back in
+	core.DeleteAdminSession(session)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest(http.MethodGet, "", cookie))
+
+	if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/admin/login" {
+		t.Errorf("expected to be sent to sign in after signing out, got %d to %q", w.Code, w.Header().Get("Location"))
+	}
+
+	if !strings.Contains(w.Header().Get("Set-Cookie"), AdminSessionCookie+"=;") {
+		t.Errorf("expected the session cookie to be cleared, got %q", w.Header().Get("Set-Cookie"))
+	}
+}
+
+func TestIsAdminAuthorizedRejects(t *testing.T) {
+	router := newTestAdminRouter(IsAdminAuthorized)
+
+	// The token of the session is no longer valid, like a revoked api token
+	revoked, err := core.CreateAdminSession("not-a-valid-token")
+	if err != nil {
+		t.Fatalf("unable to create the session: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		cookie *http.Cookie
+	}{
+		{name: "no session", cookie: nil},
+		{name: "unknown session", cookie: &http.Cookie{Name: AdminSessionCookie, Value: "unknown"}},
+		{name: "token no longer valid", cookie: &http.Cookie{Name: AdminSessionCookie, Value: revoked}},
+	}
+
+	for _, test := range tests {
+		var cookies []*http.Cookie
+		if test.cookie != nil {
+			cookies = append(cookies, test.cookie)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, adminRequest(http.MethodGet, "", cookies...))
+
+		if w.Code != http.StatusSeeOther || w.Header().Get("Location") != "/admin/login" {
+			t.Errorf("%s: expected to be sent to sign in, got %d to %q", test.name, w.Code, w.Header().Get("Location"))
+		}
+	}
+
+	if _, ok := core.GetAdminSessionToken(revoked); ok {
+		t.Error("expected the session of the invalid token to be removed")
+	}
+}
+
+func TestIsAdminAuthorizedChecksTheCSRFTokenOfForms(t *testing.T) {
+	router := newTestAdminRouter(IsAdminAuthorized)
+
+	session, err := core.CreateAdminSession(testAuthToken)
+	if err != nil {
+		t.Fatalf("unable to create the session: %v", err)
+	}
+	defer core.DeleteAdminSession(session)
+
+	other, err := core.CreateAdminSession(testAuthToken)
+	if err != nil {
+		t.Fatalf("unable to create the session: %v", err)
+	}
+	defer core.DeleteAdminSession(other)
+
+	cookie := &http.Cookie{Name: AdminSessionCookie, Value: session}
+
+	tests := []struct {
+		name   string
+		csrf   string
+		status int
+	}{
+		{name: "csrf token of the session", csrf: AdminCSRFToken(session), status: http.StatusOK},
+		{name: "no csrf token", csrf: "", status: http.StatusForbidden},
+		{name: "csrf token of another session", csrf: AdminCSRFToken(other), status: http.StatusForbidden},
+		{name: "session id as the csrf token", csrf: session, status: http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, adminRequest(http.MethodPost, test.csrf, cookie))
+
+		if w.Code != test.status {
+			t.Errorf("%s: expected the status %d, got %d", test.name, test.status, w.Code)
+		}
+	}
+}
+
+func TestAdminLoginCSRF(t *testing.T) {
+	router := newTestAdminRouter(AdminLoginCSRF)
+
+	// Showing the form hands out the cookie the csrf token is derived from
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, adminRequest(http.MethodGet, ""))
+
+	var login *http.Cookie
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == AdminLoginCookie {
+			login = cookie
+		}
+	}
+
+	if w.Code != http.StatusOK || login == nil || login.Value == "" {
+		t.Fatalf("expected the form with the login cookie, got %d and %v", w.Code, login)
+	}
+
+	if !login.HttpOnly || login.SameSite != http.SameSiteStrictMode || login.Path != "/admin/login" {
+		t.Errorf("expected an http only, strict same site cookie for the sign in page, got %+v", login)
+	}
+
+	if w.Body.String() != AdminCSRFToken(login.Value) {
+		t.Errorf("expected the form to carry the csrf token of the cookie, got %q", w.Body.String())
+	}
+
+	tests := []struct {
+		name   string
+		cookie *http.Cookie
+		csrf   string
+		status int
+	}{
+		{name: "csrf token of the cookie", cookie: login, csrf: AdminCSRFToken(login.Value), status: http.StatusOK},
+		{name: "no cookie", cookie: nil, csrf: AdminCSRFToken(login.Value), status: http.StatusForbidden},
+		{name: "no csrf token", cookie: login, csrf: "", status: http.StatusForbidden},
+		{name: "csrf token of another cookie", cookie: login, csrf: AdminCSRFToken("other"), status: http.StatusForbidden},
+		{name: "empty cookie", cookie: &http.Cookie{Name: AdminLoginCookie, Value: ""}, csrf: AdminCSRFToken(""), status: http.StatusForbidden},
+	}
+
+	for _, test := range tests {
+		var cookies []*http.Cookie
+		if test.cookie != nil {
+			cookies = append(cookies, &http.Cookie{Name: test.cookie.Name, Value: test.cookie.Value})
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, adminRequest(http.MethodPost, test.csrf, cookies...))
+
+		if w.Code != test.status {
+			t.Errorf("%s: expected the status %d, got %d", test.name, test.status, w.Code)
+		}
+	}
+}
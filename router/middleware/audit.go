@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/gin-gonic/gin"
+)
+
+//AuditTargetIDKey is the context key handlers can store the id of the record they created under, when it isn't in the response
+const AuditTargetIDKey = "auditTargetId"
+
+// anonymousActor is who the changes made without authorization are recorded for
+const anonymousActor = "anonymous"
+
+//AuditSkipKey is the context key handlers set when the request didn't change anything, like a preview
+const AuditSkipKey = "auditSkip"
+
+// auditResponseWriter keeps a copy of the response, so the id of created records can be found
+type auditResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *auditResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *auditResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+//Audit records the change made by the request in the audit log, with the target as it was before and after.
+//The target is the record of the id param, or the one whose id is in the response when there's no param.
+func Audit(targetType, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		targetID, _ := strconv.Atoi(c.Param("id"))
+
+		var before json.RawMessage
+		if targetID != 0 {
+			snapshot, err := core.GetAuditSnapshot(targetType, targetID)
+			if err != nil {
+				log.Println("Error while getting the audit snapshot:", err)
+			}
+
+			before = snapshot
+		}
+
+		writer := &auditResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+
+		status := writer.Status()
+		if status >= http.StatusBadRequest || c.GetBool(AuditSkipKey) {
+			return
+		}
+
+		if targetID == 0 {
+			targetID = c.GetInt(AuditTargetIDKey)
+		}
+
+		if targetID == 0 {
+			var created struct {
+				ID int `json:"id"`
+			}
+
+			_ = json.Unmarshal(writer.body.Bytes(), &created)
+			targetID = created.ID
+		}
+
+		// Nothing was changed, like an integration notification which needed no incident
+		if targetID == 0 && status == http.StatusNoContent {
+			return
+		}
+
+		var after json.RawMessage
+		if targetID != 0 {
+			snapshot, err := core.GetAuditSnapshot(targetType, targetID)
+			if err != nil {
+				log.Println("Error while getting the audit snapshot:", err)
+			}
+
+			after = snapshot
+		}
+
+		// Only the public subscription form is audited without anyone signed in
+		actor := c.GetString(ActorKey)
+		if actor == "" {
+			actor = anonymousActor
+		}
+
+		entry := &models.AuditEntry{
+			Actor:      actor,
+			Action:     action,
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			Status:     status,
+			TargetType: targetType,
+			TargetID:   targetID,
+			Before:     before,
+			After:      after,
+		}
+
+		if err := core.RecordAudit(entry); err != nil {
+			log.Println("Error while recording the audit entry:", err)
+		}
+	}
+}
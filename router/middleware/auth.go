@@ -1,21 +1,116 @@
 package middleware
 
 import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"strings"
 
 	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/RocketChat/statuscentral/models"
 	"github.com/gin-gonic/gin"
 )
 
-//IsAuthorized checks to ensure the request can be made
+//ActorKey is the context key under which the identity of the authorized caller is stored
+const ActorKey = "actor"
+
+//ScopesKey is the context key under which the scopes of the authorized caller are stored
+const ScopesKey = "scopes"
+
+//IsAuthorized checks to ensure the request can be made, either with an api token, an oidc access token or the token of the config
 func IsAuthorized(c *gin.Context) {
-	token := c.GetHeader("Authorization")
+	switch Authenticate(c, c.GetHeader("Authorization")) {
+	case http.StatusOK:
+		c.Next()
+	case http.StatusUnauthorized:
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+	default:
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal error"})
+	}
+}
+
+//Authenticate stores the actor and scopes of the token in the context. The status is ok when the token
+//is valid, unauthorized when it isn't and internal server error when it couldn't be checked.
+func Authenticate(c *gin.Context, token string) int {
 	validToken := config.Config.AuthToken
 
-	if !(len(validToken) > 0 && token == validToken) {
+	// The token of the config is only meant to bootstrap, it can do everything
+	if len(validToken) > 0 && subtle.ConstantTimeCompare([]byte(token), []byte(validToken)) == 1 {
+		c.Set(ActorKey, "authToken")
+		c.Set(ScopesKey, []models.Scope{models.ScopeAdmin})
+
+		return http.StatusOK
+	}
+
+	token = strings.TrimPrefix(token, "Bearer ")
+
+	if core.IsOIDCToken(token) {
+		identity, err := core.AuthenticateOIDCToken(token)
+		if errors.Is(err, core.ErrInvalidOIDCToken) {
+			log.Println("Rejected oidc token:", err)
+			return http.StatusUnauthorized
+		}
+
+		if err != nil {
+			log.Println("Error while authenticating the oidc token:", err)
+			return http.StatusInternalServerError
+		}
+
+		c.Set(ActorKey, "oidc:"+identity.Subject)
+		c.Set(ScopesKey, identity.Scopes)
+
+		return http.StatusOK
+	}
+
+	apiToken, err := core.AuthenticateAPIToken(token)
+	if err != nil {
+		log.Println("Error while authenticating the api token:", err)
+		return http.StatusInternalServerError
+	}
+
+	if apiToken == nil {
+		return http.StatusUnauthorized
+	}
+
+	c.Set(ActorKey, fmt.Sprintf("token:%d", apiToken.ID))
+	c.Set(ScopesKey, apiToken.Scopes)
+
+	return http.StatusOK
+}
+
+//RequireScope only lets the request through when the caller has the scope, it has to come after IsAuthorized
+func RequireScope(scope models.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, _ := c.Value(ScopesKey).([]models.Scope)
+
+		if !models.HasScope(scopes, scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden", "details": fmt.Sprintf("the %s scope is required", scope)})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+//IsAlertmanagerAuthorized checks the request carries the token of the alertmanager integration, either as is or as a bearer token
+func IsAlertmanagerAuthorized(c *gin.Context) {
+	if !config.Config.Alertmanager.Enabled {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "alertmanager integration is not enabled"})
+		return
+	}
+
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	validToken := config.Config.Alertmanager.Token
+
+	if subtle.ConstantTimeCompare([]byte(token), []byte(validToken)) != 1 {
 		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
 		return
 	}
 
+	c.Set(ActorKey, "alertmanager")
+
 	c.Next()
 }
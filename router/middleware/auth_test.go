@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/models"
+	"github.com/gin-gonic/gin"
+)
+
+const testAuthToken = "config-token-0123456789"
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+
+	configFile, err := ioutil.TempFile("", "statuscentral-*.yaml")
+	if err != nil {
+		panic(err)
+	}
+
+	// The tests only use the config token and what is kept in memory, nothing is stored
+	if _, err := configFile.WriteString("dataPath: " + os.TempDir() + "/\nauthToken: " + testAuthToken + "\n"); err != nil {
+		panic(err)
+	}
+
+	configFile.Close()
+
+	err = config.Load(configFile.Name())
+	os.Remove(configFile.Name())
+
+	if err != nil {
+		panic(err)
+	}
+
+	os.Exit(m.Run())
+}
+
+func TestAuthenticateWithTheConfigToken(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{name: "config token", token: testAuthToken, status: http.StatusOK},
+		{name: "other token of the same length", token: "config-token-9876543210", status: http.StatusUnauthorized},
+		{name: "start of the config token", token: testAuthToken[:10], status: http.StatusUnauthorized},
+		{name: "config token and more", token: testAuthToken + "0", status: http.StatusUnauthorized},
+		{name: "no token", token: "", status: http.StatusUnauthorized},
+	}
+
+	for _, test := range tests {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+		if status := Authenticate(c, test.token); status != test.status {
+			t.Errorf("%s: expected the status %d, got %d", test.name, test.status, status)
+			continue
+		}
+
+		if test.status != http.StatusOK {
+			if _, ok := c.Get(ActorKey); ok {
+				t.Errorf("%s: expected no actor", test.name)
+			}
+
+			continue
+		}
+
+		scopes, _ := c.Value(ScopesKey).([]models.Scope)
+		if c.GetString(ActorKey) != "authToken" || !models.HasScope(scopes, models.ScopeAdmin) {
+			t.Errorf("%s: expected the authToken actor with the admin scope, got %q with %v", test.name, c.GetString(ActorKey), scopes)
+		}
+	}
+}
+
+func TestRequireScope(t *testing.T) {
+	tests := []struct {
+		name   string
+		scopes []models.Scope
+		status int
+	}{
+		{name: "no scopes", scopes: nil, status: http.StatusForbidden},
+		{name: "other scope", scopes: []models.Scope{models.ScopeReadPrivate}, status: http.StatusForbidden},
+		{name: "required scope", scopes: []models.Scope{models.ScopeReadPrivate, models.ScopeIncidentsWrite}, status: http.StatusOK},
+		{name: "admin", scopes: []models.Scope{models.ScopeAdmin}, status: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		router := gin.New()
+		router.POST("/incidents", func(c *gin.Context) {
+			if test.scopes != nil {
+				c.Set(ScopesKey, test.scopes)
+			}
+		}, RequireScope(models.ScopeIncidentsWrite), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/incidents", nil))
+
+		if w.Code != test.status {
+			t.Errorf("%s: expected the status %d, got %d", test.name, test.status, w.Code)
+		}
+	}
+}
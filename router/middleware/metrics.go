@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/RocketChat/statuscentral/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	httpRequests = metrics.NewCounterVec(
+		"statuscentral_http_requests_total",
+		"HTTP requests handled, by route and status code.",
+		"method", "route", "status",
+	)
+
+	httpRequestDuration = metrics.NewHistogramVec(
+		"statuscentral_http_request_duration_seconds",
+		"Time taken to handle the HTTP requests, by route.",
+		[]float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		"method", "route",
+	)
+)
+
+func init() {
+	metrics.Register(httpRequestDuration)
+}
+
+//Metrics records the number and duration of the requests
+func Metrics(c *gin.Context) {
+	start := time.Now()
+
+	c.Next()
+
+	// The route template keeps the number of label values small, unlike the actual path
+	route := c.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+
+	httpRequests.Inc(c.Request.Method, route, strconv.Itoa(c.Writer.Status()))
+	httpRequestDuration.Observe(time.Since(start).Seconds(), c.Request.Method, route)
+}
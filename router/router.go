@@ -2,9 +2,12 @@ package router
 
 import (
 	"fmt"
+	"html/template"
 
 	"github.com/RocketChat/statuscentral/config"
 	v1c "github.com/RocketChat/statuscentral/controllers/v1"
+	"github.com/RocketChat/statuscentral/core"
+	"github.com/RocketChat/statuscentral/models"
 	"github.com/RocketChat/statuscentral/router/middleware"
 	"github.com/gin-gonic/gin"
 )
@@ -14,8 +17,16 @@ func Start(port int) error {
 	runMetricsRouter()
 
 	router := gin.Default()
+	if err := router.SetTrustedProxies(config.Config.HTTP.TrustedProxies); err != nil {
+		return err
+	}
+
+	router.Use(middleware.Metrics)
 
 	router.Static("/static", "./static")
+	router.SetFuncMap(template.FuncMap{
+		"markdown": core.RenderMarkdown,
+	})
 	router.LoadHTMLGlob("templates/*.tmpl")
 
 	router.GET("/", v1c.IndexHandler)
@@ -25,52 +36,148 @@ func Start(port int) error {
 
 	router.GET("/scheduled-maintenance/:id", v1c.ScheduledMaintenanceDetailHandler)
 	router.GET("/m/:id", v1c.ScheduledMaintenanceShortRedirectHandler)
+	router.GET("/scheduled-maintenance.ics", v1c.ScheduledMaintenanceCalendarHandler)
 
 	router.GET("/incidents", v1c.IncidentHistoryHandler)
 
+	router.GET("/feed.rss", v1c.FeedRSSHandler)
+	router.GET("/feed.atom", v1c.FeedAtomHandler)
+	router.GET("/services/:name/feed.rss", v1c.FeedRSSHandler)
+	router.GET("/services/:name/feed.atom", v1c.FeedAtomHandler)
+
+	adminUI := router.Group("/admin")
+
+	adminUI.GET("/login", middleware.AdminLoginCSRF, v1c.AdminLoginPage)
+	adminUI.POST("/login", middleware.AdminLoginCSRF, v1c.AdminLogin)
+
+	adminUI.Use(middleware.IsAdminAuthorized)
+	{
+		read := middleware.AdminRequireScope(models.ScopeReadPrivate)
+		incidentsWrite := middleware.AdminRequireScope(models.ScopeIncidentsWrite)
+		maintenanceWrite := middleware.AdminRequireScope(models.ScopeMaintenanceWrite)
+
+		adminUI.POST("/logout", v1c.AdminLogout)
+		adminUI.GET("", read, v1c.AdminDashboardHandler)
+
+		adminUI.GET("/new-incident", incidentsWrite, v1c.AdminIncidentNewHandler)
+		adminUI.POST("/new-incident", incidentsWrite, middleware.Audit(models.AuditTargetIncident, "incident.create"), v1c.AdminIncidentCreateHandler)
+		adminUI.GET("/incidents/:id", read, v1c.AdminIncidentHandler)
+		adminUI.POST("/incidents/:id/updates", incidentsWrite, middleware.Audit(models.AuditTargetIncident, "incident_update.create"), v1c.AdminIncidentUpdateCreateHandler)
+
+		adminUI.GET("/scheduled-maintenance/:id", read, v1c.AdminScheduledMaintenanceHandler)
+		adminUI.POST("/scheduled-maintenance/:id", maintenanceWrite, middleware.Audit(models.AuditTargetScheduledMaintenance, "scheduled_maintenance.update"), v1c.AdminScheduledMaintenancePatchHandler)
+		adminUI.POST("/scheduled-maintenance/:id/updates", maintenanceWrite, middleware.Audit(models.AuditTargetScheduledMaintenance, "scheduled_maintenance_update.create"), v1c.AdminScheduledMaintenanceUpdateCreateHandler)
+	}
+
 	v1 := router.Group("/api").Group("/v1")
 
 	v1.GET("/services", v1c.ServicesGetAll)
+	v1.GET("/services/:id/uptime", v1c.ServiceUptimeGetOne)
+	v1.GET("/uptime", v1c.ServicesUptimeGetAll)
 	v1.GET("/incidents", v1c.IncidentsGetAll)
 	v1.GET("/incidents/:id/updates", v1c.IncidentUpdatesGetAll)
 
 	v1.GET("/scheduled-maintenance", v1c.ScheduledMaintenanceGetAll)
 	v1.GET("/scheduled-maintenance/:id/updates", v1c.ScheduledMaintenanceUpdatesGetAll)
 
+	v1.POST("/subscribers", middleware.Audit(models.AuditTargetSubscriber, "subscriber.create"), v1c.SubscriberCreate)
+	v1.GET("/subscribers/confirm", v1c.SubscriberConfirm)
+	v1.GET("/subscribers/unsubscribe", v1c.SubscriberUnsubscribe)
+
+	// Integrations have tokens of their own
+	v1.POST("/integrations/alertmanager", middleware.IsAlertmanagerAuthorized, middleware.Audit(models.AuditTargetIncident, "incident.alertmanager"), v1c.AlertmanagerWebhookReceive)
+
 	v1.Use(middleware.IsAuthorized)
 	{
-		v1.GET("/config", config.Config.HttpHandler)
+		read := middleware.RequireScope(models.ScopeReadPrivate)
+		incidentsWrite := middleware.RequireScope(models.ScopeIncidentsWrite)
+		maintenanceWrite := middleware.RequireScope(models.ScopeMaintenanceWrite)
+		servicesAdmin := middleware.RequireScope(models.ScopeServicesAdmin)
+		admin := middleware.RequireScope(models.ScopeAdmin)
+
+		v1.GET("/config", admin, config.Config.HttpHandler)
 
 		// Services
-		v1.POST("/services", v1c.ServiceCreate)
-		v1.GET("/services/:id", v1c.ServicesGetOne)
-		v1.POST("/services/:id", v1c.ServiceUpdate)
-		v1.DELETE("/services/:id", middleware.NotImplemented)
+		v1.POST("/services", servicesAdmin, middleware.Audit(models.AuditTargetService, "service.create"), v1c.ServiceCreate)
+		v1.GET("/services/:id", read, v1c.ServicesGetOne)
+		v1.POST("/services/:id", servicesAdmin, middleware.Audit(models.AuditTargetService, "service.update"), v1c.ServiceUpdate)
+		v1.GET("/services/:id/history", read, v1c.ServiceHistoryGetAll)
+		v1.DELETE("/services/:id", servicesAdmin, middleware.NotImplemented)
 
 		// Regions
-		v1.POST("/regions", v1c.RegionCreate)
-		v1.GET("/regions/:id", middleware.NotImplemented)
-		v1.PATCH("/regions/:id", middleware.NotImplemented)
-		v1.DELETE("/regions/:id", v1c.RegionDelete)
+		v1.POST("/regions", servicesAdmin, middleware.Audit(models.AuditTargetRegion, "region.create"), v1c.RegionCreate)
+		v1.GET("/regions/:id", read, middleware.NotImplemented)
+		v1.PATCH("/regions/:id", servicesAdmin, middleware.NotImplemented)
+		v1.DELETE("/regions/:id", servicesAdmin, middleware.Audit(models.AuditTargetRegion, "region.delete"), v1c.RegionDelete)
 
 		// Incidents
-		v1.POST("/incidents", v1c.IncidentCreate)
-		v1.GET("/incidents/:id", v1c.IncidentGetOne)
-		v1.DELETE("/incidents/:id", v1c.IncidentDelete)
-
-		v1.POST("/incidents/:id/updates", v1c.IncidentUpdateCreate)
-		v1.GET("/incidents/:id/updates/:updateId", v1c.IncidentUpdateGetOne)
-		v1.DELETE("/incidents/:id/updates/:updateId", v1c.IncidentUpdateDelete)
+		v1.POST("/incidents", incidentsWrite, middleware.Audit(models.AuditTargetIncident, "incident.create"), v1c.IncidentCreate)
+		v1.GET("/incidents/:id", read, v1c.IncidentGetOne)
+		v1.PATCH("/incidents/:id", incidentsWrite, middleware.Audit(models.AuditTargetIncident, "incident.update"), v1c.IncidentPatch)
+		v1.DELETE("/incidents/:id", incidentsWrite, middleware.Audit(models.AuditTargetIncident, "incident.delete"), v1c.IncidentDelete)
+
+		v1.POST("/incidents/:id/updates", incidentsWrite, middleware.Audit(models.AuditTargetIncident, "incident_update.create"), v1c.IncidentUpdateCreate)
+		v1.GET("/incidents/:id/updates/:updateId", read, v1c.IncidentUpdateGetOne)
+		v1.PATCH("/incidents/:id/updates/:updateId", incidentsWrite, middleware.Audit(models.AuditTargetIncident, "incident_update.update"), v1c.IncidentUpdatePatch)
+		v1.DELETE("/incidents/:id/updates/:updateId", incidentsWrite, middleware.Audit(models.AuditTargetIncident, "incident_update.delete"), v1c.IncidentUpdateDelete)
+		v1.GET("/incidents/:id/postmortem", read, v1c.PostmortemGetOne)
+		v1.POST("/incidents/:id/postmortem", incidentsWrite, middleware.Audit(models.AuditTargetIncident, "incident_postmortem.update"), v1c.PostmortemSave)
+		v1.DELETE("/incidents/:id/postmortem", incidentsWrite, middleware.Audit(models.AuditTargetIncident, "incident_postmortem.delete"), v1c.PostmortemDelete)
+		v1.POST("/incidents/:id/postmortem/publish", incidentsWrite, middleware.Audit(models.AuditTargetIncident, "incident_postmortem.publish"), v1c.PostmortemPublish)
+		v1.POST("/incidents/:id/postmortem/unpublish", incidentsWrite, middleware.Audit(models.AuditTargetIncident, "incident_postmortem.unpublish"), v1c.PostmortemUnpublish)
+
+		// Incident Templates
+		v1.GET("/incident-templates", read, v1c.IncidentTemplatesGetAll)
+		v1.POST("/incident-templates", incidentsWrite, middleware.Audit(models.AuditTargetIncidentTemplate, "incident_template.create"), v1c.IncidentTemplateCreate)
+		v1.GET("/incident-templates/:id", read, v1c.IncidentTemplateGetOne)
+		v1.POST("/incident-templates/:id", incidentsWrite, middleware.Audit(models.AuditTargetIncidentTemplate, "incident_template.update"), v1c.IncidentTemplateUpdate)
+		v1.DELETE("/incident-templates/:id", incidentsWrite, middleware.Audit(models.AuditTargetIncidentTemplate, "incident_template.delete"), v1c.IncidentTemplateDelete)
+		v1.POST("/incident-templates/:id/render", read, v1c.IncidentTemplateRender)
 
 		// Scheduled Maintenance
-		v1.POST("/scheduled-maintenance", v1c.ScheduledMaintenanceCreate)
-		v1.GET("/scheduled-maintenance/:id", v1c.ScheduledMaintenanceGetOne)
-		v1.PATCH("/scheduled-maintenance/:id", v1c.ScheduledMaintenancePatch)
-		v1.DELETE("/scheduled-maintenance/:id", v1c.ScheduledMaintenanceDelete)
-
-		v1.POST("/scheduled-maintenance/:id/updates", v1c.ScheduledMaintenanceUpdateCreate)
-		v1.GET("/scheduled-maintenance/:id/updates/:updateId", v1c.ScheduledMaintenanceUpdateGetOne)
-		v1.DELETE("/scheduled-maintenance/:id/updates/:updateId", v1c.ScheduledMaintenanceUpdateDelete)
+		v1.POST("/scheduled-maintenance", maintenanceWrite, middleware.Audit(models.AuditTargetScheduledMaintenance, "scheduled_maintenance.create"), v1c.ScheduledMaintenanceCreate)
+		v1.GET("/scheduled-maintenance/:id", read, v1c.ScheduledMaintenanceGetOne)
+		v1.PATCH("/scheduled-maintenance/:id", maintenanceWrite, middleware.Audit(models.AuditTargetScheduledMaintenance, "scheduled_maintenance.update"), v1c.ScheduledMaintenancePatch)
+		v1.DELETE("/scheduled-maintenance/:id", maintenanceWrite, middleware.Audit(models.AuditTargetScheduledMaintenance, "scheduled_maintenance.delete"), v1c.ScheduledMaintenanceDelete)
+		v1.POST("/scheduled-maintenance/:id/extend", maintenanceWrite, middleware.Audit(models.AuditTargetScheduledMaintenance, "scheduled_maintenance.extend"), v1c.ScheduledMaintenanceExtend)
+		v1.POST("/scheduled-maintenance/:id/start", maintenanceWrite, middleware.Audit(models.AuditTargetScheduledMaintenance, "scheduled_maintenance.start"), v1c.ScheduledMaintenanceStart)
+		v1.POST("/scheduled-maintenance/:id/cancel", maintenanceWrite, middleware.Audit(models.AuditTargetScheduledMaintenance, "scheduled_maintenance.cancel"), v1c.ScheduledMaintenanceCancel)
+
+		v1.POST("/scheduled-maintenance/:id/updates", maintenanceWrite, middleware.Audit(models.AuditTargetScheduledMaintenance, "scheduled_maintenance_update.create"), v1c.ScheduledMaintenanceUpdateCreate)
+		v1.GET("/scheduled-maintenance/:id/updates/:updateId", read, v1c.ScheduledMaintenanceUpdateGetOne)
+		v1.PATCH("/scheduled-maintenance/:id/updates/:updateId", maintenanceWrite, middleware.Audit(models.AuditTargetScheduledMaintenance, "scheduled_maintenance_update.update"), v1c.ScheduledMaintenanceUpdatePatch)
+		v1.DELETE("/scheduled-maintenance/:id/updates/:updateId", maintenanceWrite, middleware.Audit(models.AuditTargetScheduledMaintenance, "scheduled_maintenance_update.delete"), v1c.ScheduledMaintenanceUpdateDelete)
+
+		// Recurring Maintenance
+		v1.GET("/recurring-maintenance", read, v1c.RecurringMaintenanceGetAll)
+		v1.POST("/recurring-maintenance", maintenanceWrite, middleware.Audit(models.AuditTargetRecurringMaintenance, "recurring_maintenance.create"), v1c.RecurringMaintenanceCreate)
+		v1.GET("/recurring-maintenance/:id", read, v1c.RecurringMaintenanceGetOne)
+		v1.POST("/recurring-maintenance/:id", maintenanceWrite, middleware.Audit(models.AuditTargetRecurringMaintenance, "recurring_maintenance.update"), v1c.RecurringMaintenanceUpdate)
+		v1.DELETE("/recurring-maintenance/:id", maintenanceWrite, middleware.Audit(models.AuditTargetRecurringMaintenance, "recurring_maintenance.delete"), v1c.RecurringMaintenanceDelete)
+		v1.GET("/recurring-maintenance/:id/occurrences", read, v1c.RecurringMaintenanceOccurrencesGetAll)
+		v1.POST("/recurring-maintenance/:id/exceptions", maintenanceWrite, middleware.Audit(models.AuditTargetRecurringMaintenance, "recurring_maintenance.exception"), v1c.RecurringMaintenanceExceptionSet)
+
+		// Subscribers
+		v1.GET("/subscribers", admin, v1c.SubscribersGetAll)
+		v1.DELETE("/subscribers/:id", admin, middleware.Audit(models.AuditTargetSubscriber, "subscriber.delete"), v1c.SubscriberDelete)
+
+		// Webhooks
+		v1.GET("/webhooks", admin, v1c.WebhooksGetAll)
+		v1.POST("/webhooks", admin, middleware.Audit(models.AuditTargetWebhook, "webhook.create"), v1c.WebhookCreate)
+		v1.GET("/webhooks/:id", admin, v1c.WebhookGetOne)
+		v1.PATCH("/webhooks/:id", admin, middleware.Audit(models.AuditTargetWebhook, "webhook.update"), v1c.WebhookPatch)
+		v1.DELETE("/webhooks/:id", admin, middleware.Audit(models.AuditTargetWebhook, "webhook.delete"), v1c.WebhookDelete)
+		v1.GET("/webhooks/:id/deliveries", admin, v1c.WebhookDeliveriesGetAll)
+
+		// API Tokens
+		v1.GET("/tokens", admin, v1c.APITokensGetAll)
+		v1.POST("/tokens", admin, middleware.Audit(models.AuditTargetAPIToken, "token.create"), v1c.APITokenCreate)
+		v1.GET("/tokens/:id", admin, v1c.APITokenGetOne)
+		v1.DELETE("/tokens/:id", admin, middleware.Audit(models.AuditTargetAPIToken, "token.revoke"), v1c.APITokenRevoke)
+
+		// Audit Log
+		v1.GET("/audit", admin, v1c.AuditGetAll)
+		v1.GET("/audit/:id", admin, v1c.AuditGetOne)
 	}
 
 	return router.Run(fmt.Sprintf(":%d", port))
@@ -79,9 +186,10 @@ func Start(port int) error {
 func runMetricsRouter() {
 	healthMetricsRouter := gin.Default()
 	healthMetricsRouter.GET("/health", v1c.LivenessCheckHandler)
+	healthMetricsRouter.GET("/metrics", v1c.MetricsHandler)
 
 	// Endpoint that will return a snapshot of the bolt database. Can be used for backup purposes
-	healthMetricsRouter.GET("/snapshot", middleware.IsAuthorized, v1c.SnapshotHandler)
+	healthMetricsRouter.GET("/snapshot", middleware.IsAuthorized, middleware.RequireScope(models.ScopeSnapshot), v1c.SnapshotHandler)
 
 	go healthMetricsRouter.Run(":8080")
 }
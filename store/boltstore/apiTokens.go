@@ -0,0 +1,181 @@
+package boltstore
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/RocketChat/statuscentral/models"
+	bolt "github.com/etcd-io/bbolt"
+)
+
+// createAPITokenHashIndex creates the bucket finding the id of a token by its hash, with the tokens which
+// aren't revoked yet. Only those can be authenticated, so revoking a token removes it from the index.
+func createAPITokenHashIndex(tx *bolt.Tx) error {
+	index, err := tx.CreateBucket(apiTokenHashBucket)
+	if err != nil {
+		return err
+	}
+
+	cursor := tx.Bucket(apiTokenBucket).Cursor()
+
+	for k, data := cursor.First(); k != nil; k, data = cursor.Next() {
+		var t models.APIToken
+		if err := json.Unmarshal(data, &t); err != nil {
+			return err
+		}
+
+		if t.RevokedAt != nil || t.Hash == "" {
+			continue
+		}
+
+		if err := index.Put([]byte(t.Hash), k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *boltStore) GetAPITokens() ([]*models.APIToken, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cursor := tx.Bucket(apiTokenBucket).Cursor()
+
+	tokens := make([]*models.APIToken, 0)
+	for k, data := cursor.First(); k != nil; k, data = cursor.Next() {
+		var t models.APIToken
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, err
+		}
+
+		tokens = append(tokens, &t)
+	}
+
+	return tokens, nil
+}
+
+func (s *boltStore) GetAPITokenByID(id int) (*models.APIToken, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	bytes := tx.Bucket(apiTokenBucket).Get(itob(id))
+	if bytes == nil {
+		return nil, nil
+	}
+
+	var token models.APIToken
+	if err := json.Unmarshal(bytes, &token); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (s *boltStore) GetAPITokenByHash(hash string) (*models.APIToken, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	id := tx.Bucket(apiTokenHashBucket).Get([]byte(hash))
+	if id == nil {
+		return nil, nil
+	}
+
+	data := tx.Bucket(apiTokenBucket).Get(id)
+	if data == nil {
+		return nil, nil
+	}
+
+	var token models.APIToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, err
+	}
+
+	if token.Hash != hash {
+		return nil, nil
+	}
+
+	return &token, nil
+}
+
+func (s *boltStore) CreateAPIToken(token *models.APIToken) error {
+	tx, err := s.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(apiTokenBucket)
+
+	seq, _ := bucket.NextSequence()
+	token.ID = int(seq)
+
+	buf, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(itob(token.ID), buf); err != nil {
+		return err
+	}
+
+	if token.RevokedAt == nil {
+		if err := tx.Bucket(apiTokenHashBucket).Put([]byte(token.Hash), itob(token.ID)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (s *boltStore) UpdateAPIToken(token *models.APIToken) error {
+	if token.ID <= 0 {
+		return errors.New("invalid token id")
+	}
+
+	tx, err := s.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(apiTokenBucket)
+
+	// A revoked token stays revoked, even when a copy read before the revocation is saved
+	if data := bucket.Get(itob(token.ID)); data != nil {
+		var stored models.APIToken
+		if err := json.Unmarshal(data, &stored); err != nil {
+			return err
+		}
+
+		if stored.RevokedAt != nil {
+			token.RevokedAt = stored.RevokedAt
+		}
+	}
+
+	buf, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(itob(token.ID), buf); err != nil {
+		return err
+	}
+
+	if token.RevokedAt != nil {
+		if err := tx.Bucket(apiTokenHashBucket).Delete([]byte(token.Hash)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
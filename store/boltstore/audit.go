@@ -0,0 +1,93 @@
+package boltstore
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+// CreateAuditEntry appends the entry to the audit log, entries are never updated or removed
+func (s *boltStore) CreateAuditEntry(entry *models.AuditEntry) error {
+	tx, err := s.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(auditBucket)
+
+	seq, _ := bucket.NextSequence()
+	entry.ID = int(seq)
+
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(itob(entry.ID), buf); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *boltStore) GetAuditEntryByID(id int) (*models.AuditEntry, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	bytes := tx.Bucket(auditBucket).Get(itob(id))
+	if bytes == nil {
+		return nil, nil
+	}
+
+	var entry models.AuditEntry
+	if err := json.Unmarshal(bytes, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// GetAuditEntries gets the entries passing the filter, newest first
+func (s *boltStore) GetAuditEntries(filter models.AuditFilter) ([]*models.AuditEntry, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cursor := tx.Bucket(auditBucket).Cursor()
+
+	entries := make([]*models.AuditEntry, 0)
+	for k, data := cursor.Last(); k != nil; k, data = cursor.Prev() {
+		var e models.AuditEntry
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, err
+		}
+
+		// Entries are in the order they happened, so nothing older can match anymore
+		if !filter.From.IsZero() && e.Time.Before(filter.From) {
+			break
+		}
+
+		if !filter.Matches(&e) {
+			continue
+		}
+
+		entries = append(entries, &e)
+
+		if filter.Limit > 0 && len(entries) >= filter.Limit {
+			break
+		}
+	}
+
+	return entries, nil
+}
@@ -2,12 +2,14 @@ package boltstore
 
 import (
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"time"
 
 	"github.com/RocketChat/statuscentral/config"
+	"github.com/RocketChat/statuscentral/models"
 	"github.com/RocketChat/statuscentral/store"
 	bolt "github.com/etcd-io/bbolt"
 )
@@ -18,12 +20,24 @@ type boltStore struct {
 
 var (
 	incidentBucket             = []byte("incidents")
+	openIncidentBucket         = []byte("incidents-open")
 	scheduledMaintenanceBucket = []byte("scheduled-maintenance")
+	deletedMaintenanceBucket   = []byte("scheduled-maintenance-deleted")
+	recurringMaintenanceBucket = []byte("recurring-maintenance")
 	serviceBucket              = []byte("services")
 	regionBucket               = []byte("regions")
+	subscriberBucket           = []byte("subscribers")
+	webhookBucket              = []byte("webhooks")
+	webhookDeliveryBucket      = []byte("webhook-deliveries")
+	statusHistoryBucket        = []byte("status-history")
+	apiTokenBucket             = []byte("api-tokens")
+	apiTokenHashBucket         = []byte("api-token-hashes")
+	auditBucket                = []byte("audit-log")
+	incidentTemplateBucket     = []byte("incident-templates")
+	migrationBucket            = []byte("migrations")
 )
 
-//New creates a new bolt store
+// New creates a new bolt store
 func New() (store.Store, error) {
 	if config.Config == nil {
 		return nil, errors.New("configuration doesn't seem to exist")
@@ -44,10 +58,28 @@ func New() (store.Store, error) {
 		return nil, err
 	}
 
+	if _, err := tx.CreateBucketIfNotExists(deletedMaintenanceBucket); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.CreateBucketIfNotExists(recurringMaintenanceBucket); err != nil {
+		return nil, err
+	}
+
 	if _, err := tx.CreateBucketIfNotExists(incidentBucket); err != nil {
 		return nil, err
 	}
 
+	if tx.Bucket(openIncidentBucket) == nil {
+		if err := createOpenIncidentIndex(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.CreateBucketIfNotExists(incidentTemplateBucket); err != nil {
+		return nil, err
+	}
+
 	if _, err := tx.CreateBucketIfNotExists(serviceBucket); err != nil {
 		return nil, err
 	}
@@ -56,6 +88,40 @@ func New() (store.Store, error) {
 		return nil, err
 	}
 
+	if _, err := tx.CreateBucketIfNotExists(subscriberBucket); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.CreateBucketIfNotExists(webhookBucket); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.CreateBucketIfNotExists(webhookDeliveryBucket); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.CreateBucketIfNotExists(statusHistoryBucket); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.CreateBucketIfNotExists(apiTokenBucket); err != nil {
+		return nil, err
+	}
+
+	if tx.Bucket(apiTokenHashBucket) == nil {
+		if err := createAPITokenHashIndex(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.CreateBucketIfNotExists(auditBucket); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.CreateBucketIfNotExists(migrationBucket); err != nil {
+		return nil, err
+	}
+
 	if err := tx.Commit(); err != nil {
 		return nil, err
 	}
@@ -88,9 +154,48 @@ func (s *boltStore) Snapshot(w io.Writer) error {
 	return nil
 }
 
-//itob returns an 8-byte big endian representation of v.
+func (s *boltStore) DBStats() map[string]float64 {
+	stats := s.Stats()
+
+	size := int64(0)
+	s.View(func(tx *bolt.Tx) error { //nolint:errcheck // Never fails
+		size = tx.Size()
+		return nil
+	})
+
+	return map[string]float64{
+		"size_bytes":             float64(size),
+		"free_pages":             float64(stats.FreePageN),
+		"pending_pages":          float64(stats.PendingPageN),
+		"free_alloc_bytes":       float64(stats.FreeAlloc),
+		"freelist_inuse_bytes":   float64(stats.FreelistInuse),
+		"read_tx_total":          float64(stats.TxN),
+		"open_read_tx":           float64(stats.OpenTxN),
+		"page_allocations_total": float64(stats.TxStats.PageCount),
+		"writes_total":           float64(stats.TxStats.Write),
+	}
+}
+
+// itob returns an 8-byte big endian representation of v.
 func itob(v int) []byte {
 	b := make([]byte, 8)
 	binary.BigEndian.PutUint64(b, uint64(v))
 	return b
 }
+
+// storedNotifications gets the notification results of a stored incident or scheduled maintenance, nil when there is none
+func storedNotifications(data []byte) (models.NotificationResults, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	var stored struct {
+		Notifications models.NotificationResults `json:"notifications"`
+	}
+
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+
+	return stored.Notifications, nil
+}
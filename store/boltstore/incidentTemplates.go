@@ -0,0 +1,115 @@
+package boltstore
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+	bolt "github.com/etcd-io/bbolt"
+)
+
+func (s *boltStore) GetIncidentTemplates() ([]*models.IncidentTemplate, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cursor := tx.Bucket(incidentTemplateBucket).Cursor()
+
+	incidentTemplates := make([]*models.IncidentTemplate, 0)
+	for k, data := cursor.First(); k != nil; k, data = cursor.Next() {
+		var t models.IncidentTemplate
+		if err := json.Unmarshal(data, &t); err != nil {
+			return nil, err
+		}
+
+		incidentTemplates = append(incidentTemplates, &t)
+	}
+
+	return incidentTemplates, nil
+}
+
+func (s *boltStore) GetIncidentTemplateByID(id int) (*models.IncidentTemplate, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	bytes := tx.Bucket(incidentTemplateBucket).Get(itob(id))
+	if bytes == nil {
+		return nil, nil
+	}
+
+	var t models.IncidentTemplate
+	if err := json.Unmarshal(bytes, &t); err != nil {
+		return nil, err
+	}
+
+	return &t, nil
+}
+
+func (s *boltStore) CreateIncidentTemplate(incidentTemplate *models.IncidentTemplate) error {
+	tx, err := s.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(incidentTemplateBucket)
+
+	seq, _ := bucket.NextSequence()
+	incidentTemplate.ID = int(seq)
+
+	if incidentTemplate.CreatedAt.IsZero() {
+		incidentTemplate.CreatedAt = time.Now()
+	}
+
+	incidentTemplate.UpdatedAt = time.Now()
+
+	buf, err := json.Marshal(incidentTemplate)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(itob(incidentTemplate.ID), buf); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *boltStore) UpdateIncidentTemplate(incidentTemplate *models.IncidentTemplate) error {
+	if incidentTemplate.ID <= 0 {
+		return errors.New("invalid incident template id")
+	}
+
+	tx, err := s.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(incidentTemplateBucket)
+
+	incidentTemplate.UpdatedAt = time.Now()
+
+	buf, err := json.Marshal(incidentTemplate)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(itob(incidentTemplate.ID), buf); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *boltStore) DeleteIncidentTemplate(id int) error {
+	return s.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(incidentTemplateBucket).Delete(itob(id))
+	})
+}
@@ -10,6 +10,38 @@ import (
 	bolt "github.com/etcd-io/bbolt"
 )
 
+// createOpenIncidentIndex creates the bucket holding the ids of the incidents which aren't resolved, so they are
+// found without reading every incident
+func createOpenIncidentIndex(tx *bolt.Tx) error {
+	if _, err := tx.CreateBucket(openIncidentBucket); err != nil {
+		return err
+	}
+
+	cursor := tx.Bucket(incidentBucket).Cursor()
+
+	for k, data := cursor.First(); k != nil; k, data = cursor.Next() {
+		var i models.Incident
+		if err := json.Unmarshal(data, &i); err != nil {
+			return err
+		}
+
+		if err := indexOpenIncident(tx, &i); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// indexOpenIncident adds the incident to the open ones, or removes it once it's resolved
+func indexOpenIncident(tx *bolt.Tx, incident *models.Incident) error {
+	if incident.Status == models.IncidentStatusResolved {
+		return tx.Bucket(openIncidentBucket).Delete(itob(incident.ID))
+	}
+
+	return tx.Bucket(openIncidentBucket).Put(itob(incident.ID), []byte{})
+}
+
 // GetIncidents retrieves a paginated list of incidents.
 // Incidents are returned from newest to oldest.
 func (s *boltStore) GetIncidents(latestOnly bool, pagination models.Pagination) ([]*models.Incident, error) {
@@ -67,9 +99,11 @@ func (s *boltStore) GetIncidents(latestOnly bool, pagination models.Pagination)
 			continue
 		}
 
-		// Only add incidents to the slice if we haven't reached the page limit yet.
-		if len(incidents) < pagination.Limit {
-			incidents = append(incidents, &i)
+		incidents = append(incidents, &i)
+
+		// The page is full, the older incidents don't need to be read
+		if len(incidents) == pagination.Limit {
+			break
 		}
 	}
 
@@ -96,6 +130,62 @@ func (s *boltStore) GetIncidentByID(id int) (*models.Incident, error) {
 	return &i, nil
 }
 
+// GetIncidentsSince gets all of the incidents which started after the given time, newest first
+func (s *boltStore) GetIncidentsSince(since time.Time) ([]*models.Incident, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cursor := tx.Bucket(incidentBucket).Cursor()
+
+	incidents := make([]*models.Incident, 0)
+	for k, data := cursor.Last(); k != nil; k, data = cursor.Prev() {
+		var i models.Incident
+		if err := json.Unmarshal(data, &i); err != nil {
+			return nil, err
+		}
+
+		if i.Time.After(since) {
+			incidents = append(incidents, &i)
+		}
+	}
+
+	return incidents, nil
+}
+
+// GetOpenIncidents gets the incidents which aren't resolved, newest first
+func (s *boltStore) GetOpenIncidents() ([]*models.Incident, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(incidentBucket)
+	cursor := tx.Bucket(openIncidentBucket).Cursor()
+
+	incidents := make([]*models.Incident, 0)
+	for k, _ := cursor.Last(); k != nil; k, _ = cursor.Prev() {
+		data := bucket.Get(k)
+		if data == nil {
+			continue
+		}
+
+		var i models.Incident
+		if err := json.Unmarshal(data, &i); err != nil {
+			return nil, err
+		}
+
+		if i.Status != models.IncidentStatusResolved {
+			incidents = append(incidents, &i)
+		}
+	}
+
+	return incidents, nil
+}
+
 func (s *boltStore) CreateIncident(incident *models.Incident) error {
 	tx, err := s.Begin(true)
 	if err != nil {
@@ -118,6 +208,10 @@ func (s *boltStore) CreateIncident(incident *models.Incident) error {
 		return err
 	}
 
+	if err := indexOpenIncident(tx, incident); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
@@ -134,6 +228,14 @@ func (s *boltStore) UpdateIncident(incident *models.Incident) error {
 
 	bucket := tx.Bucket(incidentBucket)
 
+	// The notification results are only written by UpdateIncidentNotifications, so the ones recorded
+	// while the incident was being changed aren't lost
+	notifications, err := storedNotifications(bucket.Get(itob(incident.ID)))
+	if err != nil {
+		return err
+	}
+
+	incident.Notifications = notifications
 	incident.UpdatedAt = time.Now()
 
 	buf, err := json.Marshal(incident)
@@ -145,11 +247,49 @@ func (s *boltStore) UpdateIncident(incident *models.Incident) error {
 		return err
 	}
 
+	if err := indexOpenIncident(tx, incident); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
+// UpdateIncidentNotifications changes the notification results of the incident, nothing happens when it doesn't exist
+func (s *boltStore) UpdateIncidentNotifications(id int, update func(results models.NotificationResults)) error {
+	return s.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(incidentBucket)
+
+		data := bucket.Get(itob(id))
+		if data == nil {
+			return nil
+		}
+
+		var i models.Incident
+		if err := json.Unmarshal(data, &i); err != nil {
+			return err
+		}
+
+		if i.Notifications == nil {
+			i.Notifications = make(models.NotificationResults)
+		}
+
+		update(i.Notifications)
+
+		buf, err := json.Marshal(&i)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(itob(id), buf)
+	})
+}
+
 func (s *boltStore) DeleteIncident(id int) error {
 	return s.Update(func(tx *bolt.Tx) error {
+		if err := tx.Bucket(openIncidentBucket).Delete(itob(id)); err != nil {
+			return err
+		}
+
 		return tx.Bucket(incidentBucket).Delete(itob(id))
 	})
 }
@@ -195,6 +335,10 @@ func (s *boltStore) CreateIncidentUpdate(incidentID int, update *models.StatusUp
 		return err
 	}
 
+	if err := indexOpenIncident(tx, &i); err != nil {
+		return err
+	}
+
 	return tx.Commit()
 }
 
@@ -244,6 +388,51 @@ func (s *boltStore) GetIncidentUpdatesByIncidentID(incidentId int) ([]*models.St
 	return incident.Updates, nil
 }
 
+func (s *boltStore) UpdateIncidentUpdate(incidentID int, update *models.StatusUpdate) error {
+	tx, err := s.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(incidentBucket)
+
+	bytes := bucket.Get(itob(incidentID))
+	if bytes == nil {
+		return errors.New("no incident found by that id")
+	}
+
+	var incident models.Incident
+	if err := json.Unmarshal(bytes, &incident); err != nil {
+		return err
+	}
+
+	found := false
+	for i, existing := range incident.Updates {
+		if existing.ID == update.ID {
+			incident.Updates[i] = update
+			found = true
+		}
+	}
+
+	if !found {
+		return errors.New("no incident update found by that id")
+	}
+
+	incident.UpdatedAt = time.Now()
+
+	buf, err := json.Marshal(incident)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(itob(incident.ID), buf); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (s *boltStore) DeleteIncidentUpdateByID(incidentId int, updateId int) error {
 	tx, err := s.Begin(true)
 	if err != nil {
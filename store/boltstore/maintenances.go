@@ -107,6 +107,14 @@ func (s *boltStore) UpdateScheduledMaintenance(maintenance *models.ScheduledMain
 
 	bucket := tx.Bucket(scheduledMaintenanceBucket)
 
+	// The notification results are only written by UpdateScheduledMaintenanceNotifications, so the ones
+	// recorded while the maintenance was being changed aren't lost
+	notifications, err := storedNotifications(bucket.Get(itob(maintenance.ID)))
+	if err != nil {
+		return err
+	}
+
+	maintenance.Notifications = notifications
 	maintenance.UpdatedAt = time.Now()
 
 	buf, err := json.Marshal(maintenance)
@@ -121,12 +129,92 @@ func (s *boltStore) UpdateScheduledMaintenance(maintenance *models.ScheduledMain
 	return tx.Commit()
 }
 
+// UpdateScheduledMaintenanceNotifications changes the notification results of the scheduled maintenance, nothing
+// happens when it doesn't exist
+func (s *boltStore) UpdateScheduledMaintenanceNotifications(id int, update func(results models.NotificationResults)) error {
+	return s.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(scheduledMaintenanceBucket)
+
+		data := bucket.Get(itob(id))
+		if data == nil {
+			return nil
+		}
+
+		var m models.ScheduledMaintenance
+		if err := json.Unmarshal(data, &m); err != nil {
+			return err
+		}
+
+		if m.Notifications == nil {
+			m.Notifications = make(models.NotificationResults)
+		}
+
+		update(m.Notifications)
+
+		buf, err := json.Marshal(&m)
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(itob(id), buf)
+	})
+}
+
+// DeleteScheduledMaintenance removes the scheduled maintenance, keeping a cancelled copy of it
+// around so calendars which already have it can be told about it being gone
 func (s *boltStore) DeleteScheduledMaintenance(id int) error {
 	return s.Update(func(tx *bolt.Tx) error {
-		return tx.Bucket(scheduledMaintenanceBucket).Delete(itob(id))
+		bucket := tx.Bucket(scheduledMaintenanceBucket)
+
+		bytes := bucket.Get(itob(id))
+		if bytes == nil {
+			return nil
+		}
+
+		var m models.ScheduledMaintenance
+		if err := json.Unmarshal(bytes, &m); err != nil {
+			return err
+		}
+
+		m.Cancelled = true
+		m.Sequence++
+		m.UpdatedAt = time.Now()
+
+		buf, err := json.Marshal(m)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Bucket(deletedMaintenanceBucket).Put(itob(id), buf); err != nil {
+			return err
+		}
+
+		return bucket.Delete(itob(id))
 	})
 }
 
+func (s *boltStore) GetDeletedScheduledMaintenance() ([]*models.ScheduledMaintenance, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cursor := tx.Bucket(deletedMaintenanceBucket).Cursor()
+
+	scheduledMaintenances := make([]*models.ScheduledMaintenance, 0)
+	for k, data := cursor.First(); k != nil; k, data = cursor.Next() {
+		var m models.ScheduledMaintenance
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+
+		scheduledMaintenances = append(scheduledMaintenances, &m)
+	}
+
+	return scheduledMaintenances, nil
+}
+
 func (s *boltStore) CreateScheduledMaintenanceUpdate(maintenanceID int, update *models.StatusUpdate) error {
 	tx, err := s.Begin(true)
 	if err != nil {
@@ -217,6 +305,51 @@ func (s *boltStore) GetScheduledMaintenanceUpdatesByMaintenanceID(maintenanceID
 	return scheduledMaintenance.Updates, nil
 }
 
+func (s *boltStore) UpdateScheduledMaintenanceUpdate(maintenanceID int, update *models.StatusUpdate) error {
+	tx, err := s.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(scheduledMaintenanceBucket)
+
+	bytes := bucket.Get(itob(maintenanceID))
+	if bytes == nil {
+		return errors.New("no scheduled maintenance found by that id")
+	}
+
+	var scheduledMaintenance models.ScheduledMaintenance
+	if err := json.Unmarshal(bytes, &scheduledMaintenance); err != nil {
+		return err
+	}
+
+	found := false
+	for i, existing := range scheduledMaintenance.Updates {
+		if existing.ID == update.ID {
+			scheduledMaintenance.Updates[i] = update
+			found = true
+		}
+	}
+
+	if !found {
+		return errors.New("no scheduled maintenance update found by that id")
+	}
+
+	scheduledMaintenance.UpdatedAt = time.Now()
+
+	buf, err := json.Marshal(scheduledMaintenance)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(itob(scheduledMaintenance.ID), buf); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (s *boltStore) DeleteScheduledMaintenanceUpdateByID(maintenanceID int, updateId int) error {
 	tx, err := s.Begin(true)
 	if err != nil {
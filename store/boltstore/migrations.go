@@ -0,0 +1,25 @@
+package boltstore
+
+import (
+	"time"
+
+	bolt "github.com/etcd-io/bbolt"
+)
+
+// IsMigrationApplied tells whether the migration with that name already ran on this database
+func (s *boltStore) IsMigrationApplied(name string) (bool, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	return tx.Bucket(migrationBucket).Get([]byte(name)) != nil, nil
+}
+
+// SetMigrationApplied records when the migration with that name ran, so it doesn't run again
+func (s *boltStore) SetMigrationApplied(name string) error {
+	return s.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(migrationBucket).Put([]byte(name), []byte(time.Now().UTC().Format(time.RFC3339)))
+	})
+}
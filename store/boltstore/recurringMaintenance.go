@@ -0,0 +1,115 @@
+package boltstore
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+	bolt "github.com/etcd-io/bbolt"
+)
+
+func (s *boltStore) GetRecurringMaintenances() ([]*models.RecurringMaintenance, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cursor := tx.Bucket(recurringMaintenanceBucket).Cursor()
+
+	recurringMaintenances := make([]*models.RecurringMaintenance, 0)
+	for k, data := cursor.First(); k != nil; k, data = cursor.Next() {
+		var r models.RecurringMaintenance
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, err
+		}
+
+		recurringMaintenances = append(recurringMaintenances, &r)
+	}
+
+	return recurringMaintenances, nil
+}
+
+func (s *boltStore) GetRecurringMaintenanceByID(id int) (*models.RecurringMaintenance, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	bytes := tx.Bucket(recurringMaintenanceBucket).Get(itob(id))
+	if bytes == nil {
+		return nil, nil
+	}
+
+	var r models.RecurringMaintenance
+	if err := json.Unmarshal(bytes, &r); err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+func (s *boltStore) CreateRecurringMaintenance(recurringMaintenance *models.RecurringMaintenance) error {
+	tx, err := s.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(recurringMaintenanceBucket)
+
+	seq, _ := bucket.NextSequence()
+	recurringMaintenance.ID = int(seq)
+
+	if recurringMaintenance.CreatedAt.IsZero() {
+		recurringMaintenance.CreatedAt = time.Now()
+	}
+
+	recurringMaintenance.UpdatedAt = time.Now()
+
+	buf, err := json.Marshal(recurringMaintenance)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(itob(recurringMaintenance.ID), buf); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *boltStore) UpdateRecurringMaintenance(recurringMaintenance *models.RecurringMaintenance) error {
+	if recurringMaintenance.ID <= 0 {
+		return errors.New("invalid recurring maintenance id")
+	}
+
+	tx, err := s.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(recurringMaintenanceBucket)
+
+	recurringMaintenance.UpdatedAt = time.Now()
+
+	buf, err := json.Marshal(recurringMaintenance)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(itob(recurringMaintenance.ID), buf); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *boltStore) DeleteRecurringMaintenance(id int) error {
+	return s.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(recurringMaintenanceBucket).Delete(itob(id))
+	})
+}
@@ -0,0 +1,61 @@
+package boltstore
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+)
+
+func (s *boltStore) CreateStatusChange(change *models.StatusChange) error {
+	tx, err := s.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(statusHistoryBucket)
+
+	seq, _ := bucket.NextSequence()
+	change.ID = int(seq)
+
+	if change.Time.IsZero() {
+		change.Time = time.Now()
+	}
+
+	buf, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(itob(change.ID), buf); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetStatusChangesByServiceID gets the status changes of the service and its regions, oldest first
+func (s *boltStore) GetStatusChangesByServiceID(serviceID int) ([]*models.StatusChange, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cursor := tx.Bucket(statusHistoryBucket).Cursor()
+
+	changes := make([]*models.StatusChange, 0)
+	for k, data := cursor.First(); k != nil; k, data = cursor.Next() {
+		var c models.StatusChange
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, err
+		}
+
+		if c.ServiceID == serviceID {
+			changes = append(changes, &c)
+		}
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,138 @@
+package boltstore
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+	bolt "github.com/etcd-io/bbolt"
+)
+
+func (s *boltStore) GetSubscribers() ([]*models.Subscriber, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cursor := tx.Bucket(subscriberBucket).Cursor()
+
+	subscribers := make([]*models.Subscriber, 0)
+	for k, data := cursor.First(); k != nil; k, data = cursor.Next() {
+		var i models.Subscriber
+		if err := json.Unmarshal(data, &i); err != nil {
+			return nil, err
+		}
+
+		subscribers = append(subscribers, &i)
+	}
+
+	return subscribers, nil
+}
+
+func (s *boltStore) GetSubscriberByID(id int) (*models.Subscriber, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	bytes := tx.Bucket(subscriberBucket).Get(itob(id))
+	if bytes == nil {
+		return nil, nil
+	}
+
+	var subscriber models.Subscriber
+	if err := json.Unmarshal(bytes, &subscriber); err != nil {
+		return nil, err
+	}
+
+	return &subscriber, nil
+}
+
+func (s *boltStore) GetSubscriberByEmail(email string) (*models.Subscriber, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cursor := tx.Bucket(subscriberBucket).Cursor()
+
+	for k, data := cursor.First(); k != nil; k, data = cursor.Next() {
+		var i models.Subscriber
+		if err := json.Unmarshal(data, &i); err != nil {
+			return nil, err
+		}
+
+		if i.Email == email {
+			return &i, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *boltStore) CreateSubscriber(subscriber *models.Subscriber) error {
+	tx, err := s.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(subscriberBucket)
+
+	seq, _ := bucket.NextSequence()
+	subscriber.ID = int(seq)
+
+	if subscriber.CreatedAt.IsZero() {
+		subscriber.CreatedAt = time.Now()
+	}
+
+	subscriber.UpdatedAt = time.Now()
+
+	buf, err := json.Marshal(subscriber)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(itob(subscriber.ID), buf); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *boltStore) UpdateSubscriber(subscriber *models.Subscriber) error {
+	if subscriber.ID <= 0 {
+		return errors.New("invalid subscriber id")
+	}
+
+	tx, err := s.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(subscriberBucket)
+
+	subscriber.UpdatedAt = time.Now()
+
+	buf, err := json.Marshal(subscriber)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(itob(subscriber.ID), buf); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *boltStore) DeleteSubscriber(id int) error {
+	return s.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(subscriberBucket).Delete(itob(id))
+	})
+}
@@ -0,0 +1,282 @@
+package boltstore
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/RocketChat/statuscentral/models"
+	bolt "github.com/etcd-io/bbolt"
+)
+
+func (s *boltStore) GetWebhooks() ([]*models.Webhook, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cursor := tx.Bucket(webhookBucket).Cursor()
+
+	webhooks := make([]*models.Webhook, 0)
+	for k, data := cursor.First(); k != nil; k, data = cursor.Next() {
+		var w models.Webhook
+		if err := json.Unmarshal(data, &w); err != nil {
+			return nil, err
+		}
+
+		webhooks = append(webhooks, &w)
+	}
+
+	return webhooks, nil
+}
+
+func (s *boltStore) GetWebhookByID(id int) (*models.Webhook, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	bytes := tx.Bucket(webhookBucket).Get(itob(id))
+	if bytes == nil {
+		return nil, nil
+	}
+
+	var webhook models.Webhook
+	if err := json.Unmarshal(bytes, &webhook); err != nil {
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+func (s *boltStore) GetWebhookByName(name string) (*models.Webhook, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cursor := tx.Bucket(webhookBucket).Cursor()
+
+	for k, data := cursor.First(); k != nil; k, data = cursor.Next() {
+		var w models.Webhook
+		if err := json.Unmarshal(data, &w); err != nil {
+			return nil, err
+		}
+
+		if w.Name == name {
+			return &w, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *boltStore) CreateWebhook(webhook *models.Webhook) error {
+	tx, err := s.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(webhookBucket)
+
+	seq, _ := bucket.NextSequence()
+	webhook.ID = int(seq)
+
+	if webhook.CreatedAt.IsZero() {
+		webhook.CreatedAt = time.Now()
+	}
+
+	webhook.UpdatedAt = time.Now()
+
+	buf, err := json.Marshal(webhook)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(itob(webhook.ID), buf); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *boltStore) UpdateWebhook(webhook *models.Webhook) error {
+	if webhook.ID <= 0 {
+		return errors.New("invalid webhook id")
+	}
+
+	tx, err := s.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(webhookBucket)
+
+	webhook.UpdatedAt = time.Now()
+
+	buf, err := json.Marshal(webhook)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(itob(webhook.ID), buf); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *boltStore) DeleteWebhook(id int) error {
+	return s.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(webhookBucket).Delete(itob(id))
+	})
+}
+
+func (s *boltStore) CreateWebhookDelivery(delivery *models.WebhookDelivery) error {
+	tx, err := s.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(webhookDeliveryBucket)
+
+	seq, _ := bucket.NextSequence()
+	delivery.ID = int(seq)
+
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+
+	delivery.UpdatedAt = time.Now()
+
+	buf, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(itob(delivery.ID), buf); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *boltStore) UpdateWebhookDelivery(delivery *models.WebhookDelivery) error {
+	if delivery.ID <= 0 {
+		return errors.New("invalid webhook delivery id")
+	}
+
+	tx, err := s.Begin(true)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	bucket := tx.Bucket(webhookDeliveryBucket)
+
+	delivery.UpdatedAt = time.Now()
+
+	buf, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+
+	if err := bucket.Put(itob(delivery.ID), buf); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetPendingWebhookDeliveries retrieves the deliveries still waiting to be attempted, oldest first.
+func (s *boltStore) GetPendingWebhookDeliveries() ([]*models.WebhookDelivery, error) {
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cursor := tx.Bucket(webhookDeliveryBucket).Cursor()
+
+	deliveries := make([]*models.WebhookDelivery, 0)
+	for k, data := cursor.First(); k != nil; k, data = cursor.Next() {
+		var d models.WebhookDelivery
+		if err := json.Unmarshal(data, &d); err != nil {
+			return nil, err
+		}
+
+		if d.Status == models.WebhookDeliveryStatusPending {
+			deliveries = append(deliveries, &d)
+		}
+	}
+
+	return deliveries, nil
+}
+
+// GetWebhookDeliveriesByWebhookID retrieves the latest deliveries for the webhook, newest first.
+func (s *boltStore) GetWebhookDeliveriesByWebhookID(webhookID int, limit int) ([]*models.WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = 25
+	}
+
+	tx, err := s.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	cursor := tx.Bucket(webhookDeliveryBucket).Cursor()
+
+	deliveries := make([]*models.WebhookDelivery, 0, limit)
+	for k, data := cursor.Last(); k != nil && len(deliveries) < limit; k, data = cursor.Prev() {
+		var d models.WebhookDelivery
+		if err := json.Unmarshal(data, &d); err != nil {
+			return nil, err
+		}
+
+		if d.WebhookID == webhookID {
+			deliveries = append(deliveries, &d)
+		}
+	}
+
+	return deliveries, nil
+}
+
+// DeleteWebhookDeliveriesBefore removes the finished deliveries created before the provided time.
+// Pending deliveries are always kept so no event gets lost.
+func (s *boltStore) DeleteWebhookDeliveriesBefore(before time.Time) error {
+	return s.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(webhookDeliveryBucket)
+
+		// Deleting while iterating with a cursor skips items, so collect the keys first
+		toDelete := make([][]byte, 0)
+
+		cursor := bucket.Cursor()
+		for k, data := cursor.First(); k != nil; k, data = cursor.Next() {
+			var d models.WebhookDelivery
+			if err := json.Unmarshal(data, &d); err != nil {
+				return err
+			}
+
+			if d.Status == models.WebhookDeliveryStatusPending || !d.CreatedAt.Before(before) {
+				continue
+			}
+
+			toDelete = append(toDelete, append([]byte{}, k...))
+		}
+
+		for _, k := range toDelete {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
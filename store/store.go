@@ -2,6 +2,7 @@ package store
 
 import (
 	"io"
+	"time"
 
 	"github.com/RocketChat/statuscentral/models"
 )
@@ -23,10 +24,17 @@ type Store interface {
 	GetRegionByCodeAndServiceName(regionCode, serviceName string) (*models.Region, error)
 	DeleteRegion(id int) error
 
+	// Status History
+	CreateStatusChange(change *models.StatusChange) error
+	GetStatusChangesByServiceID(serviceID int) ([]*models.StatusChange, error)
+
 	// Incidents
 	CreateIncident(incident *models.Incident) error
 	UpdateIncident(incident *models.Incident) error
+	UpdateIncidentNotifications(id int, update func(results models.NotificationResults)) error
 	GetIncidents(latest bool, pagination models.Pagination) ([]*models.Incident, error)
+	GetIncidentsSince(since time.Time) ([]*models.Incident, error)
+	GetOpenIncidents() ([]*models.Incident, error)
 	GetIncidentByID(id int) (*models.Incident, error)
 	DeleteIncident(id int) error
 
@@ -34,21 +42,80 @@ type Store interface {
 	CreateIncidentUpdate(incidentID int, update *models.StatusUpdate) error
 	GetIncidentUpdateByID(incidentID int, updateID int) (*models.StatusUpdate, error)
 	GetIncidentUpdatesByIncidentID(incidentID int) ([]*models.StatusUpdate, error)
+	UpdateIncidentUpdate(incidentID int, update *models.StatusUpdate) error
 	DeleteIncidentUpdateByID(incidentID int, updateID int) error
 
+	// Incident Templates
+	CreateIncidentTemplate(incidentTemplate *models.IncidentTemplate) error
+	UpdateIncidentTemplate(incidentTemplate *models.IncidentTemplate) error
+	GetIncidentTemplates() ([]*models.IncidentTemplate, error)
+	GetIncidentTemplateByID(id int) (*models.IncidentTemplate, error)
+	DeleteIncidentTemplate(id int) error
+
 	// Scheduled Maintenance
 	CreateScheduledMaintenance(scheduledMaintenance *models.ScheduledMaintenance) error
 	UpdateScheduledMaintenance(scheduledMaintenance *models.ScheduledMaintenance) error
+	UpdateScheduledMaintenanceNotifications(id int, update func(results models.NotificationResults)) error
 	GetScheduledMaintenance(latest bool) ([]*models.ScheduledMaintenance, error)
 	GetScheduledMaintenanceByID(id int) (*models.ScheduledMaintenance, error)
 	DeleteScheduledMaintenance(id int) error
+	GetDeletedScheduledMaintenance() ([]*models.ScheduledMaintenance, error)
+
+	// Recurring Maintenance
+	CreateRecurringMaintenance(recurringMaintenance *models.RecurringMaintenance) error
+	UpdateRecurringMaintenance(recurringMaintenance *models.RecurringMaintenance) error
+	GetRecurringMaintenances() ([]*models.RecurringMaintenance, error)
+	GetRecurringMaintenanceByID(id int) (*models.RecurringMaintenance, error)
+	DeleteRecurringMaintenance(id int) error
 
 	// Scheduled Maintenance Updates
 	CreateScheduledMaintenanceUpdate(maintenanceID int, update *models.StatusUpdate) error
 	GetScheduledMaintenanceUpdateByID(maintenanceID int, updateID int) (*models.StatusUpdate, error)
 	GetScheduledMaintenanceUpdatesByMaintenanceID(maintenanceID int) ([]*models.StatusUpdate, error)
+	UpdateScheduledMaintenanceUpdate(maintenanceID int, update *models.StatusUpdate) error
 	DeleteScheduledMaintenanceUpdateByID(maintenanceID int, updateID int) error
 
+	// Subscribers
+	CreateSubscriber(subscriber *models.Subscriber) error
+	UpdateSubscriber(subscriber *models.Subscriber) error
+	GetSubscribers() ([]*models.Subscriber, error)
+	GetSubscriberByID(id int) (*models.Subscriber, error)
+	GetSubscriberByEmail(email string) (*models.Subscriber, error)
+	DeleteSubscriber(id int) error
+
+	// Webhooks
+	CreateWebhook(webhook *models.Webhook) error
+	UpdateWebhook(webhook *models.Webhook) error
+	GetWebhooks() ([]*models.Webhook, error)
+	GetWebhookByID(id int) (*models.Webhook, error)
+	GetWebhookByName(name string) (*models.Webhook, error)
+	DeleteWebhook(id int) error
+
+	// Webhook Deliveries
+	CreateWebhookDelivery(delivery *models.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *models.WebhookDelivery) error
+	GetPendingWebhookDeliveries() ([]*models.WebhookDelivery, error)
+	GetWebhookDeliveriesByWebhookID(webhookID int, limit int) ([]*models.WebhookDelivery, error)
+	DeleteWebhookDeliveriesBefore(before time.Time) error
+
+	// API Tokens
+	CreateAPIToken(token *models.APIToken) error
+	UpdateAPIToken(token *models.APIToken) error
+	GetAPITokens() ([]*models.APIToken, error)
+	GetAPITokenByID(id int) (*models.APIToken, error)
+	GetAPITokenByHash(hash string) (*models.APIToken, error)
+
+	// Audit Log
+	CreateAuditEntry(entry *models.AuditEntry) error
+	GetAuditEntryByID(id int) (*models.AuditEntry, error)
+	GetAuditEntries(filter models.AuditFilter) ([]*models.AuditEntry, error)
+
+	// Migrations
+	IsMigrationApplied(name string) (bool, error)
+	SetMigrationApplied(name string) error
+
 	CheckDb() error
 	Snapshot(w io.Writer) error
+	// DBStats gets the statistics of the database, keyed by their name
+	DBStats() map[string]float64
 }